@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"os"
 
 	"github.com/sirupsen/logrus"
@@ -10,6 +11,8 @@ import (
 	"github.com/ekristen/aws-nuke/v3/pkg/common"
 
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/account"
+	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/approval"
+	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/backup"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/completion"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/config"
 	_ "github.com/ekristen/aws-nuke/v3/pkg/commands/list"
@@ -19,6 +22,19 @@ import (
 	_ "github.com/ekristen/aws-nuke/v3/resources"
 )
 
+// The exit codes allow automation to tell the outcome of a run apart, the codes 2 and 3 are only used with
+// --detailed-exit-code, otherwise those outcomes exit with 0:
+//
+//	0    success, e.g. all resources were removed
+//	1    an unexpected error
+//	2    a dry run found resources that would be removed
+//	3    there was nothing to remove
+//	4    the removal of some resources failed
+//	5    validation failed, e.g. the config is invalid, the account is blocklisted or the approval is not valid
+//	6    authentication failed, the account could not be looked up with the credentials
+//	7    the removal window closed before all resources were removed
+//	130  the run was interrupted by SIGINT or SIGTERM
+//	137  the run was forced to exit by a second SIGINT or SIGTERM
 func main() {
 	defer func() {
 		if r := recover(); r != nil {
@@ -45,6 +61,11 @@ func main() {
 	}
 
 	if err := app.Run(context.Background(), os.Args); err != nil {
-		logrus.Fatal(err)
+		var exitErr *common.ExitError
+		if !errors.As(err, &exitErr) {
+			logrus.Fatal(err)
+		}
+
+		os.Exit(exitErr.Code)
 	}
 }
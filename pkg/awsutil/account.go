@@ -10,9 +10,10 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 
-	"github.com/aws/aws-sdk-go/service/ec2" //nolint:staticcheck
-	"github.com/aws/aws-sdk-go/service/iam" //nolint:staticcheck
-	"github.com/aws/aws-sdk-go/service/sts" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/ec2"           //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/iam"           //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/organizations" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/sts"           //nolint:staticcheck
 
 	"github.com/ekristen/aws-nuke/v3/pkg/config"
 )
@@ -156,3 +157,36 @@ func (a *Account) Regions() []string {
 func (a *Account) DisabledRegions() []string {
 	return a.disabledRegions
 }
+
+// OrganizationDetails returns the account name and tags from Organizations. This is only possible when the
+// authenticated principal is allowed to describe the account, typically from the management account or a delegated
+// administrator, so it is only called when the configuration requires it.
+func (a *Account) OrganizationDetails() (string, map[string]string, error) {
+	globalSession, err := a.NewSession(GlobalRegionID, "")
+	if err != nil {
+		return "", nil, errors.Wrapf(err, "failed to create global session in %s", GlobalRegionID)
+	}
+
+	svc := organizations.New(globalSession)
+
+	accountOutput, err := svc.DescribeAccount(&organizations.DescribeAccountInput{
+		AccountId: ptr.String(a.ID()),
+	})
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to describe account")
+	}
+
+	tags := map[string]string{}
+	if err := svc.ListTagsForResourcePages(&organizations.ListTagsForResourceInput{
+		ResourceId: ptr.String(a.ID()),
+	}, func(page *organizations.ListTagsForResourceOutput, _ bool) bool {
+		for _, tag := range page.Tags {
+			tags[ptr.ToString(tag.Key)] = ptr.ToString(tag.Value)
+		}
+		return true
+	}); err != nil {
+		return "", nil, errors.Wrap(err, "failed to list account tags")
+	}
+
+	return ptr.ToString(accountOutput.Account.Name), tags, nil
+}
@@ -0,0 +1,137 @@
+package approval
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+func keygen(_ context.Context, c *cli.Command) error {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+
+	encoded := base64.StdEncoding.EncodeToString(privateKey.Seed())
+	if err := os.WriteFile(c.String("output"), []byte(encoded+"\n"), 0600); err != nil {
+		return err
+	}
+
+	fmt.Printf("private key written to %s, add the public key to the approvers in the config:\n", c.String("output"))
+	fmt.Println(base64.StdEncoding.EncodeToString(publicKey))
+
+	return nil
+}
+
+func sign(_ context.Context, c *cli.Command) error {
+	data, err := os.ReadFile(c.String("key"))
+	if err != nil {
+		return err
+	}
+
+	seed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(seed) != ed25519.SeedSize {
+		return fmt.Errorf("%s is not a base64 encoded ed25519 private key", c.String("key"))
+	}
+
+	approval, err := nuke.ReadApproval(c.String("request"))
+	if err != nil {
+		return err
+	}
+
+	// Note: the resources are printed for the approver to review, but only their hash is signed
+	if err := approval.Request.VerifyHash(); err != nil {
+		return fmt.Errorf("refusing to sign %s: %w", c.String("request"), err)
+	}
+
+	fmt.Printf("account: %s\n", approval.Request.AccountID)
+	fmt.Printf("expires: %s\n", approval.Request.ExpiresAt.Format("2006-01-02 15:04:05 MST"))
+	fmt.Printf("resources: %d\n", len(approval.Request.Resources))
+	for _, resource := range approval.Request.Resources {
+		fmt.Printf("  %s\n", resource)
+	}
+
+	approval.Sign(ed25519.NewKeyFromSeed(seed))
+
+	output := c.String("output")
+	if output == "" {
+		output = c.String("request")
+	}
+
+	if err := approval.Write(output); err != nil {
+		return err
+	}
+
+	fmt.Printf("approval signed and written to %s\n", output)
+
+	return nil
+}
+
+func init() {
+	keygenFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "path to write the private key to",
+			Value:   "aws-nuke-approval.key",
+		},
+	}
+
+	signFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "request",
+			Aliases:  []string{"r"},
+			Usage:    "path to the approval request written by a dry run with --approval-request",
+			Required: true,
+			Action:   common.CheckFilePath,
+		},
+		&cli.StringFlag{
+			Name:     "key",
+			Aliases:  []string{"k"},
+			Usage:    "path to the private key of the approver",
+			Required: true,
+			Action:   common.CheckFilePath,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "write the signed approval to a file instead of updating the request",
+		},
+	}
+
+	cmd := &cli.Command{
+		Name:  "approval",
+		Usage: "commands for approving runs with --no-dry-run",
+		Commands: []*cli.Command{
+			{
+				Name:  "keygen",
+				Usage: "generate an ed25519 key pair for an approver",
+				Description: `keygen writes the private key of a new approver to a file and prints the public key, which is
+added to the approvers of the approval block in the config.`,
+				Flags:  append(keygenFlags, global.Flags()...),
+				Before: global.Before,
+				Action: keygen,
+			},
+			{
+				Name:  "sign",
+				Usage: "sign an approval request",
+				Description: `sign prints the resources of an approval request written by a dry run and adds the signature
+of the approver to it. The signature binds the account, the hash of the resources and the expiry of the request.`,
+				Flags:  append(signFlags, global.Flags()...),
+				Before: global.Before,
+				Action: sign,
+			},
+		},
+	}
+
+	common.RegisterCommand(cmd)
+}
@@ -0,0 +1,79 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+func decrypt(_ context.Context, c *cli.Command) error {
+	passphrase := os.Getenv(c.String("passphrase-env"))
+	if passphrase == "" {
+		return fmt.Errorf("the passphrase must be set in %s", c.String("passphrase-env"))
+	}
+
+	sealed, err := os.ReadFile(c.String("file"))
+	if err != nil {
+		return err
+	}
+
+	data, err := nuke.DecryptExport(sealed, passphrase)
+	if err != nil {
+		return err
+	}
+
+	if output := c.String("output"); output != "" {
+		return os.WriteFile(output, data, 0600)
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
+
+func init() {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:     "file",
+			Aliases:  []string{"f"},
+			Usage:    "path to the encrypted export",
+			Required: true,
+			Action:   common.CheckFilePath,
+		},
+		&cli.StringFlag{
+			Name:  "passphrase-env",
+			Usage: "the environment variable that holds the passphrase the export was encrypted with",
+			Value: config.DefaultBackupPassphraseEnv,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "write the decrypted export to a file instead of stdout",
+		},
+	}
+
+	cmd := &cli.Command{
+		Name:  "backup",
+		Usage: "commands for working with the backups created before resources are removed",
+		Commands: []*cli.Command{
+			{
+				Name:  "decrypt",
+				Usage: "decrypt an export created by backup-before-delete",
+				Description: `decrypt reads an encrypted export of a SSMParameter or SecretsManagerSecret that was created
+before the resource was removed and prints the JSON content, which includes the value, so that it can be restored.`,
+				Flags:  append(flags, global.Flags()...),
+				Before: global.Before,
+				Action: decrypt,
+			},
+		},
+	}
+
+	common.RegisterCommand(cmd)
+}
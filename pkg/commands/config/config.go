@@ -9,6 +9,7 @@ import (
 	"github.com/urfave/cli/v3"
 
 	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/types"
 
@@ -101,6 +102,35 @@ func execute(_ context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
 
 	fmt.Println("")
 
+	// Region scoped filters are resolved on top of the account filters and presets, show the effective total per
+	// region so that it's clear which regions are treated differently.
+	fmt.Println("Effective Filters by Region:")
+	for _, regionName := range explainRegions(parsedConfig, accountID) {
+		regionSources, err := parsedConfig.RegionFilterSources(accountID, regionName)
+		if err != nil {
+			return err
+		}
+
+		regionFilters := regionSources.Filters()
+
+		scoped := ""
+		if parsedConfig.GetRegion(accountID, regionName) != nil {
+			scoped = " (region scoped)"
+		}
+
+		fmt.Printf("  %-16s %d%s\n", regionName+":", countFilters(regionFilters), scoped)
+
+		if c.Bool("with-filtered") {
+			for _, resource := range sortedKeys(regionFilters) {
+				fmt.Printf("    %s (%d)\n", resource, len(regionFilters[resource]))
+				for _, source := range regionSources[resource] {
+					fmt.Printf("      - %s\n", source.String())
+				}
+			}
+		}
+	}
+	fmt.Println("")
+
 	if c.Bool("with-filtered") {
 		fmt.Println("Resources with Filters Defined:")
 		for _, resource := range resourcesWithFilters {
@@ -140,6 +170,44 @@ func execute(_ context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
 	return nil
 }
 
+// explainRegions returns the regions from the configuration along with any region that has region scoped
+// configuration for the account, sorted and de-duplicated.
+func explainRegions(parsedConfig *config.Config, accountID string) []string {
+	regions := slices.Clone(parsedConfig.Regions)
+
+	if account, ok := parsedConfig.AccountExtensions[accountID]; ok && account != nil {
+		for regionName := range account.Regions {
+			regions = append(regions, regionName)
+		}
+	}
+
+	slices.Sort(regions)
+
+	return slices.Compact(regions)
+}
+
+// countFilters returns the total number of filters across all resource types
+func countFilters(filters filter.Filters) int {
+	total := 0
+	for _, resourceFilters := range filters {
+		total += len(resourceFilters)
+	}
+
+	return total
+}
+
+// sortedKeys returns the resource types of the filters in sorted order for stable output
+func sortedKeys(filters filter.Filters) []string {
+	keys := make([]string, 0, len(filters))
+	for key := range filters {
+		keys = append(keys, key)
+	}
+
+	slices.Sort(keys)
+
+	return keys
+}
+
 func init() {
 	flags := []cli.Flag{
 		&cli.StringFlag{
@@ -207,17 +275,39 @@ func init() {
 		},
 	}
 
-	cmd := &cli.Command{
-		Name:  "explain-config",
-		Usage: "explain the configuration file and the resources that will be nuked for an account",
-		Description: `explain the configuration file and the resources that will be nuked for an account that
+	explainUsage := "explain the configuration file and the resources that will be nuked for an account"
+	explainDescription := `explain the configuration file and the resources that will be nuked for an account that
 is defined within the configuration. You may either specific an account using the --account-id flag or
 leave it empty to use the default account that can be authenticated against. You can optionally list out included,
-excluded and resources with filters with their respective with flags.`,
-		Flags:  append(flags, global.Flags()...),
-		Before: global.Before,
-		Action: execute,
+excluded and resources with filters with their respective with flags.`
+
+	cmd := &cli.Command{
+		Name:        "explain-config",
+		Usage:       explainUsage,
+		Description: explainDescription,
+		Flags:       append(flags, global.Flags()...),
+		Before:      global.Before,
+		Action:      execute,
+	}
+
+	configCmd := &cli.Command{
+		Name:  "config",
+		Usage: "commands for working with the configuration file",
+		Commands: []*cli.Command{
+			{
+				Name:        "explain",
+				Usage:       explainUsage,
+				Description: explainDescription,
+				Flags:       append(flags, global.Flags()...),
+				Before:      global.Before,
+				Action:      execute,
+			},
+			newMigrateCommand(),
+			newExplainResourceCommand(),
+			newLintCommand(),
+		},
 	}
 
 	common.RegisterCommand(cmd)
+	common.RegisterCommand(configCmd)
 }
@@ -0,0 +1,159 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+func explainResource(_ context.Context, c *cli.Command) error {
+	parsedConfig, err := config.New(libconfig.Options{
+		Path:         c.String("config"),
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+	})
+	if err != nil {
+		logrus.Errorf("Failed to parse config file %s", c.String("config"))
+		return err
+	}
+
+	accountID := c.String("account-id")
+	if accountID == "" {
+		if len(parsedConfig.Accounts) != 1 {
+			return fmt.Errorf("--account-id is required when the config file defines more than one account")
+		}
+
+		for id := range parsedConfig.Accounts {
+			accountID = id
+		}
+	}
+
+	resourceType := c.String("type")
+	if replacement, ok := parsedConfig.Deprecations[resourceType]; ok {
+		logrus.Warnf("resource type %s is deprecated, using %s", resourceType, replacement)
+		resourceType = replacement
+	}
+
+	if registry.GetRegistration(resourceType) == nil {
+		return fmt.Errorf("resource type %s is not a known resource type", resourceType)
+	}
+
+	props, err := config.ParseProperties(c.StringSlice("properties"))
+	if err != nil {
+		return err
+	}
+
+	sources, err := parsedConfig.RegionFilterSources(accountID, c.String("region"))
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Resource Type: %s\n", resourceType)
+	fmt.Printf("Account ID:    %s\n", accountID)
+	if c.String("region") != "" {
+		fmt.Printf("Region:        %s\n", c.String("region"))
+	}
+	fmt.Println("")
+
+	evaluations := sources.Evaluate(resourceType, props)
+	if len(evaluations) == 0 {
+		fmt.Println("No filters are defined for the resource type.")
+		fmt.Println("")
+		fmt.Println("Result: the resource would be removed")
+		return nil
+	}
+
+	var matched *config.FilterEvaluation
+
+	fmt.Println("Filters Evaluated:")
+	for i := range evaluations {
+		evaluation := &evaluations[i]
+
+		status := "no match"
+		switch {
+		case evaluation.Error != nil:
+			status = fmt.Sprintf("error: %s", evaluation.Error)
+		case evaluation.Matched:
+			status = "match"
+			if matched == nil {
+				matched = evaluation
+			}
+		}
+
+		invert := ""
+		if evaluation.Source.Invert {
+			invert = " (inverted)"
+		}
+
+		filterType := string(evaluation.Source.Type)
+		if filterType == "" {
+			filterType = string(filter.Exact)
+		}
+
+		fmt.Printf("  - %s\n", evaluation.Source.String())
+		fmt.Printf("      %s %s %q%s, got %q: %s\n", evaluation.Property, filterType,
+			evaluation.Source.Value, invert, evaluation.Value, status)
+	}
+	fmt.Println("")
+
+	if matched == nil {
+		fmt.Println("Result: the resource would be removed")
+		return nil
+	}
+
+	fmt.Printf("Result: filtered by config: %s\n", matched.Source.String())
+
+	return nil
+}
+
+func newExplainResourceCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "path to config file",
+			Value:   "config.yaml",
+			Action:  common.CheckFilePath,
+		},
+		&cli.StringFlag{
+			Name:  "account-id",
+			Usage: "the account id to evaluate the filters of, it may be omitted if only one account is configured",
+		},
+		&cli.StringFlag{
+			Name:  "region",
+			Usage: "the region the resource is in, region scoped filters are only evaluated if it is set",
+		},
+		&cli.StringFlag{
+			Name:     "type",
+			Aliases:  []string{"t"},
+			Usage:    "the resource type to evaluate",
+			Required: true,
+		},
+		&cli.StringSliceFlag{
+			Name:    "properties",
+			Aliases: []string{"p"},
+			Usage:   "the properties of the resource as key=value, may be specified multiple times",
+		},
+	}
+
+	return &cli.Command{
+		Name:  "explain-resource",
+		Usage: "explain which filters would match a resource with the given properties",
+		Description: `explain-resource evaluates every filter that applies to the resource type against the
+given properties, without connecting to AWS. Each filter is listed with where it was defined in the configuration
+and whether it matched. Filters without a property are evaluated against the Name property, since the legacy
+string of a resource is not available offline.`,
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: explainResource,
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+func lint(_ context.Context, c *cli.Command) error {
+	if c.Bool("list-rules") {
+		for _, rule := range config.LintRules {
+			fmt.Printf("%-30s %-8s %s\n", rule.ID, rule.Severity, rule.Description)
+		}
+
+		return nil
+	}
+
+	failOn := config.LintSeverity(c.String("fail-on"))
+	if failOn != "none" && failOn.Level() < 0 {
+		return fmt.Errorf("invalid --fail-on value %s, must be one of error, warning, info or none", failOn)
+	}
+
+	parsedConfig, err := config.New(libconfig.Options{
+		Path:         c.String("config"),
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+	})
+	if err != nil {
+		logrus.Errorf("Failed to parse config file %s", c.String("config"))
+		return err
+	}
+
+	failed := 0
+	issues := parsedConfig.Lint()
+	for i := range issues {
+		issue := &issues[i]
+
+		entry := logrus.WithFields(logrus.Fields{
+			"rule":     issue.Rule,
+			"severity": issue.Severity,
+			"line":     issue.Line,
+		})
+
+		switch issue.Severity {
+		case config.LintSeverityError:
+			entry.Error(issue.Message)
+		case config.LintSeverityWarning:
+			entry.Warn(issue.Message)
+		default:
+			entry.Info(issue.Message)
+		}
+
+		if failOn != "none" && issue.Severity.Level() >= failOn.Level() {
+			failed++
+		}
+	}
+
+	if len(issues) == 0 {
+		logrus.Info("no issues found")
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d lint issue(s) at or above severity %s", failed, failOn)
+	}
+
+	return nil
+}
+
+func newLintCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "path to config file",
+			Value:   "config.yaml",
+			Action:  common.CheckFilePath,
+		},
+		&cli.StringFlag{
+			Name:  "fail-on",
+			Usage: "the minimum severity that results in a non-zero exit code, one of error, warning, info or none",
+			Value: string(config.LintSeverityError),
+		},
+		&cli.BoolFlag{
+			Name:  "list-rules",
+			Usage: "list the available rules along with their severity and exit",
+		},
+	}
+
+	return &cli.Command{
+		Name:  "lint",
+		Usage: "check the configuration file for dangerous or ineffective patterns",
+		Description: `lint checks a valid configuration file for patterns that are likely to be mistakes, such as
+accounts without any filters or filters that match any value. Every issue has a rule id and a severity. A rule can
+be suppressed for a single line with a "# nuke-lint: disable=<rule>" comment on the line or the line above it, or
+for the whole file with a "# nuke-lint: disable-file=<rule>" comment.`,
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: lint,
+	}
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/docs"
+	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+func migrate(_ context.Context, c *cli.Command) error {
+	raw, err := os.ReadFile(c.String("config"))
+	if err != nil {
+		return err
+	}
+
+	migrated, report, err := config.Migrate(raw, config.MigrateOptions{
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+		Properties: func(resourceType string) map[string]string {
+			reg := registry.GetRegistration(resourceType)
+			if reg == nil {
+				return nil
+			}
+
+			return docs.GeneratePropertiesMap(reg.Resource)
+		},
+	})
+	if err != nil {
+		logrus.Errorf("Failed to migrate config file %s", c.String("config"))
+		return err
+	}
+
+	for _, change := range report.Changes {
+		logrus.Info(change)
+	}
+
+	for _, warning := range report.Warnings {
+		logrus.Warn(warning)
+	}
+
+	if len(report.Changes) == 0 {
+		logrus.Info("no changes required, the configuration is already up to date")
+	}
+
+	output := c.String("output")
+	if c.Bool("in-place") {
+		output = c.String("config")
+	}
+
+	if output == "" {
+		_, err = fmt.Fprint(os.Stdout, string(migrated))
+		return err
+	}
+
+	return os.WriteFile(output, migrated, 0600)
+}
+
+func newMigrateCommand() *cli.Command {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "config",
+			Aliases: []string{"c"},
+			Usage:   "path to the legacy config file",
+			Value:   "config.yaml",
+			Action:  common.CheckFilePath,
+		},
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "path to write the migrated config file to, if empty it is written to stdout",
+		},
+		&cli.BoolFlag{
+			Name:  "in-place",
+			Usage: "overwrite the config file with the migrated config",
+		},
+	}
+
+	return &cli.Command{
+		Name:  "migrate",
+		Usage: "migrate a legacy rebuy-de/aws-nuke v2 configuration file to the current format",
+		Description: `migrate rewrites a legacy configuration file into the current format. It renames
+account-blacklist to blocklist, targets to includes, replaces deprecated resource type names and converts
+feature-flags into settings. Filters referencing properties that are not known for a resource type are reported
+as warnings, they are not changed. Comments are preserved where possible.`,
+		Flags:  append(flags, global.Flags()...),
+		Before: global.Before,
+		Action: migrate,
+	}
+}
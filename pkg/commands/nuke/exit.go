@@ -0,0 +1,117 @@
+package nuke
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// errAuth is returned when the credentials can not be used to look up the account
+var errAuth = errors.New("authentication failed")
+
+// The outcomes of a run, they are part of the summary written with --json
+const (
+	outcomeRemoved          = "removed"
+	outcomeNothingToDo      = "nothing-to-do"
+	outcomeResourcesFound   = "resources-found"
+	outcomeRemovalFailed    = "removal-failed"
+	outcomeValidationFailed = "validation-failed"
+	outcomeAuthFailed       = "auth-failed"
+	outcomeWindowClosed     = "window-closed"
+	outcomeInterrupted      = "interrupted"
+	outcomeError            = "error"
+)
+
+// summary is the single line JSON summary written to stderr at the end of a run with --json
+type summary struct {
+	Outcome  string         `json:"outcome"`
+	ExitCode int            `json:"exit_code"`
+	DryRun   bool           `json:"dry_run"`
+	Summary  map[string]int `json:"summary,omitempty"`
+	Error    string         `json:"error,omitempty"`
+}
+
+// classify returns the outcome and the exit code of a run. The report is nil if the run was not started, any error
+// before that is a validation error, unless it is an authentication error.
+func classify(err error, report *nuke.Report, started bool) (string, int) {
+	switch {
+	case errors.Is(err, nuke.ErrInterrupted):
+		return outcomeInterrupted, common.ExitCodeInterrupted
+	case errors.Is(err, nuke.ErrRemovalFailed):
+		return outcomeRemovalFailed, common.ExitCodeRemovalFailed
+	case errors.Is(err, nuke.ErrRemovalWindowClosed):
+		return outcomeWindowClosed, common.ExitCodeWindowClosed
+	case errors.Is(err, errAuth):
+		return outcomeAuthFailed, common.ExitCodeAuth
+	case errors.Is(err, nuke.ErrValidation), errors.Is(err, nuke.ErrApprovalRequired),
+		errors.Is(err, nuke.ErrApprovalInvalid), err != nil && !started:
+		return outcomeValidationFailed, common.ExitCodeValidation
+	case err != nil:
+		return outcomeError, common.ExitCodeError
+	case report == nil:
+		return outcomeNothingToDo, common.ExitCodeNothingToDo
+	case report.DryRun && report.Summary[queue.ItemStateNew.String()]+
+		report.Summary[queue.ItemStateNewDependency.String()] > 0:
+		return outcomeResourcesFound, common.ExitCodeResourcesFound
+	case !report.DryRun && report.Summary[queue.ItemStateFinished.String()] > 0:
+		return outcomeRemoved, common.ExitCodeSuccess
+	default:
+		return outcomeNothingToDo, common.ExitCodeNothingToDo
+	}
+}
+
+// finish logs the error of the run and returns it with the exit code of its outcome, with --json the summary is
+// written as the last output. Outcomes that are not a failure only exit with their own code with --detailed-exit-code.
+func finish(c *cli.Command, err error, report *nuke.Report, started bool) error {
+	outcome, code := classify(err, report, started)
+	if err == nil && !c.Bool("detailed-exit-code") {
+		code = common.ExitCodeSuccess
+	}
+
+	if err != nil {
+		logrus.Error(err)
+	}
+
+	if c.Bool("json") {
+		writeSummary(outcome, code, !c.Bool("no-dry-run"), err, report)
+	}
+
+	if code == common.ExitCodeSuccess {
+		return nil
+	}
+
+	return &common.ExitError{Code: code, Err: err}
+}
+
+// writeSummary writes the single line JSON summary of the run to stderr
+func writeSummary(outcome string, code int, dryRun bool, err error, report *nuke.Report) {
+	s := summary{
+		Outcome:  outcome,
+		ExitCode: code,
+		DryRun:   dryRun,
+	}
+
+	if report != nil {
+		s.Summary = report.Summary
+	}
+
+	if err != nil {
+		s.Error = err.Error()
+	}
+
+	data, marshalErr := json.Marshal(s)
+	if marshalErr != nil {
+		return
+	}
+
+	fmt.Fprintln(os.Stderr, string(data))
+}
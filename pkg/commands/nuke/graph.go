@@ -0,0 +1,90 @@
+package nuke
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+func executeGraph(ctx context.Context, c *cli.Command) error {
+	format := c.String("format")
+	if format != "dot" && format != "json" {
+		return fmt.Errorf("unsupported graph format %q, must be dot or json", format)
+	}
+
+	s, err := newScanSetup(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.registerScanners(c, nil); err != nil {
+		return err
+	}
+
+	if err := s.nuke.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.nuke.Scan(ctx); err != nil {
+		return err
+	}
+
+	graph := nuke.BuildGraph(s.nuke.Queue.GetItems(), nuke.DependencyRules)
+
+	output := c.String("output")
+	file, err := os.OpenFile(output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if format == "json" {
+		err = graph.WriteJSON(file)
+	} else {
+		err = graph.WriteDOT(file)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.logger.Infof("Dependency graph of %d resources written to %s", len(graph.Order), output)
+
+	if len(graph.Cycles) > 0 {
+		s.logger.Warnf("the dependencies of %d resources form a cycle, the dependencies between them are ignored",
+			len(graph.Cycles))
+	}
+
+	return nil
+}
+
+func init() {
+	flags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "path to write the dependency graph to",
+			Value:   "aws-nuke-graph.dot",
+		},
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "format of the dependency graph, either dot or json",
+			Value: "dot",
+		},
+	}
+
+	cmd := &cli.Command{
+		Name:   "graph",
+		Usage:  "scan an aws account and export the dependency graph of the resources that would be removed",
+		Flags:  append(append(flags, scanFlags()...), global.Flags()...),
+		Before: global.Before,
+		Action: executeGraph,
+	}
+
+	common.RegisterCommand(cmd)
+}
@@ -0,0 +1,115 @@
+package nuke
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+func executeInventory(ctx context.Context, c *cli.Command) error {
+	s, err := newScanSetup(c)
+	if err != nil {
+		return err
+	}
+
+	if err := s.registerScanners(c, nil); err != nil {
+		return err
+	}
+
+	if err := s.nuke.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.nuke.Scan(ctx); err != nil {
+		return err
+	}
+
+	output := c.String("output")
+	snapshot := nuke.NewSnapshot(s.account.ID(), s.nuke.Queue.GetItems())
+	if err := snapshot.Write(output); err != nil {
+		return err
+	}
+
+	s.logger.Infof("Inventory of %d resources written to %s", len(snapshot.Resources), output)
+
+	return nil
+}
+
+func executeDiff(_ context.Context, c *cli.Command) error {
+	if c.Args().Len() != 2 {
+		return fmt.Errorf("diff requires exactly two snapshots, the old and the new one")
+	}
+
+	before, err := nuke.ReadSnapshot(c.Args().Get(0))
+	if err != nil {
+		return err
+	}
+
+	after, err := nuke.ReadSnapshot(c.Args().Get(1))
+	if err != nil {
+		return err
+	}
+
+	if before.AccountID != after.AccountID {
+		return fmt.Errorf("the snapshots are of different accounts, %s and %s", before.AccountID, after.AccountID)
+	}
+
+	diff := nuke.DiffSnapshots(before, after)
+
+	switch c.String("format") {
+	case "text":
+		return diff.Print(os.Stdout)
+	case "json":
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(diff)
+	default:
+		return fmt.Errorf("unsupported diff format %q, must be text or json", c.String("format"))
+	}
+}
+
+func init() {
+	inventoryFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:    "output",
+			Aliases: []string{"o"},
+			Usage:   "path to write the snapshot to",
+			Value:   "aws-nuke-inventory.json",
+		},
+	}
+
+	diffFlags := []cli.Flag{
+		&cli.StringFlag{
+			Name:  "format",
+			Usage: "format of the difference, either text or json",
+			Value: "text",
+		},
+	}
+
+	inventoryCmd := &cli.Command{
+		Name:   "inventory",
+		Usage:  "scan an aws account without removing anything and write a snapshot of the resources",
+		Flags:  append(append(inventoryFlags, scanFlags()...), global.Flags()...),
+		Before: global.Before,
+		Action: executeInventory,
+	}
+
+	diffCmd := &cli.Command{
+		Name:      "diff",
+		Usage:     "compare two inventory snapshots and show the added, removed and changed resources",
+		ArgsUsage: "<old snapshot> <new snapshot>",
+		Flags:     append(diffFlags, global.Flags()...),
+		Before:    global.Before,
+		Action:    executeDiff,
+	}
+
+	common.RegisterCommand(inventoryCmd)
+	common.RegisterCommand(diffCmd)
+}
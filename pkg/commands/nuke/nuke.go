@@ -4,29 +4,18 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"slices"
-	"strings"
+	"sync"
 	"time"
 
-	"github.com/gotidy/ptr"
-	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 
-	"github.com/aws/aws-sdk-go/aws/endpoints" //nolint:staticcheck
-
-	libconfig "github.com/ekristen/libnuke/pkg/config"
-	libnuke "github.com/ekristen/libnuke/pkg/nuke"
-	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/scanner"
-	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/awsutil"
 	"github.com/ekristen/aws-nuke/v3/pkg/commands/global"
 	"github.com/ekristen/aws-nuke/v3/pkg/common"
 	"github.com/ekristen/aws-nuke/v3/pkg/config"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
-
-	"github.com/ekristen/aws-nuke/v3/resources"
 )
 
 // ConfigureCreds is a helper function to configure the awsutil.Credentials object from the cli.Context
@@ -44,211 +33,220 @@ func ConfigureCreds(c *cli.Command) (creds *awsutil.Credentials) {
 	return creds
 }
 
-func execute(baseCtx context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
+func execute(baseCtx context.Context, c *cli.Command) (err error) { //nolint:funlen,gocyclo
 	ctx, cancel := context.WithCancel(baseCtx)
 	defer cancel()
 
-	defaultRegion := c.String("default-region")
-	creds := ConfigureCreds(c)
+	// Map the outcome of the run to its exit code, any error before the run is started is a validation error
+	var report *nuke.Report
+	started := false
+	defer func() {
+		err = finish(c, err, report, started)
+	}()
 
-	if err := creds.Validate(); err != nil {
+	s, err := newScanSetup(c)
+	if err != nil {
 		return err
 	}
 
-	// Create the parameters object that will be used to configure the nuke process.
-	params := &libnuke.Parameters{
-		Force:          c.Bool("force"),
-		ForceSleep:     c.Int("force-sleep"),
-		Quiet:          c.Bool("quiet"),
-		NoDryRun:       c.Bool("no-dry-run"),
-		Includes:       c.StringSlice("include"),
-		Excludes:       c.StringSlice("exclude"),
-		Alternatives:   c.StringSlice("cloud-control"),
-		MaxWaitRetries: c.Int("max-wait-retries"),
-	}
+	n, params, parsedConfig, account, logger := s.nuke, s.params, s.config, s.account, s.logger
+	n.SetRunSleep(c.Duration("run-sleep-delay"))
 
-	if len(c.StringSlice("feature-flag")) > 0 {
-		if slices.Contains(c.StringSlice("feature-flag"), "wait-on-dependencies") {
-			params.WaitOnDependencies = true
-		}
+	// Register the maintenance window check, it only applies when resources are actually removed. The run is refused
+	// outside the window, and optionally no new removals are triggered once the window closes during the run.
+	if schedule := parsedConfig.GetSchedule(account.ID()); schedule != nil {
+		n.RegisterValidateHandler(func() error {
+			if !params.NoDryRun {
+				return nil
+			}
+
+			if err := schedule.Validate(); err != nil {
+				return err
+			}
+
+			now := time.Now()
+			if err := schedule.Check(now); err != nil {
+				return err
+			}
+
+			if end, ok := schedule.WindowEnd(now); ok {
+				logger.Infof("within the maintenance window, it closes at %s", end.Format(time.RFC3339))
+			}
+
+			return nil
+		})
 
-		if slices.Contains(c.StringSlice("feature-flag"), "filter-groups") {
-			params.UseFilterGroups = true
+		if schedule.StopAtWindowEnd {
+			n.RegisterRemovalWindow(schedule.Check)
 		}
 	}
 
-	logger := logrus.StandardLogger()
-	logger.SetOutput(os.Stdout)
+	// Configure the backups that are created before stateful resources are removed, a passphrase is only required for
+	// the encrypted exports when resources are actually removed.
+	var backup *nuke.BackupOptions
+	if parsedConfig.BackupBeforeDelete != nil {
+		backupConfig := parsedConfig.BackupBeforeDelete
+		backup = &nuke.BackupOptions{
+			ResourceTypes:   backupConfig.GetResourceTypes(),
+			ExportDirectory: backupConfig.GetExportDirectory(),
+			Passphrase:      backupConfig.GetPassphrase(),
+		}
 
-	// Parse the user supplied configuration file to pass in part to configure the nuke process.
-	parsedConfig, err := config.New(libconfig.Options{
-		Path:         c.String("config"),
-		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
-		Log:          logger.WithField("component", "config"),
-	})
-	if err != nil {
-		logger.Errorf("Failed to parse config file %s", c.String("config"))
-		return err
+		n.RegisterValidateHandler(func() error {
+			if !params.NoDryRun {
+				return nil
+			}
+
+			return backupConfig.Validate()
+		})
 	}
 
-	// Set the default region for the AWS SDK to use.
-	if defaultRegion != "" {
-		awsutil.DefaultRegionID = defaultRegion
-
-		partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), defaultRegion)
-		if !ok {
-			if parsedConfig.CustomEndpoints.GetRegion(defaultRegion) == nil {
-				err = fmt.Errorf(
-					"the custom region '%s' must be specified in the configuration 'endpoints'"+
-						" to determine its partition", defaultRegion)
-				logger.WithError(err).Errorf("unable to resolve partition for region: %s", defaultRegion)
-				return err
-			}
+	// Register the export of IAM and resource policies, they are written before the first resource is removed
+	if parsedConfig.PolicyExport != nil {
+		if err := parsedConfig.PolicyExport.Validate(); err != nil {
+			return err
 		}
 
-		awsutil.DefaultAWSPartitionID = partition.ID()
+		n.RegisterPolicyExport(&nuke.PolicyExportOptions{
+			ResourceTypes: parsedConfig.PolicyExport.ResourceTypes,
+			Directory:     parsedConfig.PolicyExport.Directory,
+		})
 	}
 
-	// Create the AWS Account object. This will be used to get the account ID and aliases for the account.
-	account, err := awsutil.NewAccount(creds, parsedConfig.CustomEndpoints)
-	if err != nil {
-		return err
-	}
+	// Register the timeouts of the removal, resources that time out are reported and not retried
+	n.RegisterRunTimeout(c.Duration("run-timeout"))
+	if parsedConfig.RemoveTimeout != nil {
+		defaultTimeout, timeouts, err := parsedConfig.RemoveTimeout.Timeouts()
+		if err != nil {
+			return err
+		}
 
-	// Get the filters for the account that is being connected to via the AWS SDK.
-	filters, err := parsedConfig.Filters(account.ID())
-	if err != nil {
-		return err
-	}
+		n.RegisterRemoveTimeout(func(resourceType string) time.Duration {
+			if timeout, ok := timeouts[resourceType]; ok {
+				return timeout
+			}
 
-	// Instantiate libnuke
-	n := libnuke.New(params, filters, parsedConfig.Settings)
+			return defaultTimeout
+		})
+	}
 
-	n.SetRunSleep(c.Duration("run-sleep-delay"))
-	n.SetLogger(logger.WithField("component", "libnuke"))
-	n.RegisterVersion(common.AppVersion.String())
+	// The interactive review of the resources found by the scan, it requires a user to be present
+	var review nuke.Review
+	if c.Bool("review") {
+		if params.Force {
+			return fmt.Errorf("--review can not be used with --no-prompt")
+		}
 
-	// Register our custom validate handler that validates the account and AWS nuke unique alias checks
-	n.RegisterValidateHandler(func() error {
-		return parsedConfig.ValidateAccount(account.ID(), account.Aliases(), c.Bool("no-alias-check"))
-	})
+		reviewer := &nuke.Reviewer{In: os.Stdin, Out: os.Stdout, AccountID: account.ID()}
+		review = reviewer.Review
+	}
 
 	// Register our custom prompt handler that shows the account information
 	p := &nuke.Prompt{Parameters: params, Account: account, Logger: logger}
-	n.RegisterPrompt(p.Prompt)
-
-	// Get any specific account level configuration
-	accountConfig := parsedConfig.Accounts[account.ID()]
-
-	// Get current registered resource names
-	resourceNames := registry.GetNames()
-
-	// Combine all the places where alternative resource types can be defined and then dynamically
-	// register them as a Cloud Control resource type.
-	altResourceTypes := types.Collection(registry.ExpandNames(n.Parameters.Alternatives))
-	altResourceTypes = altResourceTypes.Union(parsedConfig.ResourceTypes.GetAlternatives())
-	altResourceTypes = altResourceTypes.Union(accountConfig.ResourceTypes.GetAlternatives())
-	for _, rt := range altResourceTypes {
-		if slices.Contains(resourceNames, rt) {
-			continue
+	prompt := p.Prompt
+
+	// Put the approval in front of the prompt, a run with --no-dry-run is refused unless it has been approved
+	if parsedConfig.Approval != nil {
+		gate, err := approvalGate(c, n, parsedConfig.Approval, account.ID())
+		if err != nil {
+			return err
 		}
 
-		resources.RegisterCloudControl(rt)
-	}
+		prompt = gate.Wrap(prompt)
 
-	// Resolve the resource types to be used for the nuke process based on the parameters, global configuration, and
-	// account level configuration.
-	resourceTypes := types.ResolveResourceTypes(
-		registry.GetNames(), // note: we want to re-pull the registry here due to the dynamic registration above
-		[]types.Collection{
-			registry.ExpandNames(n.Parameters.Includes),
-			parsedConfig.ResourceTypes.GetIncludes(),
-			accountConfig.ResourceTypes.GetIncludes(),
-		},
-		[]types.Collection{
-			registry.ExpandNames(n.Parameters.Excludes),
-			parsedConfig.ResourceTypes.Excludes,
-			accountConfig.ResourceTypes.Excludes,
-		},
-		[]types.Collection{
-			registry.ExpandNames(n.Parameters.Alternatives),
-			parsedConfig.ResourceTypes.GetAlternatives(),
-			accountConfig.ResourceTypes.GetAlternatives(),
-		},
-		registry.GetAlternativeResourceTypeMapping(),
-	)
-
-	// If the user has specified the "all" region, then we need to get the enabled regions for the account
-	// and use those. Otherwise, we will use the regions that are specified in the configuration.
-	if slices.Contains(parsedConfig.Regions, "all") {
-		parsedConfig.Regions = account.Regions()
-
-		logger.Info(
-			`"all" detected in region list, only enabled regions and "global" will be used, all others ignored`)
-
-		if len(parsedConfig.Regions) > 1 {
-			logger.Warnf(`additional regions defined along with "all", these will be ignored!`)
+		// Note: the approval is for the resources found by the scan, it is verified before the review excludes any
+		if review != nil {
+			review = gate.WrapReview(review)
 		}
+	} else if c.String("approval") != "" {
+		return fmt.Errorf("--approval requires the approval block in the config")
+	}
 
-		logger.Infof("The following regions are enabled for the account (%d total):", len(parsedConfig.Regions))
+	if review != nil {
+		n.RegisterReview(review)
+	}
 
-		printableRegions := make([]string, 0)
-		for i, region := range parsedConfig.Regions {
-			printableRegions = append(printableRegions, region)
-			if i%6 == 0 { // print 5 regions per line
-				logger.Infof("> %s", strings.Join(printableRegions, ", "))
-				printableRegions = make([]string, 0)
-			} else if i == len(parsedConfig.Regions)-1 {
-				logger.Infof("> %s", strings.Join(printableRegions, ", "))
-			}
-		}
+	n.RegisterPrompt(prompt)
+
+	if err := s.registerScanners(c, backup); err != nil {
+		return err
 	}
 
-	// Register the scanners for each region that is defined in the configuration.
-	for _, regionName := range parsedConfig.Regions {
-		// Step 1 - Create the region object
-		region := nuke.NewRegion(regionName, account.ResourceTypeToServiceType, account.NewSession, account.NewConfig)
-
-		// Step 2 - Create the scannerActual object
-		scannerActual, scannerActualErr := scanner.New(&scanner.Config{
-			Owner:         regionName,
-			ResourceTypes: resourceTypes,
-			Opts: &nuke.ListerOpts{
-				Region:    region,
-				AccountID: ptr.String(account.ID()),
-				Logger: logger.WithFields(logrus.Fields{
-					"component": "scanner",
-					"region":    regionName,
-				}),
-			},
-			Logger:          logger,
-			ParallelQueries: c.Int64("parallel-queries"),
-			QueueSize:       c.Int("max-queue-size"),
+	// Write the report regardless of the outcome of the run, so that it's clear what was, and what was not, removed.
+	// It is written at most once, a forced exit writes it while the run is still in progress.
+	var reportOnce sync.Once
+	writeReport := func(report *nuke.Report) {
+		reportOnce.Do(func() {
+			reportPath := c.String("report")
+			if reportPath == "" {
+				return
+			}
+
+			if err := report.Write(reportPath); err != nil {
+				logger.WithError(err).Errorf("unable to write report to %s", reportPath)
+			}
 		})
-		if scannerActualErr != nil {
-			return scannerActualErr
-		}
+	}
+
+	stopSignals := handleSignals(n, cancel, c.Duration("grace-period"), logger, func() {
+		forcedErr := fmt.Errorf("%w: forced exit", nuke.ErrInterrupted)
+		forcedReport := n.Report(forcedErr)
+		writeReport(forcedReport)
 
-		// Step 3 - Register a mutate function that will be called to modify the lister options for each resource type
-		// see pkg/nuke/resource.go for the MutateOpts function. Its purpose is to create the proper session for the
-		// proper region.
-		regMutateErr := scannerActual.RegisterMutateOptsFunc(nuke.MutateOpts)
-		if regMutateErr != nil {
-			return regMutateErr
+		if c.Bool("json") {
+			writeSummary(outcomeInterrupted, common.ExitCodeForced, forcedReport.DryRun, forcedErr, forcedReport)
 		}
+	})
+	defer stopSignals()
+
+	started = true
+	runErr := n.Run(ctx)
+	report = n.Report(runErr)
+	writeReport(report)
+
+	// Write the approval request of a successful dry run, it is signed by the approvers with `approval sign`
+	if requestPath := c.String("approval-request"); requestPath != "" && runErr == nil && !params.NoDryRun {
+		approval := nuke.NewApproval(account.ID(), n.Queue.GetItems(), c.Duration("approval-ttl"))
+		if err := approval.Write(requestPath); err != nil {
+			return err
+		}
+
+		logger.Infof("approval request for %d resources written to %s", len(approval.Request.Resources), requestPath)
+	}
+
+	return runErr
+}
+
+func approvalGate(c *cli.Command, n *nuke.Nuke, approvalConfig *config.Approval, accountID string) (*nuke.ApprovalGate, error) {
+	if err := approvalConfig.Validate(); err != nil {
+		return nil, err
+	}
+
+	trusted, err := approvalConfig.GetPublicKeys()
+	if err != nil {
+		return nil, err
+	}
 
-		// Step 4 - Register the scannerActual with the nuke object
-		regScanErr := n.RegisterScanner(nuke.Account, scannerActual)
-		if regScanErr != nil {
-			return regScanErr
+	gate := &nuke.ApprovalGate{
+		Nuke:         n,
+		AccountID:    accountID,
+		Trusted:      trusted,
+		MinApprovals: approvalConfig.GetMinApprovals(),
+	}
+
+	if approvalPath := c.String("approval"); approvalPath != "" {
+		gate.Approval, err = nuke.ReadApproval(approvalPath)
+		if err != nil {
+			return nil, err
 		}
 	}
 
-	return n.Run(ctx)
+	return gate, nil
 }
 
-func init() { //nolint:funlen
-	flags := []cli.Flag{
+// runFlags returns the flags of the run command
+func runFlags() []cli.Flag { //nolint:funlen
+	return []cli.Flag{
 		&cli.StringFlag{
 			Name:    "config",
 			Aliases: []string{"c"},
@@ -270,6 +268,10 @@ func init() { //nolint:funlen
 			Name:  "cloud-control",
 			Usage: "use these resource types with the Cloud Control API instead of the default",
 		},
+		&cli.BoolFlag{
+			Name:  "cloud-control-all",
+			Usage: "register every resource type of the Cloud Control catalog that is not implemented by aws-nuke",
+		},
 		&cli.BoolFlag{
 			Name:    "quiet",
 			Aliases: []string{"q"},
@@ -302,6 +304,41 @@ func init() { //nolint:funlen
 			Usage:   "time to sleep between run/loops of resource deletions, default is 5 seconds",
 			Value:   5 * time.Second,
 		},
+		&cli.DurationFlag{
+			Name:  "run-timeout",
+			Usage: "maximum duration of the removal, resources that are not removed by then are reported as timed out",
+		},
+		&cli.BoolFlag{
+			Name:  "detailed-exit-code",
+			Usage: "exit with 2 if a dry run found resources and with 3 if there was nothing to remove, instead of 0",
+		},
+		&cli.DurationFlag{
+			Name:  "grace-period",
+			Usage: "time to wait for removals in progress to finish after SIGINT or SIGTERM, a second signal forces exit",
+			Value: 20 * time.Second,
+		},
+		&cli.BoolFlag{
+			Name:  "review",
+			Usage: "interactively review the resources found by the scan and exclude resources or types before removal",
+		},
+		&cli.StringFlag{
+			Name:  "approval-request",
+			Usage: "path to write the approval request of a dry run to, it is signed with the approval sign command",
+		},
+		&cli.DurationFlag{
+			Name:  "approval-ttl",
+			Usage: "how long the approval request is valid for",
+			Value: 24 * time.Hour,
+		},
+		&cli.StringFlag{
+			Name:   "approval",
+			Usage:  "path to the signed approval, it is required with --no-dry-run if approval is configured",
+			Action: common.CheckFilePath,
+		},
+		&cli.StringFlag{
+			Name:  "report",
+			Usage: "path to write a json report of the run to, it is written regardless of the outcome of the run",
+		},
 		&cli.BoolFlag{
 			Name:  "no-alias-check",
 			Usage: "disable aws account alias check - requires entry in config as well",
@@ -365,14 +402,16 @@ func init() { //nolint:funlen
 			Hidden:  true,
 		},
 	}
+}
 
+func init() {
 	cmd := &cli.Command{
 		Name:  "run",
 		Usage: "run nuke against an aws account and remove everything from it",
 		Aliases: []string{
 			"nuke",
 		},
-		Flags:  append(flags, global.Flags()...),
+		Flags:  append(runFlags(), global.Flags()...),
 		Before: global.Before,
 		Action: execute,
 	}
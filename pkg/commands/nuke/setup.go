@@ -0,0 +1,333 @@
+package nuke
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints" //nolint:staticcheck
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/awsutil"
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+
+	"github.com/ekristen/aws-nuke/v3/resources"
+)
+
+// scanSetup is the nuke process along with everything it was configured from, it is shared by the commands that
+// scan the account
+type scanSetup struct {
+	nuke    *nuke.Nuke
+	params  *libnuke.Parameters
+	config  *config.Config
+	account *awsutil.Account
+	logger  *logrus.Logger
+}
+
+// newScanSetup validates the credentials, parses the configuration, connects to the account and creates the nuke
+// process with the filters and validate handlers of the account
+func newScanSetup(c *cli.Command) (*scanSetup, error) { //nolint:funlen
+	defaultRegion := c.String("default-region")
+	creds := ConfigureCreds(c)
+
+	if err := creds.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Create the parameters object that will be used to configure the nuke process.
+	params := &libnuke.Parameters{
+		Force:          c.Bool("force"),
+		ForceSleep:     c.Int("force-sleep"),
+		Quiet:          c.Bool("quiet"),
+		NoDryRun:       c.Bool("no-dry-run"),
+		Includes:       c.StringSlice("include"),
+		Excludes:       c.StringSlice("exclude"),
+		Alternatives:   c.StringSlice("cloud-control"),
+		MaxWaitRetries: c.Int("max-wait-retries"),
+	}
+
+	if len(c.StringSlice("feature-flag")) > 0 {
+		if slices.Contains(c.StringSlice("feature-flag"), "wait-on-dependencies") {
+			params.WaitOnDependencies = true
+		}
+
+		if slices.Contains(c.StringSlice("feature-flag"), "filter-groups") {
+			params.UseFilterGroups = true
+		}
+	}
+
+	logger := logrus.StandardLogger()
+	logger.SetOutput(os.Stdout)
+
+	// Parse the user supplied configuration file to pass in part to configure the nuke process.
+	parsedConfig, err := config.New(libconfig.Options{
+		Path:         c.String("config"),
+		Deprecations: registry.GetDeprecatedResourceTypeMapping(),
+		Log:          logger.WithField("component", "config"),
+	})
+	if err != nil {
+		logger.Errorf("Failed to parse config file %s", c.String("config"))
+		return nil, err
+	}
+
+	// Set the default region for the AWS SDK to use.
+	if defaultRegion != "" {
+		awsutil.DefaultRegionID = defaultRegion
+
+		partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), defaultRegion)
+		if !ok {
+			if parsedConfig.CustomEndpoints.GetRegion(defaultRegion) == nil {
+				err = fmt.Errorf(
+					"the custom region '%s' must be specified in the configuration 'endpoints'"+
+						" to determine its partition", defaultRegion)
+				logger.WithError(err).Errorf("unable to resolve partition for region: %s", defaultRegion)
+				return nil, err
+			}
+		}
+
+		awsutil.DefaultAWSPartitionID = partition.ID()
+	}
+
+	// Create the AWS Account object. This will be used to get the account ID and aliases for the account.
+	account, err := awsutil.NewAccount(creds, parsedConfig.CustomEndpoints)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errAuth, err)
+	}
+
+	// Get the filters for the account that is being connected to via the AWS SDK.
+	filters, err := parsedConfig.Filters(account.ID())
+	if err != nil {
+		return nil, err
+	}
+
+	// Instantiate aws-nuke's wrapper around libnuke
+	n := nuke.New(params, filters, parsedConfig.Settings)
+
+	n.SetLogger(logger.WithField("component", "libnuke"))
+	n.RegisterVersion(common.AppVersion.String())
+
+	// Register the expiry policy, resources carrying an expiry tag are only removed once they have expired
+	if parsedConfig.Expiry != nil {
+		n.RegisterExpiryPolicy(&nuke.ExpiryPolicy{
+			ExpiresAtTag:       parsedConfig.Expiry.ExpiresAtTag,
+			TTLTag:             parsedConfig.Expiry.TTLTag,
+			CreationProperties: parsedConfig.Expiry.CreationProperties,
+		})
+	}
+
+	// Register our custom validate handler that validates the account and AWS nuke unique alias checks, the account
+	// name and tags are only looked up in Organizations when the blocklist of the account requires them
+	n.RegisterValidateHandler(func() error {
+		details := &config.AccountDetails{
+			ID:      account.ID(),
+			Aliases: account.Aliases(),
+		}
+
+		policy, err := parsedConfig.BlocklistPolicy(account.ID())
+		if err != nil {
+			return err
+		}
+
+		if policy.RequiresOrganizationDetails() {
+			name, tags, err := account.OrganizationDetails()
+			if err != nil {
+				logger.WithError(err).Error("unable to get the account name and tags from organizations")
+			} else {
+				details.Name = name
+				details.Tags = tags
+			}
+		}
+
+		return parsedConfig.ValidateAccountDetails(details, c.Bool("no-alias-check"))
+	})
+
+	return &scanSetup{
+		nuke:    n,
+		params:  params,
+		config:  parsedConfig,
+		account: account,
+		logger:  logger,
+	}, nil
+}
+
+// registerScanners resolves the resource types and registers a scanner for each region of the configuration
+func (s *scanSetup) registerScanners(c *cli.Command, backup *nuke.BackupOptions) error { //nolint:funlen,gocyclo
+	n, parsedConfig, account, logger := s.nuke, s.config, s.account, s.logger
+
+	// Get any specific account level configuration
+	accountConfig := parsedConfig.Accounts[account.ID()]
+
+	// Get current registered resource names
+	resourceNames := registry.GetNames()
+
+	// Combine all the places where alternative resource types can be defined and then dynamically
+	// register them as a Cloud Control resource type.
+	altResourceTypes := types.Collection(registry.ExpandNames(n.Parameters.Alternatives))
+	altResourceTypes = altResourceTypes.Union(parsedConfig.ResourceTypes.GetAlternatives())
+	altResourceTypes = altResourceTypes.Union(accountConfig.ResourceTypes.GetAlternatives())
+	for _, rt := range altResourceTypes {
+		if slices.Contains(resourceNames, rt) {
+			continue
+		}
+
+		resources.RegisterCloudControl(rt)
+	}
+
+	// Register every resource type of the Cloud Control catalog, the include and exclude lists below still apply, so
+	// that a subset of them can be targeted, e.g. `AWS::Lambda::*`
+	if c.Bool("cloud-control-all") {
+		registered, err := resources.RegisterCloudControlCatalog()
+		if err != nil {
+			return err
+		}
+
+		logger.Infof("Registered %d resource types from the Cloud Control catalog", len(registered))
+	}
+
+	// Resolve the resource types to be used for the nuke process based on the parameters, global configuration, and
+	// account level configuration.
+	resourceTypes := types.ResolveResourceTypes(
+		registry.GetNames(), // note: we want to re-pull the registry here due to the dynamic registration above
+		[]types.Collection{
+			registry.ExpandNames(n.Parameters.Includes),
+			parsedConfig.ResourceTypes.GetIncludes(),
+			accountConfig.ResourceTypes.GetIncludes(),
+		},
+		[]types.Collection{
+			registry.ExpandNames(n.Parameters.Excludes),
+			parsedConfig.ResourceTypes.Excludes,
+			accountConfig.ResourceTypes.Excludes,
+		},
+		[]types.Collection{
+			registry.ExpandNames(n.Parameters.Alternatives),
+			parsedConfig.ResourceTypes.GetAlternatives(),
+			accountConfig.ResourceTypes.GetAlternatives(),
+		},
+		registry.GetAlternativeResourceTypeMapping(),
+	)
+
+	// If the user has specified the "all" region, then we need to get the enabled regions for the account
+	// and use those. Otherwise, we will use the regions that are specified in the configuration.
+	if slices.Contains(parsedConfig.Regions, "all") {
+		parsedConfig.Regions = account.Regions()
+
+		logger.Info(
+			`"all" detected in region list, only enabled regions and "global" will be used, all others ignored`)
+
+		if len(parsedConfig.Regions) > 1 {
+			logger.Warnf(`additional regions defined along with "all", these will be ignored!`)
+		}
+
+		logger.Infof("The following regions are enabled for the account (%d total):", len(parsedConfig.Regions))
+
+		printableRegions := make([]string, 0)
+		for i, region := range parsedConfig.Regions {
+			printableRegions = append(printableRegions, region)
+			if i%6 == 0 { // print 5 regions per line
+				logger.Infof("> %s", strings.Join(printableRegions, ", "))
+				printableRegions = make([]string, 0)
+			} else if i == len(parsedConfig.Regions)-1 {
+				logger.Infof("> %s", strings.Join(printableRegions, ", "))
+			}
+		}
+	}
+
+	// The requests to the Cloud Control API are limited per region, the limiter is shared by the scanners of all
+	// regions, since multiple regions in the configuration may use the same session region, e.g. global.
+	requestsPerMinute, maxBackoff, err := parsedConfig.CloudControlRateLimit.Limits()
+	if err != nil {
+		return err
+	}
+
+	cloudControlLimiter := nuke.NewRateLimiter(requestsPerMinute, maxBackoff)
+
+	// Register the scanners for each region that is defined in the configuration.
+	for _, regionName := range parsedConfig.Regions {
+		// Step 0 - Register the filters for the region along with where they were defined in the configuration, so
+		// that filtered resources report the filter that matched. Any region scoped settings are registered as well,
+		// resources discovered by the scanner for the region will be evaluated against these instead of the account
+		// wide defaults.
+		filterSources, filterSourcesErr := parsedConfig.RegionFilterSources(account.ID(), regionName)
+		if filterSourcesErr != nil {
+			return filterSourcesErr
+		}
+
+		n.RegisterRegionFilterSources(regionName, filterSources)
+
+		if parsedConfig.GetRegion(account.ID(), regionName) != nil {
+			n.RegisterRegionSettings(regionName, parsedConfig.RegionSettings(account.ID(), regionName))
+		}
+
+		// Step 1 - Create the region object
+		region := nuke.NewRegion(regionName, account.ResourceTypeToServiceType, account.NewSession, account.NewConfig)
+
+		// Step 2 - Create the scannerActual object
+		scannerActual, scannerActualErr := scanner.New(&scanner.Config{
+			Owner:         regionName,
+			ResourceTypes: resourceTypes,
+			Opts: &nuke.ListerOpts{
+				Region:    region,
+				AccountID: ptr.String(account.ID()),
+				Backup:    backup,
+				Logger: logger.WithFields(logrus.Fields{
+					"component": "scanner",
+					"region":    regionName,
+				}),
+				CloudControlLimiter: cloudControlLimiter,
+			},
+			Logger:          logger,
+			ParallelQueries: c.Int64("parallel-queries"),
+			QueueSize:       c.Int("max-queue-size"),
+		})
+		if scannerActualErr != nil {
+			return scannerActualErr
+		}
+
+		// Step 3 - Register a mutate function that will be called to modify the lister options for each resource type
+		// see pkg/nuke/resource.go for the MutateOpts function. Its purpose is to create the proper session for the
+		// proper region.
+		regMutateErr := scannerActual.RegisterMutateOptsFunc(nuke.MutateOpts)
+		if regMutateErr != nil {
+			return regMutateErr
+		}
+
+		// Step 4 - Register the scannerActual with the nuke object
+		regScanErr := n.RegisterScanner(nuke.Account, scannerActual)
+		if regScanErr != nil {
+			return regScanErr
+		}
+	}
+
+	return nil
+}
+
+// scanFlags returns the flags of the run command that configure the scan, commands that only scan the account use
+// them so that they find the same resources as a run would
+func scanFlags() []cli.Flag {
+	names := []string{
+		"config", "include", "exclude", "cloud-control", "cloud-control-all", "quiet", "no-alias-check", "feature-flag",
+		"default-region", "access-key-id", "secret-access-key", "session-token", "profile", "assume-role-arn",
+		"assume-role-session-name", "assume-role-external-id", "parallel-queries", "max-queue-size",
+	}
+
+	flags := make([]cli.Flag, 0, len(names))
+	for _, flag := range runFlags() {
+		if slices.Contains(names, flag.Names()[0]) {
+			flags = append(flags, flag)
+		}
+	}
+
+	return flags
+}
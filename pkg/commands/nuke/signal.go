@@ -0,0 +1,67 @@
+package nuke
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+// handleSignals interrupts the run on the first SIGINT or SIGTERM. No new removals are triggered and removals that
+// are in progress are waited on until the grace period expires, after which the context is cancelled. Before the
+// removal has started there is nothing to wait on, so the context is cancelled right away. A second signal calls
+// forced and exits immediately. The returned function stops handling the signals.
+func handleSignals(n *nuke.Nuke, cancel context.CancelFunc, grace time.Duration, logger *logrus.Logger,
+	forced func()) func() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	done := make(chan struct{})
+
+	go func() {
+		var sig os.Signal
+		select {
+		case sig = <-signals:
+		case <-done:
+			return
+		}
+
+		n.Interrupt()
+
+		if !n.Removing() {
+			logger.Warnf("received %s, stopping the run, send it again to force exit", sig)
+			cancel()
+		} else {
+			logger.Warnf("received %s, no new removals are triggered, waiting up to %s for removals in progress, "+
+				"send it again to force exit", sig, grace)
+		}
+
+		timer := time.NewTimer(grace)
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-timer.C:
+				logger.Warn("grace period expired, cancelling the removals in progress")
+				cancel()
+			case sig = <-signals:
+				logger.Errorf("received %s again, forcing exit", sig)
+				forced()
+				os.Exit(common.ExitCodeForced)
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The exit codes of aws-nuke, main.go documents when each of them is used
+const (
+	ExitCodeSuccess        = 0
+	ExitCodeError          = 1
+	ExitCodeResourcesFound = 2
+	ExitCodeNothingToDo    = 3
+	ExitCodeRemovalFailed  = 4
+	ExitCodeValidation     = 5
+	ExitCodeAuth           = 6
+	ExitCodeWindowClosed   = 7
+	ExitCodeInterrupted    = 130
+	ExitCodeForced         = 137
+)
+
+// ExitError is returned by a command to exit with a specific code, the command has already reported the error. The
+// error is nil when the code reports an outcome that is not a failure, e.g. that a dry run found resources.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("exit code %d", e.Code)
+	}
+
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCodeOf returns the exit code for the error returned by a command
+func ExitCodeOf(err error) int {
+	if err == nil {
+		return ExitCodeSuccess
+	}
+
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+
+	return ExitCodeError
+}
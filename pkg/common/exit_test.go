@@ -0,0 +1,20 @@
+package common
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExitCodeOf(t *testing.T) {
+	assert.Equal(t, ExitCodeSuccess, ExitCodeOf(nil))
+	assert.Equal(t, ExitCodeError, ExitCodeOf(errors.New("unexpected")))
+	assert.Equal(t, ExitCodeNothingToDo, ExitCodeOf(&ExitError{Code: ExitCodeNothingToDo}))
+	assert.Equal(t, ExitCodeRemovalFailed,
+		ExitCodeOf(fmt.Errorf("wrapped: %w", &ExitError{Code: ExitCodeRemovalFailed, Err: errors.New("failed")})))
+
+	assert.Equal(t, "exit code 3", (&ExitError{Code: ExitCodeNothingToDo}).Error())
+	assert.Equal(t, "failed", (&ExitError{Code: ExitCodeRemovalFailed, Err: errors.New("failed")}).Error())
+}
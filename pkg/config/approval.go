@@ -0,0 +1,63 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+)
+
+// Approver is a person that can approve a run by signing the approval request of a dry run.
+type Approver struct {
+	// Name is the name of the approver, it is used in messages only.
+	Name string `yaml:"name"`
+
+	// PublicKey is the base64 encoded ed25519 public key of the approver.
+	PublicKey string `yaml:"public-key"`
+}
+
+// Approval is the configuration for requiring a signed approval before running with --no-dry-run.
+type Approval struct {
+	// Approvers is the list of approvers whose signatures are trusted.
+	Approvers []Approver `yaml:"approvers"`
+
+	// MinApprovals is the number of distinct approvers that must sign the approval request, defaults to 1.
+	MinApprovals int `yaml:"min-approvals"`
+}
+
+// GetMinApprovals returns the number of approvals required
+func (a *Approval) GetMinApprovals() int {
+	if a.MinApprovals <= 0 {
+		return 1
+	}
+
+	return a.MinApprovals
+}
+
+// GetPublicKeys returns the decoded public keys of the approvers
+func (a *Approval) GetPublicKeys() ([]ed25519.PublicKey, error) {
+	keys := make([]ed25519.PublicKey, 0, len(a.Approvers))
+	for _, approver := range a.Approvers {
+		key, err := base64.StdEncoding.DecodeString(approver.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("approval: the public key of %s is not a base64 encoded ed25519 key", approver.Name)
+		}
+
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+// Validate checks that the public keys are valid and that there are enough approvers.
+func (a *Approval) Validate() error {
+	if _, err := a.GetPublicKeys(); err != nil {
+		return err
+	}
+
+	if len(a.Approvers) < a.GetMinApprovals() {
+		return fmt.Errorf("approval: %d approvals are required, but only %d approvers are configured",
+			a.GetMinApprovals(), len(a.Approvers))
+	}
+
+	return nil
+}
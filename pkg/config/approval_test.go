@@ -0,0 +1,31 @@
+package config
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApproval_Validate(t *testing.T) {
+	publicKey, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	alice := Approver{Name: "alice", PublicKey: base64.StdEncoding.EncodeToString(publicKey)}
+
+	approval := &Approval{Approvers: []Approver{alice}}
+	assert.NoError(t, approval.Validate())
+	assert.Equal(t, 1, approval.GetMinApprovals())
+
+	keys, err := approval.GetPublicKeys()
+	assert.NoError(t, err)
+	assert.Equal(t, []ed25519.PublicKey{publicKey}, keys)
+
+	assert.EqualError(t, (&Approval{Approvers: []Approver{alice}, MinApprovals: 2}).Validate(),
+		"approval: 2 approvals are required, but only 1 approvers are configured")
+	assert.EqualError(t, (&Approval{}).Validate(),
+		"approval: 1 approvals are required, but only 0 approvers are configured")
+	assert.EqualError(t, (&Approval{Approvers: []Approver{{Name: "bob", PublicKey: "invalid"}}}).Validate(),
+		"approval: the public key of bob is not a base64 encoded ed25519 key")
+}
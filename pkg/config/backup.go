@@ -0,0 +1,97 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+)
+
+// DefaultBackupPassphraseEnv is the environment variable the passphrase for encrypted exports is read from by default
+const DefaultBackupPassphraseEnv = "AWS_NUKE_BACKUP_PASSPHRASE"
+
+// DefaultBackupExportDirectory is the directory encrypted exports are written to by default
+const DefaultBackupExportDirectory = "aws-nuke-backups"
+
+// BackupResourceTypes is the list of resource types that support creating an artifact before removal
+var BackupResourceTypes = []string{
+	"DocDBCluster",
+	"DynamoDBTable",
+	"EC2Volume",
+	"NeptuneCluster",
+	"RDSDBCluster",
+	"RDSInstance",
+	"SSMParameter",
+	"SecretsManagerSecret",
+}
+
+// BackupExportResourceTypes is the list of resource types whose values are exported to an encrypted local file
+// instead of an artifact in the account.
+var BackupExportResourceTypes = []string{
+	"SSMParameter",
+	"SecretsManagerSecret",
+}
+
+// BackupBeforeDelete is the configuration for creating a recoverable artifact before a resource is removed.
+type BackupBeforeDelete struct {
+	// ResourceTypes limits the backups to the given resource types, if it is empty all supported types are backed up.
+	ResourceTypes []string `yaml:"resource-types"`
+
+	// ExportDirectory is the local directory that encrypted exports are written to.
+	ExportDirectory string `yaml:"export-directory"`
+
+	// PassphraseEnv is the name of the environment variable that holds the passphrase the exports are encrypted with.
+	PassphraseEnv string `yaml:"passphrase-env"`
+}
+
+// GetResourceTypes returns the resource types that are backed up
+func (b *BackupBeforeDelete) GetResourceTypes() []string {
+	if len(b.ResourceTypes) == 0 {
+		return BackupResourceTypes
+	}
+
+	return b.ResourceTypes
+}
+
+// GetExportDirectory returns the export directory, or the default if it is not set
+func (b *BackupBeforeDelete) GetExportDirectory() string {
+	if b.ExportDirectory == "" {
+		return DefaultBackupExportDirectory
+	}
+
+	return b.ExportDirectory
+}
+
+// GetPassphrase returns the passphrase from the configured environment variable
+func (b *BackupBeforeDelete) GetPassphrase() string {
+	if b.PassphraseEnv == "" {
+		return os.Getenv(DefaultBackupPassphraseEnv)
+	}
+
+	return os.Getenv(b.PassphraseEnv)
+}
+
+// Validate checks that the resource types support backups and that a passphrase is available if any of them are
+// exported to a local file.
+func (b *BackupBeforeDelete) Validate() error {
+	requiresPassphrase := false
+	for _, resourceType := range b.GetResourceTypes() {
+		if !slices.Contains(BackupResourceTypes, resourceType) {
+			return fmt.Errorf("backup-before-delete: resource type %s does not support backups", resourceType)
+		}
+
+		if slices.Contains(BackupExportResourceTypes, resourceType) {
+			requiresPassphrase = true
+		}
+	}
+
+	if requiresPassphrase && b.GetPassphrase() == "" {
+		env := b.PassphraseEnv
+		if env == "" {
+			env = DefaultBackupPassphraseEnv
+		}
+
+		return fmt.Errorf("backup-before-delete: the passphrase for encrypted exports must be set in %s", env)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupBeforeDelete_Validate(t *testing.T) {
+	cases := []struct {
+		name       string
+		backup     *BackupBeforeDelete
+		passphrase string
+		error      string
+	}{
+		{
+			name:   "snapshots only",
+			backup: &BackupBeforeDelete{ResourceTypes: []string{"EC2Volume", "RDSInstance"}},
+		},
+		{
+			name:   "unsupported type",
+			backup: &BackupBeforeDelete{ResourceTypes: []string{"S3Bucket"}},
+			error:  "backup-before-delete: resource type S3Bucket does not support backups",
+		},
+		{
+			name:   "all types require passphrase",
+			backup: &BackupBeforeDelete{},
+			error:  "backup-before-delete: the passphrase for encrypted exports must be set in AWS_NUKE_BACKUP_PASSPHRASE",
+		},
+		{
+			name:       "all types with passphrase",
+			backup:     &BackupBeforeDelete{},
+			passphrase: "secret",
+		},
+		{
+			name:   "custom passphrase env",
+			backup: &BackupBeforeDelete{ResourceTypes: []string{"SSMParameter"}, PassphraseEnv: "MY_PASSPHRASE"},
+			error:  "backup-before-delete: the passphrase for encrypted exports must be set in MY_PASSPHRASE",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Setenv(DefaultBackupPassphraseEnv, tc.passphrase)
+			t.Setenv("MY_PASSPHRASE", "")
+
+			err := tc.backup.Validate()
+			if tc.error != "" {
+				assert.EqualError(t, err, tc.error)
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestBackupBeforeDelete_Defaults(t *testing.T) {
+	backup := &BackupBeforeDelete{}
+	assert.Equal(t, BackupResourceTypes, backup.GetResourceTypes())
+	assert.Equal(t, DefaultBackupExportDirectory, backup.GetExportDirectory())
+}
@@ -0,0 +1,181 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// AccountDetails is the information about an account that is validated against the blocklist.
+type AccountDetails struct {
+	// ID is the account ID
+	ID string
+
+	// Aliases is the list of IAM aliases for the account
+	Aliases []string
+
+	// Name is the account name from Organizations, it is empty if it could not be retrieved
+	Name string
+
+	// Tags is the account tags from Organizations
+	Tags map[string]string
+}
+
+// BlocklistPolicy is the effective blocklist for an account, it is the combination of the global blocklist settings
+// and the account level blocklist settings.
+type BlocklistPolicy struct {
+	Terms            []string
+	Regex            []*regexp.Regexp
+	Tags             []string
+	MatchAccountName bool
+}
+
+// RequiresOrganizationDetails returns true if the policy needs the account name or tags from Organizations
+func (p *BlocklistPolicy) RequiresOrganizationDetails() bool {
+	return len(p.Tags) > 0 || p.MatchAccountName
+}
+
+// Match returns the term, or regex, that matches the value, if any. Terms are matched case-insensitive, regular
+// expressions are matched as is.
+func (p *BlocklistPolicy) Match(value string) (string, bool) {
+	for _, term := range p.Terms {
+		if strings.Contains(strings.ToLower(value), strings.ToLower(term)) {
+			return term, true
+		}
+	}
+
+	for _, re := range p.Regex {
+		if re.MatchString(value) {
+			return re.String(), true
+		}
+	}
+
+	return "", false
+}
+
+// MatchTags returns the blocklisted tag that matches the account tags, if any. Tag keys are matched exactly, tag
+// values are matched case-insensitive.
+func (p *BlocklistPolicy) MatchTags(tags map[string]string) (string, bool) {
+	for _, blocklisted := range p.Tags {
+		key, value, hasValue := strings.Cut(blocklisted, "=")
+
+		actual, ok := tags[key]
+		if !ok {
+			continue
+		}
+
+		if !hasValue || strings.EqualFold(actual, value) {
+			return blocklisted, true
+		}
+	}
+
+	return "", false
+}
+
+// BlocklistPolicy returns the effective blocklist policy for the account.
+func (c *Config) BlocklistPolicy(accountID string) (*BlocklistPolicy, error) {
+	terms := c.BlocklistTerms
+	regex := c.BlocklistRegex
+	tags := c.BlocklistTags
+	matchAccountName := c.BlocklistMatchAccountName
+
+	if account := c.AccountExtensions[accountID]; account != nil {
+		if account.BlocklistOverride {
+			terms, regex, tags = nil, nil, nil
+		}
+
+		terms = append(terms[:len(terms):len(terms)], account.BlocklistTerms...)
+		regex = append(regex[:len(regex):len(regex)], account.BlocklistRegex...)
+		tags = append(tags[:len(tags):len(tags)], account.BlocklistTags...)
+
+		if account.BlocklistMatchAccountName != nil {
+			matchAccountName = *account.BlocklistMatchAccountName
+		}
+	}
+
+	policy := &BlocklistPolicy{
+		Terms:            terms,
+		Tags:             tags,
+		MatchAccountName: matchAccountName,
+	}
+
+	for _, expr := range regex {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocklist regex '%s': %w", expr, err)
+		}
+
+		policy.Regex = append(policy.Regex, re)
+	}
+
+	return policy, nil
+}
+
+// ValidateAccountDetails validates the account against the configuration. In addition to the checks described in
+// ValidateAccount, the account name and tags from Organizations are checked if the blocklist policy of the account
+// requires them. The account name and tags are checked even when the alias checks are skipped.
+func (c *Config) ValidateAccountDetails(details *AccountDetails, skipAliasChecks bool) error {
+	// Call the libnuke config validation first
+	if err := c.Config.ValidateAccount(details.ID); err != nil {
+		return err
+	}
+
+	policy, err := c.BlocklistPolicy(details.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.validateOrganizationDetails(policy, details); err != nil {
+		return err
+	}
+
+	if skipAliasChecks {
+		if c.InBypassAliasCheckAccounts(details.ID) {
+			return nil
+		}
+
+		c.Log.Warnf("--no-alias-check is set, but the account ID '%s' isn't in the bypass list.", details.ID)
+	}
+
+	if len(details.Aliases) == 0 {
+		return fmt.Errorf("specified account doesn't have an alias. " +
+			"For safety reasons you need to specify an account alias. " +
+			"Your production account should contain the term 'prod'")
+	}
+
+	for _, alias := range details.Aliases {
+		if keyword, ok := policy.Match(alias); ok {
+			return fmt.Errorf("you are trying to nuke an account with the alias '%s', "+
+				"but it contains the blocklisted keyword '%s'. Aborting", alias, keyword)
+		}
+	}
+
+	return nil
+}
+
+// validateOrganizationDetails validates the account name and tags against the policy, if the policy requires them,
+// but they are not available, validation fails rather than silently skipping the check.
+func (c *Config) validateOrganizationDetails(policy *BlocklistPolicy, details *AccountDetails) error {
+	if !policy.RequiresOrganizationDetails() {
+		return nil
+	}
+
+	if details.Name == "" {
+		return fmt.Errorf("the blocklist for account '%s' requires the account name and tags from Organizations, "+
+			"but they are not available. Aborting", details.ID)
+	}
+
+	if tag, ok := policy.MatchTags(details.Tags); ok {
+		return fmt.Errorf("you are trying to nuke an account with the tag '%s', "+
+			"which is blocklisted. Aborting", tag)
+	}
+
+	if policy.MatchAccountName {
+		if keyword, ok := policy.Match(details.Name); ok {
+			return fmt.Errorf("you are trying to nuke an account with the name '%s', "+
+				"but it contains the blocklisted keyword '%s'. Aborting", details.Name, keyword)
+		}
+	}
+
+	return nil
+}
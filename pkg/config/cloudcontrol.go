@@ -0,0 +1,67 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+const (
+	// DefaultCloudControlRequestsPerMinute is the default rate of requests to the Cloud Control API per region. AWS
+	// does not publish the rate limits, the rate seems to be 60 requests per minute.
+	DefaultCloudControlRequestsPerMinute = 55
+
+	// DefaultCloudControlMaxBackoff is the default maximum delay that is added to every request to the Cloud Control
+	// API of a region after it was throttled.
+	DefaultCloudControlMaxBackoff = 30 * time.Second
+)
+
+// CloudControlRateLimit configures the rate limit of the requests to the Cloud Control API, it is defined next to the
+// Cloud Control resource types as `resource-types.cloud-control-rate-limit`. Every region has its own rate limit.
+type CloudControlRateLimit struct {
+	// RequestsPerMinute is the number of requests per minute per region, including listing, deleting and polling
+	// the status of deletions. If it is not set, DefaultCloudControlRequestsPerMinute is used.
+	RequestsPerMinute int `yaml:"requests-per-minute"`
+
+	// MaxBackoff is the maximum delay that is added to requests after the region was throttled, e.g. `1m`. The delay
+	// doubles every time the region is throttled and halves after every successful request. If it is not set,
+	// DefaultCloudControlMaxBackoff is used.
+	MaxBackoff string `yaml:"max-backoff"`
+}
+
+// Limits returns the requests per minute and the maximum backoff, with the defaults for the ones that are not set.
+func (r *CloudControlRateLimit) Limits() (int, time.Duration, error) {
+	if r == nil {
+		return DefaultCloudControlRequestsPerMinute, DefaultCloudControlMaxBackoff, nil
+	}
+
+	requestsPerMinute := DefaultCloudControlRequestsPerMinute
+	if r.RequestsPerMinute < 0 {
+		return 0, 0, fmt.Errorf("cloud-control-rate-limit: requests-per-minute must be positive, got %d",
+			r.RequestsPerMinute)
+	} else if r.RequestsPerMinute > 0 {
+		requestsPerMinute = r.RequestsPerMinute
+	}
+
+	maxBackoff := DefaultCloudControlMaxBackoff
+	if r.MaxBackoff != "" {
+		parsed, err := time.ParseDuration(r.MaxBackoff)
+		if err != nil {
+			return 0, 0, fmt.Errorf("cloud-control-rate-limit: max-backoff: %w", err)
+		}
+
+		if parsed < 0 {
+			return 0, 0, fmt.Errorf("cloud-control-rate-limit: max-backoff must not be negative, got %s", r.MaxBackoff)
+		}
+
+		maxBackoff = parsed
+	}
+
+	return requestsPerMinute, maxBackoff, nil
+}
+
+// resourceTypesOnly is used to parse the `resource-types` block a second time for the aws-nuke specific settings.
+type resourceTypesOnly struct {
+	ResourceTypes struct {
+		CloudControlRateLimit *CloudControlRateLimit `yaml:"cloud-control-rate-limit"`
+	} `yaml:"resource-types"`
+}
@@ -0,0 +1,67 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+func TestCloudControlRateLimit_Limits(t *testing.T) {
+	requestsPerMinute, maxBackoff, err := (&CloudControlRateLimit{
+		RequestsPerMinute: 120,
+		MaxBackoff:        "1m",
+	}).Limits()
+	assert.NoError(t, err)
+	assert.Equal(t, 120, requestsPerMinute)
+	assert.Equal(t, time.Minute, maxBackoff)
+
+	var unset *CloudControlRateLimit
+	requestsPerMinute, maxBackoff, err = unset.Limits()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCloudControlRequestsPerMinute, requestsPerMinute)
+	assert.Equal(t, DefaultCloudControlMaxBackoff, maxBackoff)
+
+	requestsPerMinute, maxBackoff, err = (&CloudControlRateLimit{}).Limits()
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultCloudControlRequestsPerMinute, requestsPerMinute)
+	assert.Equal(t, DefaultCloudControlMaxBackoff, maxBackoff)
+
+	_, _, err = (&CloudControlRateLimit{RequestsPerMinute: -1}).Limits()
+	assert.EqualError(t, err, "cloud-control-rate-limit: requests-per-minute must be positive, got -1")
+
+	_, _, err = (&CloudControlRateLimit{MaxBackoff: "later"}).Limits()
+	assert.EqualError(t, err, `cloud-control-rate-limit: max-backoff: time: invalid duration "later"`)
+
+	_, _, err = (&CloudControlRateLimit{MaxBackoff: "-1s"}).Limits()
+	assert.EqualError(t, err, "cloud-control-rate-limit: max-backoff must not be negative, got -1s")
+}
+
+func TestConfig_CloudControlRateLimit(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/cloudcontrol.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &CloudControlRateLimit{
+		RequestsPerMinute: 120,
+		MaxBackoff:        "1m",
+	}, config.CloudControlRateLimit)
+
+	// Note: the rate limit is parsed next to the libnuke resource types, which are not affected by it
+	assert.Equal(t, types.Collection{"AWS::EC2::VPC"}, config.ResourceTypes.GetAlternatives())
+
+	example, err := New(libconfig.Options{
+		Path: "testdata/example.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, example.CloudControlRateLimit)
+}
@@ -3,11 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
-	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/ekristen/libnuke/pkg/config"
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/filter"
 	"github.com/ekristen/libnuke/pkg/settings"
 )
 
@@ -35,6 +36,13 @@ func New(opts config.Options) (*Config, error) {
 	// Step 5 - Resolve any deprecated feature flags
 	c.ResolveDeprecatedFeatureFlags()
 
+	// Step 6 - Resolve any deprecated resource types in the region scoped filters
+	if !opts.NoResolveDeprecations {
+		if err := c.ResolveRegionDeprecations(); err != nil {
+			return nil, err
+		}
+	}
+
 	return c, nil
 }
 
@@ -51,6 +59,18 @@ type Config struct {
 	// blocklist.
 	NoBlocklistTermsDefault bool `yaml:"no-blocklist-terms-default"`
 
+	// BlocklistRegex is a list of regular expressions that are blocklisted from being used in an alias, or the
+	// account name if BlocklistMatchAccountName is set.
+	BlocklistRegex []string `yaml:"blocklist-regex"`
+
+	// BlocklistTags is a list of Organizations account tags, in the form of Key=Value, that are blocklisted. If only
+	// the Key is given, any value is blocklisted.
+	BlocklistTags []string `yaml:"blocklist-tags"`
+
+	// BlocklistMatchAccountName is a setting that enables matching the blocklist terms and regex against the account
+	// name from Organizations in addition to the aliases.
+	BlocklistMatchAccountName bool `yaml:"blocklist-match-account-name"`
+
 	// BypassAliasCheckAccounts is a list of account IDs that will be allowed to bypass the alias check.
 	// This is useful for accounts that don't have an alias for a number of reasons, it must be used with a cli
 	// flag --no-alias-check to be effective.
@@ -65,6 +85,103 @@ type Config struct {
 
 	// CustomEndpoints is a collection of custom endpoints that can be used to override the default AWS endpoints.
 	CustomEndpoints CustomEndpoints `yaml:"endpoints"`
+
+	// Expiry configures the built-in expiry policy, resources tagged with an expiry are only removed once they have
+	// expired. If it is not defined, the policy is disabled.
+	Expiry *Expiry `yaml:"expiry"`
+
+	// Schedule restricts the removal of resources to maintenance windows, it is only enforced with --no-dry-run. If it
+	// is not defined, removals are allowed at any time.
+	Schedule *Schedule `yaml:"schedule"`
+
+	// BackupBeforeDelete configures creating a recoverable artifact, e.g. a snapshot, before stateful resources are
+	// removed. If it is not defined, no artifacts are created.
+	BackupBeforeDelete *BackupBeforeDelete `yaml:"backup-before-delete"`
+
+	// PolicyExport configures exporting the IAM and resource policies of resources to a local directory before they
+	// are removed. If it is not defined, no policies are exported.
+	PolicyExport *PolicyExport `yaml:"policy-export"`
+
+	// Approval requires a dry run to be approved by signing it before running with --no-dry-run. If it is not
+	// defined, no approval is required.
+	Approval *Approval `yaml:"approval"`
+
+	// RemoveTimeout limits how long the removal of a resource may take, resources that time out are not retried. If
+	// it is not defined, removals are not limited.
+	RemoveTimeout *RemoveTimeout `yaml:"remove-timeout"`
+
+	// CloudControlRateLimit configures the rate limit of the requests to the Cloud Control API per region. It is
+	// parsed from the `resource-types` block, next to the Cloud Control resource types. If it is not defined, the
+	// defaults are used.
+	CloudControlRateLimit *CloudControlRateLimit `yaml:"-"`
+
+	// AccountExtensions is a collection of aws-nuke specific account level configuration. It is parsed from the same
+	// `accounts` block as the libnuke account configuration, but it cannot be inlined due to the key collision.
+	AccountExtensions map[string]*Account `yaml:"-"`
+
+	// Path is the path of the file the configuration was loaded from, it is used to report where filters were defined.
+	Path string `yaml:"-"`
+}
+
+// Account is the aws-nuke specific extension to the libnuke account configuration.
+type Account struct {
+	// BlocklistTerms is a list of keywords that are blocklisted from being used in an alias for the account only. They
+	// are added to the global BlocklistTerms unless BlocklistOverride is set.
+	BlocklistTerms []string `yaml:"blocklist-terms"`
+
+	// BlocklistRegex is a list of regular expressions that are blocklisted for the account only. They are added to the
+	// global BlocklistRegex unless BlocklistOverride is set.
+	BlocklistRegex []string `yaml:"blocklist-regex"`
+
+	// BlocklistTags is a list of Organizations account tags that are blocklisted for the account only. They are added
+	// to the global BlocklistTags unless BlocklistOverride is set.
+	BlocklistTags []string `yaml:"blocklist-tags"`
+
+	// BlocklistMatchAccountName overrides the global BlocklistMatchAccountName for the account if it is set.
+	BlocklistMatchAccountName *bool `yaml:"blocklist-match-account-name"`
+
+	// BlocklistOverride is a setting that makes the account level blocklist terms, regex and tags replace the global
+	// ones instead of being added to them.
+	BlocklistOverride bool `yaml:"blocklist-override"`
+
+	// Schedule replaces the global schedule for the account.
+	Schedule *Schedule `yaml:"schedule"`
+
+	// Regions is a map of region names to region scoped configuration. The special `global` pseudo-region can be
+	// used to scope configuration to global resources only.
+	Regions map[string]*Region `yaml:"regions"`
+}
+
+// Region is a collection of filters, presets and settings that only apply to resources discovered in a specific
+// region. They are applied in addition to the account level configuration.
+type Region struct {
+	// Presets is a list of presets that are only applied to the region. The presets are defined in the top level
+	// Presets field.
+	Presets []string `yaml:"presets"`
+
+	// Filters is a collection of filters that are only applied to the region.
+	Filters filter.Filters `yaml:"filters"`
+
+	// Settings is a collection of resource settings that are only applied to the region. They are merged on top
+	// of the global settings.
+	Settings *settings.Settings `yaml:"settings"`
+}
+
+// Expiry configures which tags are used by the expiry policy to decide if a resource is eligible for removal.
+type Expiry struct {
+	// ExpiresAtTag is the tag key holding an absolute RFC3339 expiry timestamp, e.g. `nuke:expires-at`
+	ExpiresAtTag string `yaml:"expires-at-tag"`
+
+	// TTLTag is the tag key holding a duration relative to the creation time of the resource, e.g. `nuke:ttl`
+	TTLTag string `yaml:"ttl-tag"`
+
+	// CreationProperties overrides the ordered list of properties used to find the creation time of a resource
+	CreationProperties []string `yaml:"creation-properties"`
+}
+
+// accountsOnly is used to parse the `accounts` block a second time into the aws-nuke specific account configuration.
+type accountsOnly struct {
+	Accounts map[string]*Account `yaml:"accounts"`
 }
 
 // Load loads a configuration from a file and parses it into a Config struct.
@@ -80,6 +197,21 @@ func (c *Config) Load(path string) error {
 		return err
 	}
 
+	accounts := &accountsOnly{}
+	if err := yaml.Unmarshal(raw, accounts); err != nil {
+		return err
+	}
+
+	c.AccountExtensions = accounts.Accounts
+
+	resourceTypes := &resourceTypesOnly{}
+	if err := yaml.Unmarshal(raw, resourceTypes); err != nil {
+		return err
+	}
+
+	c.CloudControlRateLimit = resourceTypes.ResourceTypes.CloudControlRateLimit
+	c.Path = path
+
 	if !c.NoBlocklistTermsDefault {
 		c.BlocklistTerms = append(c.BlocklistTerms, "prod")
 	}
@@ -100,32 +232,119 @@ func (c *Config) InBypassAliasCheckAccounts(accountID string) bool {
 
 // ValidateAccount validates the account ID and aliases for the specified account. This will return an error if the
 // account ID is invalid, the account ID is blocklisted, the account doesn't have an alias, the account alias contains
-// the substring 'prod', or the account ID isn't listed in the config.
+// the substring 'prod', or the account ID isn't listed in the config. See ValidateAccountDetails for validating the
+// account name and tags as well.
 func (c *Config) ValidateAccount(accountID string, aliases []string, skipAliasChecks bool) error {
-	// Call the libnuke config validation first
-	if err := c.Config.ValidateAccount(accountID); err != nil {
-		return err
+	return c.ValidateAccountDetails(&AccountDetails{
+		ID:      accountID,
+		Aliases: aliases,
+	}, skipAliasChecks)
+}
+
+// GetRegion returns the region scoped configuration for the account and region, or nil if there is none.
+func (c *Config) GetRegion(accountID, region string) *Region {
+	account, ok := c.AccountExtensions[accountID]
+	if !ok || account == nil {
+		return nil
 	}
 
-	if skipAliasChecks {
-		if c.InBypassAliasCheckAccounts(accountID) {
-			return nil
+	return account.Regions[region]
+}
+
+// Filters resolves the account filters and account presets into one set of filters. It replaces the libnuke Filters
+// function, which appends the presets to the account filters, so that the filters resolved afterward, e.g. by
+// RegionFilterSources, do not contain the presets twice.
+func (c *Config) Filters(accountID string) (filter.Filters, error) {
+	account, ok := c.Accounts[accountID]
+	if !ok || account == nil {
+		return nil, liberrors.ErrAccountNotConfigured
+	}
+
+	filters := filter.Filters{}
+	filters.Append(account.Filters)
+
+	if err := c.appendPresets(filters, account.Presets); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// RegionFilters resolves the account filters, account presets and any filters and presets scoped to the region into
+// one set of filters. Like Filters it does not modify the account filters, so it is safe to call for multiple regions.
+func (c *Config) RegionFilters(accountID, region string) (filter.Filters, error) {
+	filters, err := c.Filters(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	regionConfig := c.GetRegion(accountID, region)
+	if regionConfig == nil {
+		return filters, nil
+	}
+
+	filters.Append(regionConfig.Filters)
+
+	if err := c.appendPresets(filters, regionConfig.Presets); err != nil {
+		return nil, err
+	}
+
+	return filters, nil
+}
+
+// RegionSettings returns the global settings merged with any settings scoped to the account and region. The global
+// settings are copied, so they are never modified.
+func (c *Config) RegionSettings(accountID, region string) *settings.Settings {
+	merged := &settings.Settings{}
+
+	if c.Settings != nil {
+		for name, setting := range *c.Settings {
+			merged.Set(name, copySetting(setting))
 		}
+	}
 
-		c.Log.Warnf("--no-alias-check is set, but the account ID '%s' isn't in the bypass list.", accountID)
+	regionConfig := c.GetRegion(accountID, region)
+	if regionConfig == nil || regionConfig.Settings == nil {
+		return merged
 	}
 
-	if len(aliases) == 0 {
-		return fmt.Errorf("specified account doesn't have an alias. " +
-			"For safety reasons you need to specify an account alias. " +
-			"Your production account should contain the term 'prod'")
+	for name, setting := range *regionConfig.Settings {
+		merged.Set(name, copySetting(setting))
 	}
 
-	for _, alias := range aliases {
-		for _, keyword := range c.BlocklistTerms {
-			if strings.Contains(strings.ToLower(alias), keyword) {
-				return fmt.Errorf("you are trying to nuke an account with the alias '%s', "+
-					"but it contains the blocklisted keyword '%s'. Aborting", alias, keyword)
+	return merged
+}
+
+// ResolveRegionDeprecations resolves any deprecated resource types in the region scoped filters. This is the region
+// scoped equivalent of the libnuke ResolveDeprecations function.
+func (c *Config) ResolveRegionDeprecations() error {
+	for _, account := range c.AccountExtensions {
+		if account == nil {
+			continue
+		}
+
+		for regionName, regionConfig := range account.Regions {
+			if regionConfig == nil {
+				continue
+			}
+
+			for resourceType, filters := range regionConfig.Filters {
+				replacement, ok := c.Deprecations[resourceType]
+				if !ok {
+					continue
+				}
+
+				c.Log.Warnf("deprecated resource type '%s' in region '%s' - converting to '%s'",
+					resourceType, regionName, replacement)
+				if _, ok := regionConfig.Filters[replacement]; ok {
+					return liberrors.ErrDeprecatedResourceType(
+						fmt.Sprintf(
+							"using deprecated resource type and replacement: '%s','%s'", resourceType, replacement))
+				}
+
+				regionConfig.Filters[replacement] = filters
+
+				delete(regionConfig.Filters, resourceType)
 			}
 		}
 	}
@@ -133,6 +352,34 @@ func (c *Config) ValidateAccount(accountID string, aliases []string, skipAliasCh
 	return nil
 }
 
+// appendPresets appends the filters of each named preset to the filters, returning an error for unknown presets.
+func (c *Config) appendPresets(filters filter.Filters, presets []string) error {
+	for _, presetName := range presets {
+		preset, ok := c.Presets[presetName]
+		if !ok {
+			return liberrors.ErrUnknownPreset(presetName)
+		}
+
+		filters.Append(preset.Filters)
+	}
+
+	return nil
+}
+
+// copySetting returns a shallow copy of a setting so that merging settings never modifies the source.
+func copySetting(setting *settings.Setting) *settings.Setting {
+	copied := settings.Setting{}
+	if setting == nil {
+		return &copied
+	}
+
+	for k, v := range *setting {
+		copied[k] = v
+	}
+
+	return &copied
+}
+
 // ResolveDeprecatedFeatureFlags resolves any deprecated feature flags in the configuration. This converts the legacy
 // feature flags into the new settings format. The feature flags will be deprecated with version 4.x. This was left in
 // place to make the transition to the libnuke library and ekristen/aws-nuke@v3 easier for existing users.
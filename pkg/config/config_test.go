@@ -113,6 +113,10 @@ func TestConfig_LoadExample(t *testing.T) {
 			},
 		},
 		BlocklistTerms: []string{"prod"},
+		AccountExtensions: map[string]*Account{
+			"555133742": {},
+		},
+		Path: "testdata/example.yaml",
 	}
 
 	assert.Equal(t, expect, *config)
@@ -176,6 +180,10 @@ func TestConfig_NoBlocklistTermsProd(t *testing.T) {
 		CustomEndpoints:         CustomEndpoints{},
 		BlocklistTerms:          []string{"alpha"},
 		NoBlocklistTermsDefault: true,
+		AccountExtensions: map[string]*Account{
+			"555133742": {},
+		},
+		Path: "testdata/no-blocklist-term-prod.yaml",
 	}
 
 	assert.Equal(t, expect, *config)
@@ -501,3 +509,274 @@ func TestConfig_ValidateAccount_Blocklist(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_RegionFilters(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/region-scoped.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		region string
+		want   map[string]int
+	}{
+		{
+			name:   "unscoped",
+			region: "us-east-1",
+			want:   map[string]int{"IAMRole": 1, "S3Bucket": 1},
+		},
+		{
+			name:   "scoped",
+			region: "eu-west-1",
+			want:   map[string]int{"IAMRole": 1, "S3Bucket": 1, "EC2Instance": 1, "EC2VPC": 1},
+		},
+		{
+			name:   "global",
+			region: "global",
+			want:   map[string]int{"IAMRole": 1, "S3Bucket": 1, "IAMUser": 1},
+		},
+		{
+			// Note: resolving a second time must not duplicate preset filters
+			name:   "scoped-again",
+			region: "eu-west-1",
+			want:   map[string]int{"IAMRole": 1, "S3Bucket": 1, "EC2Instance": 1, "EC2VPC": 1},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			filters, err := config.RegionFilters("555133742", tc.region)
+			assert.NoError(t, err)
+
+			got := map[string]int{}
+			for resourceType, resourceFilters := range filters {
+				got[resourceType] = len(resourceFilters)
+			}
+
+			assert.Equal(t, tc.want, got)
+		})
+	}
+
+	_, err = config.RegionFilters("1111111111", "eu-west-1")
+	assert.Error(t, err)
+}
+
+func TestConfig_RegionSettings(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/region-scoped.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	euWest1 := config.RegionSettings("555133742", "eu-west-1").Get("EC2Instance")
+	assert.True(t, euWest1.GetBool("DisableStopProtection"))
+	assert.True(t, euWest1.GetBool("DisableDeletionProtection"))
+
+	usEast1 := config.RegionSettings("555133742", "us-east-1").Get("EC2Instance")
+	assert.True(t, usEast1.GetBool("DisableStopProtection"))
+	assert.False(t, usEast1.GetBool("DisableDeletionProtection"))
+
+	// Note: the global settings must never be modified by the region scoped settings
+	assert.False(t, config.Settings.Get("EC2Instance").GetBool("DisableDeletionProtection"))
+}
+
+func TestConfig_RegionFiltersUnknownPreset(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/region-scoped.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.GetRegion("555133742", "eu-west-1").Presets = []string{"missing"}
+
+	_, err = config.RegionFilters("555133742", "eu-west-1")
+	assert.ErrorContains(t, err, "missing")
+}
+
+func TestConfig_Expiry(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/expiry.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, &Expiry{
+		ExpiresAtTag:       "nuke:expires-at",
+		TTLTag:             "nuke:ttl",
+		CreationProperties: []string{"LaunchTime"},
+	}, config.Expiry)
+
+	example, err := New(libconfig.Options{
+		Path: "testdata/example.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Nil(t, example.Expiry)
+}
+
+func TestConfig_ValidateAccountDetails(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/blocklist.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name            string
+		details         AccountDetails
+		skipAliasChecks bool
+		wantErr         string
+	}{
+		{
+			name: "allowed",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"sandbox"}, Name: "Sandbox",
+				Tags: map[string]string{"Environment": "sandbox"},
+			},
+		},
+		{
+			name: "global-term",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"team-Staging"}, Name: "Sandbox",
+			},
+			wantErr: "blocklisted keyword 'staging'",
+		},
+		{
+			name: "default-term",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"my-prod"}, Name: "Sandbox",
+			},
+			wantErr: "blocklisted keyword 'prod'",
+		},
+		{
+			name: "global-regex",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"live-42"}, Name: "Sandbox",
+			},
+			wantErr: "blocklisted keyword '^live-[0-9]+$'",
+		},
+		{
+			name: "global-regex-no-match",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"live-42-test"}, Name: "Sandbox",
+			},
+		},
+		{
+			name: "global-tag",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"sandbox"}, Name: "Sandbox",
+				Tags: map[string]string{"Environment": "Production"},
+			},
+			wantErr: "tag 'Environment=production'",
+		},
+		{
+			name: "global-tag-skip-alias-checks",
+			details: AccountDetails{
+				ID: "555133742", Name: "Sandbox",
+				Tags: map[string]string{"Environment": "production"},
+			},
+			skipAliasChecks: true,
+			wantErr:         "tag 'Environment=production'",
+		},
+		{
+			name: "organization-details-missing",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"sandbox"},
+			},
+			wantErr: "requires the account name and tags from Organizations",
+		},
+		{
+			name: "account-term",
+			details: AccountDetails{
+				ID: "555133743", Aliases: []string{"shared-services"}, Name: "Sandbox",
+			},
+			wantErr: "blocklisted keyword 'shared'",
+		},
+		{
+			name: "account-name",
+			details: AccountDetails{
+				ID: "555133743", Aliases: []string{"sandbox"}, Name: "Staging Workloads",
+			},
+			wantErr: "name 'Staging Workloads'",
+		},
+		{
+			name: "account-name-not-enabled",
+			details: AccountDetails{
+				ID: "555133742", Aliases: []string{"sandbox"}, Name: "Staging Workloads",
+			},
+		},
+		{
+			name: "override-drops-global-terms",
+			details: AccountDetails{
+				ID: "555133744", Aliases: []string{"prod-staging"},
+			},
+		},
+		{
+			name: "override-term",
+			details: AccountDetails{
+				ID: "555133744", Aliases: []string{"critical-sandbox"},
+			},
+			wantErr: "blocklisted keyword 'critical'",
+		},
+		{
+			name: "override-regex",
+			details: AccountDetails{
+				ID: "555133744", Aliases: []string{"Core-Network"},
+			},
+			wantErr: "blocklisted keyword '(?i)^core'",
+		},
+		{
+			name: "override-tag-key-only",
+			details: AccountDetails{
+				ID: "555133745", Aliases: []string{"sandbox"}, Name: "Sandbox",
+				Tags: map[string]string{"DoNotNuke": "yes", "Environment": "production"},
+			},
+			wantErr: "tag 'DoNotNuke'",
+		},
+		{
+			name: "override-tag-no-match",
+			details: AccountDetails{
+				ID: "555133745", Aliases: []string{"sandbox"}, Name: "Sandbox",
+				Tags: map[string]string{"Environment": "production"},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := config.ValidateAccountDetails(&tc.details, tc.skipAliasChecks)
+			if tc.wantErr != "" {
+				assert.ErrorContains(t, err, tc.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestConfig_BlocklistPolicyInvalidRegex(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/blocklist.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	config.BlocklistRegex = append(config.BlocklistRegex, "[invalid")
+
+	_, err = config.BlocklistPolicy("555133742")
+	assert.ErrorContains(t, err, "invalid blocklist regex")
+
+	policy, err := config.BlocklistPolicy("555133744")
+	assert.NoError(t, err)
+	assert.False(t, policy.RequiresOrganizationDetails())
+}
@@ -0,0 +1,416 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+)
+
+// LintSeverity is the severity of a lint rule
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"
+	LintSeverityWarning LintSeverity = "warning"
+	LintSeverityInfo    LintSeverity = "info"
+)
+
+// Level returns the numeric level of the severity, higher is more severe, unknown severities return -1
+func (s LintSeverity) Level() int {
+	switch s {
+	case LintSeverityError:
+		return 2
+	case LintSeverityWarning:
+		return 1
+	case LintSeverityInfo:
+		return 0
+	default:
+		return -1
+	}
+}
+
+// LintRule is a single check that is run against the configuration
+type LintRule struct {
+	ID          string
+	Severity    LintSeverity
+	Description string
+
+	check func(l *linter)
+}
+
+// LintIssue is a single problem found in the configuration by a lint rule
+type LintIssue struct {
+	Rule     string
+	Severity LintSeverity
+	Message  string
+	Line     int
+}
+
+// String returns the issue in a human readable format
+func (i *LintIssue) String() string {
+	if i.Line > 0 {
+		return fmt.Sprintf("line %d: %s [%s] %s", i.Line, i.Severity, i.Rule, i.Message)
+	}
+
+	return fmt.Sprintf("%s [%s] %s", i.Severity, i.Rule, i.Message)
+}
+
+// LintRules is the list of all lint rules, ordered by ID
+var LintRules = []LintRule{
+	{
+		ID:          "account-no-filters",
+		Severity:    LintSeverityWarning,
+		Description: "account has no filters or presets, every resource in the account will be removed",
+		check:       lintAccountNoFilters,
+	},
+	{
+		ID:          "all-regions-broad-includes",
+		Severity:    LintSeverityWarning,
+		Description: "the all region is used without limiting the resource types with includes",
+		check:       lintAllRegionsBroadIncludes,
+	},
+	{
+		ID:          "bypass-account-not-configured",
+		Severity:    LintSeverityWarning,
+		Description: "an account in bypass-alias-check-accounts is not configured in accounts",
+		check:       lintBypassAccountNotConfigured,
+	},
+	{
+		ID:          "filter-matches-all",
+		Severity:    LintSeverityError,
+		Description: "a filter matches any value, the resource type is either never or always removed",
+		check:       lintFilterMatchesAll,
+	},
+	{
+		ID:          "invert-date-filter",
+		Severity:    LintSeverityWarning,
+		Description: "invert is used on a date filter, the reversed comparison also matches resources without the date",
+		check:       lintInvertDateFilter,
+	},
+	{
+		ID:          "unused-preset",
+		Severity:    LintSeverityInfo,
+		Description: "a preset is defined but not referenced by any account or region",
+		check:       lintUnusedPreset,
+	},
+}
+
+// lintProbes are values used to decide if a filter matches any value
+var lintProbes = []string{"", "0", "aws-nuke", "arn:aws:iam::000000000000:role/Some Role", "ÜNICODE\n"}
+
+// lintDirective matches the inline comment used to suppress lint rules
+var lintDirective = regexp.MustCompile(`nuke-lint:\s*(disable|disable-file)=([\w,-]+)`)
+
+type linter struct {
+	config *Config
+	root   *yaml.Node
+	rule   *LintRule
+	issues []LintIssue
+}
+
+func (l *linter) report(node *yaml.Node, format string, args ...interface{}) {
+	line := 0
+	if node != nil {
+		line = node.Line
+	}
+
+	l.issues = append(l.issues, LintIssue{
+		Rule:     l.rule.ID,
+		Severity: l.rule.Severity,
+		Message:  fmt.Sprintf(format, args...),
+		Line:     line,
+	})
+}
+
+// Lint checks the configuration for patterns that are valid, but dangerous or ineffective. Rules may be suppressed
+// with a `# nuke-lint: disable=<rule>` comment on the line of the issue or the line above it, or for the whole file
+// with a `# nuke-lint: disable-file=<rule>` comment. Multiple rules are separated by commas, `all` matches any rule.
+func (c *Config) Lint() []LintIssue {
+	l := &linter{
+		config: c,
+		root:   c.loadNode(),
+	}
+
+	for i := range LintRules {
+		l.rule = &LintRules[i]
+		l.rule.check(l)
+	}
+
+	var lines []string
+	if c.Path != "" {
+		if raw, err := os.ReadFile(c.Path); err == nil {
+			lines = strings.Split(string(raw), "\n")
+		}
+	}
+
+	issues := make([]LintIssue, 0, len(l.issues))
+	for _, issue := range l.issues {
+		if !lintSuppressed(lines, issue) {
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Line < issues[j].Line
+	})
+
+	return issues
+}
+
+// lintSuppressed returns true if the issue is suppressed by a directive in the file
+func lintSuppressed(lines []string, issue LintIssue) bool {
+	for i, line := range lines {
+		for _, match := range lintDirective.FindAllStringSubmatch(line, -1) {
+			rules := strings.Split(match[2], ",")
+			if !slices.Contains(rules, issue.Rule) && !slices.Contains(rules, "all") {
+				continue
+			}
+
+			if match[1] == "disable-file" {
+				return true
+			}
+
+			// Note: lines are 1-indexed in the yaml nodes, the directive may be on the line of the issue or on a
+			// comment only line directly above it
+			lineNumber := i + 1
+			if lineNumber == issue.Line {
+				return true
+			}
+
+			if lineNumber == issue.Line-1 && strings.HasPrefix(strings.TrimSpace(line), "#") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// sortedAccountIDs returns the account IDs in sorted order for stable output
+func (c *Config) sortedAccountIDs() []string {
+	ids := make([]string, 0, len(c.Accounts))
+	for id := range c.Accounts {
+		ids = append(ids, id)
+	}
+
+	slices.Sort(ids)
+
+	return ids
+}
+
+// lintFilterSources returns every filter defined in the configuration along with where it was defined
+func (l *linter) lintFilterSources() []FilterSource {
+	sources := FilterSources{}
+
+	presetNames := make([]string, 0, len(l.config.Presets))
+	for name := range l.config.Presets {
+		presetNames = append(presetNames, name)
+	}
+	slices.Sort(presetNames)
+
+	for _, name := range presetNames {
+		l.config.appendSources(sources, l.config.Presets[name].Filters, fmt.Sprintf("preset:%s", name),
+			l.root, "presets", name, "filters")
+	}
+
+	for _, id := range l.config.sortedAccountIDs() {
+		if account := l.config.Accounts[id]; account != nil {
+			l.config.appendSources(sources, account.Filters, fmt.Sprintf("account:%s", id),
+				l.root, "accounts", id, "filters")
+		}
+
+		extension := l.config.AccountExtensions[id]
+		if extension == nil {
+			continue
+		}
+
+		for region, regionConfig := range extension.Regions {
+			if regionConfig == nil {
+				continue
+			}
+
+			l.config.appendSources(sources, regionConfig.Filters, fmt.Sprintf("account:%s/region:%s", id, region),
+				l.root, "accounts", id, "regions", region, "filters")
+		}
+	}
+
+	resourceTypes := make([]string, 0, len(sources))
+	for resourceType := range sources {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	slices.Sort(resourceTypes)
+
+	var all []FilterSource
+	for _, resourceType := range resourceTypes {
+		all = append(all, sources[resourceType]...)
+	}
+
+	return all
+}
+
+// filterNode returns a node with the line of the filter, so issues can be reported against it
+func filterNode(source *FilterSource) *yaml.Node {
+	return &yaml.Node{Line: source.Line}
+}
+
+func lintAccountNoFilters(l *linter) {
+	for _, id := range l.config.sortedAccountIDs() {
+		account := l.config.Accounts[id]
+		if account != nil && (len(account.Filters) > 0 || len(account.Presets) > 0) {
+			continue
+		}
+
+		if extension := l.config.AccountExtensions[id]; extension != nil {
+			scoped := false
+			for _, regionConfig := range extension.Regions {
+				if regionConfig != nil && (len(regionConfig.Filters) > 0 || len(regionConfig.Presets) > 0) {
+					scoped = true
+				}
+			}
+
+			if scoped {
+				continue
+			}
+		}
+
+		keyNode, _ := mappingEntry(mappingValue(l.root, "accounts"), id)
+		l.report(keyNode, "account %s has no filters or presets, every resource in the account will be removed", id)
+	}
+}
+
+func lintAllRegionsBroadIncludes(l *linter) {
+	if !slices.Contains(l.config.Regions, "all") {
+		return
+	}
+
+	regionsNode := mappingValue(l.root, "regions")
+
+	if len(l.config.ResourceTypes.GetIncludes()) > 0 {
+		return
+	}
+
+	var accounts []string
+	for _, id := range l.config.sortedAccountIDs() {
+		account := l.config.Accounts[id]
+		if account != nil && len(account.ResourceTypes.GetIncludes()) > 0 {
+			continue
+		}
+
+		accounts = append(accounts, id)
+	}
+
+	if len(accounts) > 0 {
+		l.report(regionsNode, "regions contains all and the resource types are not limited with includes for "+
+			"accounts: %s", strings.Join(accounts, ", "))
+	}
+}
+
+func lintBypassAccountNotConfigured(l *linter) {
+	bypassNode := mappingValue(l.root, "bypass-alias-check-accounts")
+
+	for i, id := range l.config.BypassAliasCheckAccounts {
+		if _, ok := l.config.Accounts[id]; ok {
+			continue
+		}
+
+		var node *yaml.Node
+		if bypassNode != nil && bypassNode.Kind == yaml.SequenceNode && i < len(bypassNode.Content) {
+			node = bypassNode.Content[i]
+		}
+
+		l.report(node, "account %s is in bypass-alias-check-accounts, but is not configured in accounts", id)
+	}
+}
+
+func lintFilterMatchesAll(l *linter) {
+	for _, source := range l.lintFilterSources() {
+		if source.Type == filter.DateOlderThan || source.Type == filter.DateOlderThanNow {
+			continue
+		}
+
+		matchesAll := true
+		for _, probe := range lintProbes {
+			matched, err := source.Match(probe)
+			if err != nil || !matched {
+				matchesAll = false
+				break
+			}
+		}
+
+		if !matchesAll {
+			continue
+		}
+
+		if source.Invert {
+			l.report(filterNode(&source), "%s filter %q matches any value and is inverted, it never matches (%s)",
+				source.Type, source.Value, source.String())
+			continue
+		}
+
+		l.report(filterNode(&source), "%s filter %q matches any value, %s resources are never removed (%s)",
+			source.Type, source.Value, source.ResourceType, source.String())
+	}
+}
+
+func lintInvertDateFilter(l *linter) {
+	for _, source := range l.lintFilterSources() {
+		if !source.Invert {
+			continue
+		}
+
+		if source.Type != filter.DateOlderThan && source.Type != filter.DateOlderThanNow {
+			continue
+		}
+
+		l.report(filterNode(&source), "invert is used on %s filter for property %s, the comparison is reversed and "+
+			"resources without the property always match (%s)", source.Type, source.Property, source.String())
+	}
+}
+
+func lintUnusedPreset(l *linter) {
+	used := map[string]bool{}
+
+	for id, account := range l.config.Accounts {
+		if account != nil {
+			for _, name := range account.Presets {
+				used[name] = true
+			}
+		}
+
+		if extension := l.config.AccountExtensions[id]; extension != nil {
+			for _, regionConfig := range extension.Regions {
+				if regionConfig == nil {
+					continue
+				}
+
+				for _, name := range regionConfig.Presets {
+					used[name] = true
+				}
+			}
+		}
+	}
+
+	presetsNode := mappingValue(l.root, "presets")
+
+	names := make([]string, 0, len(l.config.Presets))
+	for name := range l.config.Presets {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		if used[name] {
+			continue
+		}
+
+		keyNode, _ := mappingEntry(presetsNode, name)
+		l.report(keyNode, "preset %s is defined but not referenced by any account or region", name)
+	}
+}
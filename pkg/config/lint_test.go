@@ -0,0 +1,74 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+func TestConfig_Lint(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/lint.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type result struct {
+		Rule     string
+		Severity LintSeverity
+		Line     int
+	}
+
+	var got []result
+	for _, issue := range config.Lint() {
+		got = append(got, result{Rule: issue.Rule, Severity: issue.Severity, Line: issue.Line})
+	}
+
+	assert.Equal(t, []result{
+		{Rule: "all-regions-broad-includes", Severity: LintSeverityWarning, Line: 3},
+		{Rule: "bypass-account-not-configured", Severity: LintSeverityWarning, Line: 10},
+		{Rule: "filter-matches-all", Severity: LintSeverityError, Line: 18},
+		{Rule: "invert-date-filter", Severity: LintSeverityWarning, Line: 21},
+		{Rule: "account-no-filters", Severity: LintSeverityWarning, Line: 25},
+		{Rule: "unused-preset", Severity: LintSeverityInfo, Line: 35},
+	}, got)
+}
+
+func TestLintSuppressed(t *testing.T) {
+	lines := []string{
+		"# nuke-lint: disable-file=unused-preset",
+		"accounts:",
+		"  # nuke-lint: disable=account-no-filters,filter-matches-all",
+		"  123: {}",
+		"  456: {} # nuke-lint: disable=all",
+		"  789: {}",
+	}
+
+	cases := []struct {
+		name       string
+		issue      LintIssue
+		suppressed bool
+	}{
+		{name: "file", issue: LintIssue{Rule: "unused-preset", Line: 30}, suppressed: true},
+		{name: "line-above", issue: LintIssue{Rule: "account-no-filters", Line: 4}, suppressed: true},
+		{name: "line-above-other-rule", issue: LintIssue{Rule: "unused", Line: 4}, suppressed: false},
+		{name: "same-line-all", issue: LintIssue{Rule: "account-no-filters", Line: 5}, suppressed: true},
+		{name: "trailing-does-not-apply-below", issue: LintIssue{Rule: "account-no-filters", Line: 6}, suppressed: false},
+		{name: "no-line", issue: LintIssue{Rule: "account-no-filters"}, suppressed: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.suppressed, lintSuppressed(lines, tc.issue))
+		})
+	}
+}
+
+func TestLintSeverity_Level(t *testing.T) {
+	assert.Greater(t, LintSeverityError.Level(), LintSeverityWarning.Level())
+	assert.Greater(t, LintSeverityWarning.Level(), LintSeverityInfo.Level())
+	assert.Equal(t, -1, LintSeverity("unknown").Level())
+}
@@ -0,0 +1,372 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MigrateOptions are the options used to migrate a legacy configuration.
+type MigrateOptions struct {
+	// Deprecations is a map of deprecated resource types to their replacements, usually populated from
+	// registry.GetDeprecatedResourceTypeMapping()
+	Deprecations map[string]string
+
+	// Properties returns the known properties for a resource type, it is used to report filters referencing
+	// properties that no longer exist. If it is nil, or returns an empty map, no properties are checked.
+	Properties func(resourceType string) map[string]string
+}
+
+// MigrateReport is the collection of changes performed and issues found while migrating a legacy configuration.
+type MigrateReport struct {
+	// Changes is a list of the changes that were made to the configuration
+	Changes []string
+
+	// Warnings is a list of issues that could not be migrated automatically and need to be reviewed
+	Warnings []string
+}
+
+func (r *MigrateReport) change(node *yaml.Node, format string, args ...interface{}) {
+	r.Changes = append(r.Changes, fmt.Sprintf("line %d: %s", node.Line, fmt.Sprintf(format, args...)))
+}
+
+func (r *MigrateReport) warn(node *yaml.Node, format string, args ...interface{}) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf("line %d: %s", node.Line, fmt.Sprintf(format, args...)))
+}
+
+// featureFlagSettings maps the legacy `feature-flags` keys to the resource type and setting that replaces them.
+var featureFlagSettings = map[string][2]string{
+	"disable-ec2-instance-stop-protection":            {"EC2Instance", "DisableStopProtection"},
+	"force-delete-lightsail-addons":                   {"LightsailInstance", "ForceDeleteAddOns"},
+	"disable-deletion-protection.RDSInstance":         {"RDSInstance", "DisableDeletionProtection"},
+	"disable-deletion-protection.EC2Instance":         {"EC2Instance", "DisableDeletionProtection"},
+	"disable-deletion-protection.CloudformationStack": {"CloudFormationStack", "DisableDeletionProtection"},
+	"disable-deletion-protection.ELBv2":               {"ELBv2", "DisableDeletionProtection"},
+	"disable-deletion-protection.QLDBLedger":          {"QLDBLedger", "DisableDeletionProtection"},
+}
+
+// Migrate rewrites a legacy (rebuy-de/aws-nuke v2) configuration into the current format. The configuration is
+// modified at the YAML node level so that comments and ordering are preserved where possible. The following is
+// migrated:
+//
+//   - `account-blacklist` and `account-blocklist` are renamed to `blocklist`
+//   - `targets` is renamed to `includes`
+//   - deprecated resource type names are replaced in filters, resource types and settings
+//   - `feature-flags` is converted into `settings`
+//
+// Filters referencing properties that are unknown to the resource type are reported, but not changed.
+func Migrate(raw []byte, opts MigrateOptions) ([]byte, *MigrateReport, error) {
+	report := &MigrateReport{}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, nil, err
+	}
+
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil, fmt.Errorf("configuration must be a yaml mapping")
+	}
+
+	m := &migrator{opts: opts, report: report}
+	root := doc.Content[0]
+
+	m.migrateBlocklist(root)
+	m.migrateResourceTypes(mappingValue(root, "resource-types"))
+	m.migrateFeatureFlags(root)
+	m.migrateSettings(mappingValue(root, "settings"))
+
+	forEachMapping(mappingValue(root, "presets"), func(_, preset *yaml.Node) {
+		m.migrateFilters(mappingValue(preset, "filters"))
+	})
+
+	forEachMapping(mappingValue(root, "accounts"), func(_, account *yaml.Node) {
+		m.migrateFilters(mappingValue(account, "filters"))
+		m.migrateResourceTypes(mappingValue(account, "resource-types"))
+
+		forEachMapping(mappingValue(account, "regions"), func(_, region *yaml.Node) {
+			m.migrateFilters(mappingValue(region, "filters"))
+			m.migrateSettings(mappingValue(region, "settings"))
+		})
+	})
+
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, nil, err
+	}
+
+	return buf.Bytes(), report, nil
+}
+
+type migrator struct {
+	opts   MigrateOptions
+	report *MigrateReport
+}
+
+// migrateBlocklist merges the deprecated blocklist keys into `blocklist`
+func (m *migrator) migrateBlocklist(root *yaml.Node) {
+	for _, legacyKey := range []string{"account-blacklist", "account-blocklist"} {
+		keyNode, valueNode := mappingEntry(root, legacyKey)
+		if keyNode == nil {
+			continue
+		}
+
+		_, blocklist := mappingEntry(root, "blocklist")
+		if blocklist == nil {
+			keyNode.Value = "blocklist"
+			m.report.change(keyNode, "renamed '%s' to 'blocklist'", legacyKey)
+			continue
+		}
+
+		blocklist.Content = append(blocklist.Content, valueNode.Content...)
+		removeMappingEntry(root, legacyKey)
+		m.report.change(keyNode, "merged '%s' into 'blocklist'", legacyKey)
+	}
+}
+
+// migrateResourceTypes renames `targets` to `includes` and replaces deprecated resource type names
+func (m *migrator) migrateResourceTypes(resourceTypes *yaml.Node) {
+	if resourceTypes == nil || resourceTypes.Kind != yaml.MappingNode {
+		return
+	}
+
+	if keyNode, targets := mappingEntry(resourceTypes, "targets"); keyNode != nil {
+		_, includes := mappingEntry(resourceTypes, "includes")
+		if includes == nil {
+			keyNode.Value = "includes"
+			m.report.change(keyNode, "renamed 'targets' to 'includes'")
+		} else {
+			includes.Content = append(includes.Content, targets.Content...)
+			removeMappingEntry(resourceTypes, "targets")
+			m.report.change(keyNode, "merged 'targets' into 'includes'")
+		}
+	}
+
+	for _, key := range []string{"includes", "excludes", "alternatives", "cloud-control"} {
+		list := mappingValue(resourceTypes, key)
+		if list == nil || list.Kind != yaml.SequenceNode {
+			continue
+		}
+
+		for _, item := range list.Content {
+			if replacement, ok := m.opts.Deprecations[item.Value]; ok {
+				m.report.change(item, "renamed deprecated resource type '%s' to '%s' in '%s'",
+					item.Value, replacement, key)
+				item.Value = replacement
+			}
+		}
+	}
+}
+
+// migrateFilters replaces deprecated resource type names and reports unknown properties
+func (m *migrator) migrateFilters(filters *yaml.Node) {
+	if filters == nil || filters.Kind != yaml.MappingNode {
+		return
+	}
+
+	m.renameResourceTypeKeys(filters, "filters")
+
+	forEachMapping(filters, func(key, value *yaml.Node) {
+		m.checkFilterProperties(key.Value, value)
+	})
+}
+
+// migrateSettings replaces deprecated resource type names in settings
+func (m *migrator) migrateSettings(settings *yaml.Node) {
+	if settings == nil || settings.Kind != yaml.MappingNode {
+		return
+	}
+
+	m.renameResourceTypeKeys(settings, "settings")
+}
+
+// renameResourceTypeKeys renames any deprecated resource type keys of a mapping, merging the values if the
+// replacement is already present.
+func (m *migrator) renameResourceTypeKeys(mapping *yaml.Node, section string) {
+	for i := 0; i < len(mapping.Content); i += 2 {
+		keyNode := mapping.Content[i]
+
+		replacement, ok := m.opts.Deprecations[keyNode.Value]
+		if !ok {
+			continue
+		}
+
+		existing := mappingValue(mapping, replacement)
+		if existing == nil {
+			m.report.change(keyNode, "renamed deprecated resource type '%s' to '%s' in '%s'",
+				keyNode.Value, replacement, section)
+			keyNode.Value = replacement
+			continue
+		}
+
+		value := mapping.Content[i+1]
+		if existing.Kind != value.Kind {
+			m.report.warn(keyNode, "deprecated resource type '%s' and '%s' are both defined in '%s', "+
+				"they must be merged manually", keyNode.Value, replacement, section)
+			continue
+		}
+
+		existing.Content = append(existing.Content, value.Content...)
+		m.report.change(keyNode, "merged deprecated resource type '%s' into '%s' in '%s'",
+			keyNode.Value, replacement, section)
+		mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		i -= 2
+	}
+}
+
+// checkFilterProperties reports filters that reference a property that the resource type does not have
+func (m *migrator) checkFilterProperties(resourceType string, filters *yaml.Node) {
+	if m.opts.Properties == nil || filters.Kind != yaml.SequenceNode {
+		return
+	}
+
+	known := m.opts.Properties(resourceType)
+	if len(known) == 0 {
+		return
+	}
+
+	for _, f := range filters.Content {
+		property := mappingValue(f, "property")
+		if property == nil || property.Value == "" || strings.HasPrefix(property.Value, "tag:") {
+			continue
+		}
+
+		if _, ok := known[property.Value]; ok {
+			continue
+		}
+
+		suggestion := ""
+		for name := range known {
+			if strings.EqualFold(name, property.Value) {
+				suggestion = fmt.Sprintf(", did you mean '%s'?", name)
+				break
+			}
+		}
+
+		m.report.warn(property, "property '%s' is not known for resource type '%s', it may have been renamed%s",
+			property.Value, resourceType, suggestion)
+	}
+}
+
+// migrateFeatureFlags converts the `feature-flags` block into `settings`
+func (m *migrator) migrateFeatureFlags(root *yaml.Node) {
+	keyNode, flags := mappingEntry(root, "feature-flags")
+	if keyNode == nil {
+		return
+	}
+
+	enabled := map[string][]string{}
+	var resourceTypes []string
+
+	enable := func(flag string, node *yaml.Node) {
+		mapping, ok := featureFlagSettings[flag]
+		if !ok {
+			m.report.warn(node, "unknown feature flag '%s', it must be migrated manually", flag)
+			return
+		}
+
+		if node.Value != "true" {
+			return
+		}
+
+		if _, ok := enabled[mapping[0]]; !ok {
+			resourceTypes = append(resourceTypes, mapping[0])
+		}
+
+		enabled[mapping[0]] = append(enabled[mapping[0]], mapping[1])
+	}
+
+	forEachMapping(flags, func(key, value *yaml.Node) {
+		if value.Kind == yaml.MappingNode {
+			forEachMapping(value, func(subKey, subValue *yaml.Node) {
+				enable(key.Value+"."+subKey.Value, subValue)
+			})
+			return
+		}
+
+		enable(key.Value, value)
+	})
+
+	settings := mappingValue(root, "settings")
+	if settings == nil {
+		settings = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		keyNode.Value = "settings"
+		idx := slices.Index(root.Content, keyNode)
+		root.Content[idx+1] = settings
+	} else {
+		removeMappingEntry(root, "feature-flags")
+	}
+
+	for _, resourceType := range resourceTypes {
+		setting := mappingValue(settings, resourceType)
+		if setting == nil {
+			setting = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			settings.Content = append(settings.Content, scalarNode(resourceType), setting)
+		}
+
+		for _, name := range enabled[resourceType] {
+			if mappingValue(setting, name) != nil {
+				continue
+			}
+
+			setting.Content = append(setting.Content, scalarNode(name), &yaml.Node{
+				Kind: yaml.ScalarNode, Tag: "!!bool", Value: "true",
+			})
+		}
+	}
+
+	m.report.change(keyNode, "converted 'feature-flags' to 'settings'")
+}
+
+// mappingEntry returns the key and value node of a mapping for the given key
+func mappingEntry(mapping *yaml.Node, key string) (keyNode, valueNode *yaml.Node) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return nil, nil
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i], mapping.Content[i+1]
+		}
+	}
+
+	return nil, nil
+}
+
+// mappingValue returns the value node of a mapping for the given key
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	_, value := mappingEntry(mapping, key)
+	return value
+}
+
+// removeMappingEntry removes the key and value from a mapping
+func removeMappingEntry(mapping *yaml.Node, key string) {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// forEachMapping calls fn for each key and value of a mapping node
+func forEachMapping(mapping *yaml.Node, fn func(key, value *yaml.Node)) {
+	if mapping == nil || mapping.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		fn(mapping.Content[i], mapping.Content[i+1])
+	}
+}
+
+func scalarNode(value string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+}
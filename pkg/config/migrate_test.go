@@ -0,0 +1,102 @@
+package config
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+func TestMigrate(t *testing.T) {
+	raw, err := os.ReadFile("testdata/legacy-v2.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	migrated, report, err := Migrate(raw, MigrateOptions{
+		Deprecations: map[string]string{
+			"IamRole": "IAMRole",
+		},
+		Properties: func(resourceType string) map[string]string {
+			if resourceType == "S3Bucket" {
+				return map[string]string{"Name": "", "CreationDate": ""}
+			}
+			return nil
+		},
+	})
+	assert.NoError(t, err)
+
+	// Note: comments must be preserved
+	assert.Contains(t, string(migrated), "# the legacy blocklist")
+	assert.Contains(t, string(migrated), "# keep the admin role")
+	assert.Contains(t, string(migrated), "# production")
+
+	assert.NotContains(t, string(migrated), "account-blacklist")
+	assert.NotContains(t, string(migrated), "feature-flags")
+	assert.NotContains(t, string(migrated), "targets")
+	assert.NotContains(t, string(migrated), "IamRole")
+
+	var parsed struct {
+		libconfig.Config `yaml:",inline"`
+		FeatureFlags     map[string]interface{} `yaml:"feature-flags"`
+	}
+	assert.NoError(t, yaml.Unmarshal(migrated, &parsed))
+
+	assert.Equal(t, []string{"1234567890"}, parsed.Blocklist)
+	assert.Equal(t, []string{"IAMRole", "S3Bucket"}, []string(parsed.ResourceTypes.Includes))
+	assert.Len(t, parsed.Accounts["555133742"].Filters["IAMRole"], 1)
+	assert.Len(t, parsed.Presets["common"].Filters["IAMRole"], 2)
+
+	assert.Equal(t, true, parsed.Settings.Get("EC2Instance").Get("DisableStopProtection"))
+	assert.Equal(t, true, parsed.Settings.Get("RDSInstance").Get("DisableDeletionProtection"))
+	assert.Equal(t, true, parsed.Settings.Get("CloudFormationStack").Get("DisableDeletionProtection"))
+	assert.Nil(t, parsed.Settings.Get("ELBv2").Get("DisableDeletionProtection"))
+
+	assert.Len(t, report.Warnings, 1)
+	assert.Contains(t, report.Warnings[0], "property 'name' is not known for resource type 'S3Bucket'")
+	assert.Contains(t, report.Warnings[0], "did you mean 'Name'?")
+	assert.NotEmpty(t, report.Changes)
+}
+
+func TestMigrate_ExistingKeys(t *testing.T) {
+	raw := []byte(`
+blocklist:
+  - "111111111111"
+account-blocklist:
+  - "222222222222"
+settings:
+  EC2Instance:
+    DisableStopProtection: true
+feature-flags:
+  disable-ec2-instance-stop-protection: true
+  force-delete-lightsail-addons: true
+resource-types:
+  includes:
+    - S3Bucket
+  targets:
+    - S3Object
+`)
+
+	migrated, report, err := Migrate(raw, MigrateOptions{})
+	assert.NoError(t, err)
+	assert.Empty(t, report.Warnings)
+
+	var parsed libconfig.Config
+	assert.NoError(t, yaml.Unmarshal(migrated, &parsed))
+
+	assert.Equal(t, []string{"111111111111", "222222222222"}, parsed.Blocklist)
+	assert.Equal(t, []string{"S3Bucket", "S3Object"}, []string(parsed.ResourceTypes.Includes))
+	assert.Equal(t, true, parsed.Settings.Get("LightsailInstance").Get("ForceDeleteAddOns"))
+	assert.Equal(t, true, parsed.Settings.Get("EC2Instance").Get("DisableStopProtection"))
+}
+
+func TestMigrate_Invalid(t *testing.T) {
+	_, _, err := Migrate([]byte(`- not a mapping`), MigrateOptions{})
+	assert.Error(t, err)
+
+	_, _, err = Migrate([]byte(`{{{`), MigrateOptions{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+)
+
+// PolicyExportResourceTypes is the list of resource types whose policies can be exported before removal
+var PolicyExportResourceTypes = []string{
+	"IAMGroup",
+	"IAMPolicy",
+	"IAMRole",
+	"IAMUser",
+	"KMSKey",
+	"LambdaFunction",
+	"S3Bucket",
+	"SNSTopic",
+	"SQSQueue",
+}
+
+// PolicyExport is the configuration for exporting IAM and resource policies to a local directory before the
+// resources are removed.
+type PolicyExport struct {
+	// Directory is the local directory the policies are written to as JSON.
+	Directory string `yaml:"directory"`
+
+	// ResourceTypes limits the export to the given resource types, if it is empty all supported types are exported.
+	ResourceTypes []string `yaml:"resource-types"`
+}
+
+// Validate checks that the directory is set and that the resource types support exporting policies.
+func (p *PolicyExport) Validate() error {
+	if p.Directory == "" {
+		return fmt.Errorf("policy-export: directory is required")
+	}
+
+	for _, resourceType := range p.ResourceTypes {
+		if !slices.Contains(PolicyExportResourceTypes, resourceType) {
+			return fmt.Errorf("policy-export: resource type %s does not support exporting policies", resourceType)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPolicyExport_Validate(t *testing.T) {
+	assert.NoError(t, (&PolicyExport{Directory: "policies"}).Validate())
+	assert.NoError(t, (&PolicyExport{Directory: "policies", ResourceTypes: []string{"IAMRole"}}).Validate())
+	assert.EqualError(t, (&PolicyExport{}).Validate(), "policy-export: directory is required")
+	assert.EqualError(t, (&PolicyExport{Directory: "policies", ResourceTypes: []string{"EC2Instance"}}).Validate(),
+		"policy-export: resource type EC2Instance does not support exporting policies")
+}
@@ -0,0 +1,264 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+	"github.com/ekristen/libnuke/pkg/filter"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+// FilterSource is a filter along with where it was defined in the configuration.
+type FilterSource struct {
+	filter.Filter
+
+	// ResourceType is the resource type the filter was defined under, this can be `__global__`
+	ResourceType string
+
+	// Source describes the configuration block the filter was defined in, e.g. `account`, `preset:terraform`,
+	// `region:eu-west-1` or `region:eu-west-1/preset:network`
+	Source string
+
+	// Index is the position of the filter in the list of filters for the resource type in the source block
+	Index int
+
+	// File is the configuration file the filter was defined in
+	File string
+
+	// Line is the line number in the configuration file, it is zero if it could not be determined
+	Line int
+}
+
+// Global returns true if the filter is a global filter that applies to all resource types
+func (f *FilterSource) Global() bool {
+	return f.ResourceType == filter.Global
+}
+
+// String returns a human readable description of where the filter was defined
+func (f *FilterSource) String() string {
+	location := fmt.Sprintf("%s %s[%d]", f.Source, f.ResourceType, f.Index)
+	if f.Line > 0 {
+		location = fmt.Sprintf("%s (%s:%d)", location, filepath.Base(f.File), f.Line)
+	}
+
+	return location
+}
+
+// FilterSources is the collection of filters with their sources, keyed by resource type.
+type FilterSources map[string][]FilterSource
+
+// Get returns the global filters followed by the filters for the resource type, in the same order as the
+// libnuke filter.Filters Get function.
+func (s FilterSources) Get(resourceType string) []FilterSource {
+	var sources []FilterSource
+
+	sources = append(sources, s[filter.Global]...)
+
+	if resourceType != filter.Global {
+		sources = append(sources, s[resourceType]...)
+	}
+
+	return sources
+}
+
+// Filters converts the sources back into the libnuke filters
+func (s FilterSources) Filters() filter.Filters {
+	filters := filter.Filters{}
+	for resourceType, sources := range s {
+		for i := range sources {
+			filters[resourceType] = append(filters[resourceType], sources[i].Filter)
+		}
+	}
+
+	return filters
+}
+
+// FilterEvaluation is the result of evaluating a single filter against a set of properties
+type FilterEvaluation struct {
+	Source   FilterSource
+	Property string
+	Value    string
+	Matched  bool
+	Error    error
+}
+
+// Evaluate evaluates all filters for the resource type against the properties, it returns the result of every
+// filter so that it is possible to explain why a resource is, or is not, filtered.
+func (s FilterSources) Evaluate(resourceType string, props types.Properties) []FilterEvaluation {
+	var evaluations []FilterEvaluation
+
+	for _, source := range s.Get(resourceType) {
+		evaluation := FilterEvaluation{
+			Source:   source,
+			Property: source.Property,
+		}
+
+		if source.Property == "" {
+			// Note: the legacy string of a resource is not available offline, fall back to the Name property
+			evaluation.Property = "Name"
+		}
+
+		evaluation.Value = props.Get(evaluation.Property)
+
+		matched, err := source.Match(evaluation.Value)
+		if err != nil {
+			evaluation.Error = err
+		}
+
+		if source.Invert {
+			matched = !matched
+		}
+
+		evaluation.Matched = matched && err == nil
+		evaluations = append(evaluations, evaluation)
+	}
+
+	return evaluations
+}
+
+// RegionFilterSources resolves the same filters as RegionFilters, but records where each filter was defined.
+func (c *Config) RegionFilterSources(accountID, region string) (FilterSources, error) {
+	account, ok := c.Accounts[accountID]
+	if !ok || account == nil {
+		return nil, liberrors.ErrAccountNotConfigured
+	}
+
+	root := c.loadNode()
+	sources := FilterSources{}
+
+	c.appendSources(sources, account.Filters, "account", root, "accounts", accountID, "filters")
+
+	if err := c.appendPresetSources(sources, account.Presets, "", root); err != nil {
+		return nil, err
+	}
+
+	regionConfig := c.GetRegion(accountID, region)
+	if regionConfig == nil {
+		return sources, nil
+	}
+
+	regionSource := fmt.Sprintf("region:%s", region)
+	c.appendSources(sources, regionConfig.Filters, regionSource,
+		root, "accounts", accountID, "regions", region, "filters")
+
+	if err := c.appendPresetSources(sources, regionConfig.Presets, regionSource+"/", root); err != nil {
+		return nil, err
+	}
+
+	return sources, nil
+}
+
+func (c *Config) appendPresetSources(sources FilterSources, presets []string, prefix string, root *yaml.Node) error {
+	for _, presetName := range presets {
+		preset, ok := c.Presets[presetName]
+		if !ok {
+			return liberrors.ErrUnknownPreset(presetName)
+		}
+
+		c.appendSources(sources, preset.Filters, fmt.Sprintf("%spreset:%s", prefix, presetName),
+			root, "presets", presetName, "filters")
+	}
+
+	return nil
+}
+
+// appendSources appends the filters to the sources, looking up the line number of each filter in the yaml node
+// found at the path.
+func (c *Config) appendSources(sources FilterSources, filters filter.Filters, source string, root *yaml.Node,
+	path ...string) {
+	block := lookupNode(root, path...)
+
+	for resourceType, resourceFilters := range filters {
+		list := c.lookupResourceTypeNode(block, resourceType)
+
+		for i, f := range resourceFilters {
+			line := 0
+			if list != nil && list.Kind == yaml.SequenceNode && i < len(list.Content) {
+				line = list.Content[i].Line
+			}
+
+			sources[resourceType] = append(sources[resourceType], FilterSource{
+				Filter:       f,
+				ResourceType: resourceType,
+				Source:       source,
+				Index:        i,
+				File:         c.Path,
+				Line:         line,
+			})
+		}
+	}
+}
+
+// lookupResourceTypeNode returns the node for the resource type, taking into account that the resource type may
+// have been renamed from a deprecated resource type while the configuration was resolved.
+func (c *Config) lookupResourceTypeNode(block *yaml.Node, resourceType string) *yaml.Node {
+	if node := mappingValue(block, resourceType); node != nil {
+		return node
+	}
+
+	for deprecated, replacement := range c.Deprecations {
+		if replacement != resourceType {
+			continue
+		}
+
+		if node := mappingValue(block, deprecated); node != nil {
+			return node
+		}
+	}
+
+	return nil
+}
+
+// loadNode parses the configuration file into a yaml node, it is only used to look up line numbers, so any error
+// results in a nil node and line numbers will be omitted.
+func (c *Config) loadNode() *yaml.Node {
+	if c.Path == "" {
+		return nil
+	}
+
+	raw, err := os.ReadFile(c.Path)
+	if err != nil {
+		return nil
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(raw, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+
+	return doc.Content[0]
+}
+
+// lookupNode walks the mapping nodes following the path of keys
+func lookupNode(node *yaml.Node, path ...string) *yaml.Node {
+	for _, key := range path {
+		if node == nil {
+			return nil
+		}
+
+		node = mappingValue(node, key)
+	}
+
+	return node
+}
+
+// ParseProperties parses a list of key=value pairs into properties, it is used to describe a hypothetical resource.
+func ParseProperties(pairs []string) (types.Properties, error) {
+	props := types.NewProperties()
+
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid property '%s', expected key=value", pair)
+		}
+
+		props.Set(key, value)
+	}
+
+	return props, nil
+}
@@ -0,0 +1,124 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+func TestConfig_RegionFilterSources(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/region-scoped.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := config.RegionFilterSources("555133742", "eu-west-1")
+	assert.NoError(t, err)
+
+	cases := []struct {
+		resourceType string
+		source       string
+		line         int
+		description  string
+	}{
+		{
+			resourceType: "IAMRole",
+			source:       "account",
+			line:         16,
+			description:  "account IAMRole[0] (region-scoped.yaml:16)",
+		},
+		{
+			resourceType: "EC2Instance",
+			source:       "region:eu-west-1",
+			line:         23,
+			description:  "region:eu-west-1 EC2Instance[0] (region-scoped.yaml:23)",
+		},
+		{
+			resourceType: "S3Bucket",
+			source:       "preset:terraform",
+			line:         37,
+			description:  "preset:terraform S3Bucket[0] (region-scoped.yaml:37)",
+		},
+		{
+			resourceType: "EC2VPC",
+			source:       "region:eu-west-1/preset:keep-network",
+			line:         42,
+			description:  "region:eu-west-1/preset:keep-network EC2VPC[0] (region-scoped.yaml:42)",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.resourceType, func(t *testing.T) {
+			got := sources.Get(tc.resourceType)
+			if !assert.Len(t, got, 1) {
+				return
+			}
+
+			assert.Equal(t, tc.source, got[0].Source)
+			assert.Equal(t, 0, got[0].Index)
+			assert.Equal(t, tc.line, got[0].Line)
+			assert.Equal(t, tc.description, got[0].String())
+		})
+	}
+
+	filters, err := config.RegionFilters("555133742", "eu-west-1")
+	assert.NoError(t, err)
+	assert.Equal(t, filters, sources.Filters())
+}
+
+func TestConfig_FiltersKeepsAccountFilters(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/region-scoped.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filters, err := config.Filters("555133742")
+	assert.NoError(t, err)
+	assert.Len(t, filters["S3Bucket"], 1)
+	assert.Len(t, filters["IAMRole"], 1)
+
+	// Note: the presets must not be added to the account filters, otherwise they are reported as account filters
+	assert.NotContains(t, config.Accounts["555133742"].Filters, "S3Bucket")
+
+	sources, err := config.RegionFilterSources("555133742", "us-east-1")
+	assert.NoError(t, err)
+	assert.Len(t, sources.Get("S3Bucket"), 1)
+	assert.Equal(t, "preset:terraform", sources.Get("S3Bucket")[0].Source)
+}
+
+func TestFilterSources_Evaluate(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/region-scoped.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sources, err := config.RegionFilterSources("555133742", "eu-west-1")
+	assert.NoError(t, err)
+
+	props, err := ParseProperties([]string{"tag:Name=bastion", "InstanceType=t3.micro"})
+	assert.NoError(t, err)
+
+	evaluations := sources.Evaluate("EC2Instance", props)
+	assert.Len(t, evaluations, 1)
+	assert.True(t, evaluations[0].Matched)
+	assert.Equal(t, "bastion", evaluations[0].Value)
+	assert.Equal(t, "region:eu-west-1", evaluations[0].Source.Source)
+
+	props, err = ParseProperties([]string{"tag:Name=web"})
+	assert.NoError(t, err)
+
+	evaluations = sources.Evaluate("EC2Instance", props)
+	assert.Len(t, evaluations, 1)
+	assert.False(t, evaluations[0].Matched)
+
+	_, err = ParseProperties([]string{"invalid"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// RemoveTimeout limits how long the removal of a resource may take, from the first removal attempt until the resource
+// is gone. Durations are in the format of Go durations, e.g. `30m` or `1h30m`.
+type RemoveTimeout struct {
+	// Default is the timeout for all resource types that do not have their own timeout, if it is empty these are not
+	// limited.
+	Default string `yaml:"default"`
+
+	// ResourceTypes is the timeout per resource type.
+	ResourceTypes map[string]string `yaml:"resource-types"`
+}
+
+// Timeouts returns the parsed default timeout and the timeouts per resource type.
+func (r *RemoveTimeout) Timeouts() (time.Duration, map[string]time.Duration, error) {
+	var defaultTimeout time.Duration
+	if r.Default != "" {
+		parsed, err := parseTimeout(r.Default)
+		if err != nil {
+			return 0, nil, fmt.Errorf("remove-timeout: default: %w", err)
+		}
+
+		defaultTimeout = parsed
+	}
+
+	timeouts := make(map[string]time.Duration, len(r.ResourceTypes))
+	for resourceType, value := range r.ResourceTypes {
+		parsed, err := parseTimeout(value)
+		if err != nil {
+			return 0, nil, fmt.Errorf("remove-timeout: %s: %w", resourceType, err)
+		}
+
+		timeouts[resourceType] = parsed
+	}
+
+	return defaultTimeout, timeouts, nil
+}
+
+// Validate checks that all timeouts can be parsed.
+func (r *RemoveTimeout) Validate() error {
+	_, _, err := r.Timeouts()
+	return err
+}
+
+func parseTimeout(value string) (time.Duration, error) {
+	timeout, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("timeout must be positive, got %s", value)
+	}
+
+	return timeout, nil
+}
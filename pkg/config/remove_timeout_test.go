@@ -0,0 +1,32 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveTimeout_Timeouts(t *testing.T) {
+	removeTimeout := &RemoveTimeout{
+		Default: "30m",
+		ResourceTypes: map[string]string{
+			"CloudFormationStack": "1h30m",
+		},
+	}
+
+	defaultTimeout, timeouts, err := removeTimeout.Timeouts()
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, defaultTimeout)
+	assert.Equal(t, map[string]time.Duration{"CloudFormationStack": 90 * time.Minute}, timeouts)
+
+	defaultTimeout, timeouts, err = (&RemoveTimeout{}).Timeouts()
+	assert.NoError(t, err)
+	assert.Zero(t, defaultTimeout)
+	assert.Empty(t, timeouts)
+
+	assert.EqualError(t, (&RemoveTimeout{Default: "soon"}).Validate(),
+		`remove-timeout: default: time: invalid duration "soon"`)
+	assert.EqualError(t, (&RemoveTimeout{ResourceTypes: map[string]string{"EKSCluster": "-1m"}}).Validate(),
+		"remove-timeout: EKSCluster: timeout must be positive, got -1m")
+}
@@ -0,0 +1,275 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// scheduleSearchLimit is how far ahead the next opening or closing of a window is searched for
+const scheduleSearchLimit = 366 * 24 * time.Hour
+
+// Schedule is a collection of maintenance windows during which resources are allowed to be removed.
+type Schedule struct {
+	// Timezone is the IANA timezone the windows are evaluated in, e.g. `Europe/Berlin`, defaults to UTC
+	Timezone string `yaml:"timezone"`
+
+	// Windows is a list of cron-like expressions with the fields minute, hour, day of month, month and day of week.
+	// A time is within the schedule if it matches any of the expressions, e.g. `* 9-16 * * mon-fri`.
+	Windows []string `yaml:"windows"`
+
+	// StopAtWindowEnd is a setting that stops triggering new removals once the window closes during a run.
+	StopAtWindowEnd bool `yaml:"stop-at-window-end"`
+}
+
+// GetSchedule returns the schedule for the account, an account level schedule replaces the global schedule. It
+// returns nil if no schedule is configured.
+func (c *Config) GetSchedule(accountID string) *Schedule {
+	if account := c.AccountExtensions[accountID]; account != nil && account.Schedule != nil {
+		return account.Schedule
+	}
+
+	return c.Schedule
+}
+
+// Validate checks that the timezone and all windows can be parsed.
+func (s *Schedule) Validate() error {
+	if len(s.Windows) == 0 {
+		return fmt.Errorf("schedule: at least one window is required")
+	}
+
+	_, _, err := s.parse()
+	return err
+}
+
+// Allowed returns true if the time is within any of the windows
+func (s *Schedule) Allowed(t time.Time) (bool, error) {
+	loc, windows, err := s.parse()
+	if err != nil {
+		return false, err
+	}
+
+	return windowsMatch(windows, t.In(loc)), nil
+}
+
+// Check returns an error describing when the next window opens if the time is not within the schedule.
+func (s *Schedule) Check(t time.Time) error {
+	allowed, err := s.Allowed(t)
+	if err != nil {
+		return err
+	}
+
+	if allowed {
+		return nil
+	}
+
+	next, found := s.next(t, true)
+	if !found {
+		return fmt.Errorf("outside of the maintenance window, no window opens within the next year")
+	}
+
+	return fmt.Errorf("outside of the maintenance window, the next window opens at %s",
+		next.Format(time.RFC3339))
+}
+
+// WindowEnd returns when the window that the time is in closes, it returns false if the time is not within the
+// schedule or the window does not close within the next year.
+func (s *Schedule) WindowEnd(t time.Time) (time.Time, bool) {
+	if allowed, err := s.Allowed(t); err != nil || !allowed {
+		return time.Time{}, false
+	}
+
+	return s.next(t, false)
+}
+
+// next returns the first minute after the time at which the schedule is open, or closed if open is false
+func (s *Schedule) next(t time.Time, open bool) (time.Time, bool) {
+	loc, windows, err := s.parse()
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	start := t.In(loc).Truncate(time.Minute).Add(time.Minute)
+	for candidate := start; candidate.Sub(start) < scheduleSearchLimit; candidate = candidate.Add(time.Minute) {
+		if windowsMatch(windows, candidate) == open {
+			return candidate, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parse parses the timezone and all windows
+func (s *Schedule) parse() (*time.Location, []*cronWindow, error) {
+	loc, err := s.location()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	windows := make([]*cronWindow, 0, len(s.Windows))
+	for _, expr := range s.Windows {
+		window, err := parseCronWindow(expr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		windows = append(windows, window)
+	}
+
+	return loc, windows, nil
+}
+
+// windowsMatch returns true if the time matches any of the windows
+func windowsMatch(windows []*cronWindow, t time.Time) bool {
+	for _, window := range windows {
+		if window.matches(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Schedule) location() (*time.Location, error) {
+	if s.Timezone == "" {
+		return time.UTC, nil
+	}
+
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, fmt.Errorf("schedule: invalid timezone '%s': %w", s.Timezone, err)
+	}
+
+	return loc, nil
+}
+
+// cronWindow is a parsed cron-like expression, each field is the set of allowed values
+type cronWindow struct {
+	minute, hour, dom, month, dow map[int]bool
+	domAny, dowAny                bool
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDays = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+func parseCronWindow(expr string) (*cronWindow, error) {
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("schedule: invalid window '%s': expected 5 fields", expr)
+	}
+
+	window := &cronWindow{
+		domAny: fields[2] == "*",
+		dowAny: fields[4] == "*",
+	}
+
+	var err error
+	if window.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("schedule: invalid window '%s': minute: %w", expr, err)
+	}
+	if window.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("schedule: invalid window '%s': hour: %w", expr, err)
+	}
+	if window.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("schedule: invalid window '%s': day of month: %w", expr, err)
+	}
+	if window.month, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("schedule: invalid window '%s': month: %w", expr, err)
+	}
+	if window.dow, err = parseCronField(fields[4], 0, 7, cronDays); err != nil {
+		return nil, fmt.Errorf("schedule: invalid window '%s': day of week: %w", expr, err)
+	}
+
+	// Note: both 0 and 7 are sunday
+	if window.dow[7] {
+		window.dow[0] = true
+	}
+
+	return window, nil
+}
+
+// matches follows the cron semantics, if both the day of month and day of week are restricted, either may match
+func (w *cronWindow) matches(t time.Time) bool {
+	if !w.minute[t.Minute()] || !w.hour[t.Hour()] || !w.month[int(t.Month())] {
+		return false
+	}
+
+	domMatch := w.dom[t.Day()]
+	dowMatch := w.dow[int(t.Weekday())]
+
+	if !w.domAny && !w.dowAny {
+		return domMatch || dowMatch
+	}
+
+	return domMatch && dowMatch
+}
+
+// parseCronField parses a single field supporting `*`, lists, ranges, steps and names
+func parseCronField(field string, lowest, highest int, names map[string]int) (map[int]bool, error) {
+	values := map[int]bool{}
+
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step '%s'", stepPart)
+			}
+		}
+
+		start, end := lowest, highest
+		if rangePart != "*" {
+			startPart, endPart, isRange := strings.Cut(rangePart, "-")
+
+			var err error
+			if start, err = parseCronValue(startPart, lowest, highest, names); err != nil {
+				return nil, err
+			}
+
+			end = start
+			if isRange {
+				if end, err = parseCronValue(endPart, lowest, highest, names); err != nil {
+					return nil, err
+				}
+			} else if hasStep {
+				end = highest
+			}
+
+			if end < start {
+				return nil, fmt.Errorf("invalid range '%s'", rangePart)
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			values[i] = true
+		}
+	}
+
+	return values, nil
+}
+
+func parseCronValue(value string, lowest, highest int, names map[string]int) (int, error) {
+	if i, ok := names[strings.ToLower(value)]; ok {
+		return i, nil
+	}
+
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value '%s'", value)
+	}
+
+	if i < lowest || i > highest {
+		return 0, fmt.Errorf("value %d out of range %d-%d", i, lowest, highest)
+	}
+
+	return i, nil
+}
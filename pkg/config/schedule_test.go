@@ -0,0 +1,92 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+)
+
+func TestSchedule(t *testing.T) {
+	schedule := &Schedule{
+		Timezone: "Europe/Berlin",
+		Windows: []string{
+			"* 9-16 * * mon-fri",
+			"0-29 10 1 jan *",
+		},
+	}
+	assert.NoError(t, schedule.Validate())
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		time    time.Time
+		allowed bool
+	}{
+		{name: "weekday-open", time: time.Date(2024, 6, 3, 9, 0, 0, 0, berlin), allowed: true},
+		{name: "weekday-last-minute", time: time.Date(2024, 6, 3, 16, 59, 0, 0, berlin), allowed: true},
+		{name: "weekday-closed", time: time.Date(2024, 6, 3, 17, 0, 0, 0, berlin), allowed: false},
+		{name: "weekday-utc", time: time.Date(2024, 6, 3, 7, 30, 0, 0, time.UTC), allowed: true},
+		{name: "weekend", time: time.Date(2024, 6, 1, 12, 0, 0, 0, berlin), allowed: false},
+		{name: "new-year-holiday", time: time.Date(2028, 1, 1, 10, 15, 0, 0, berlin), allowed: true},
+		{name: "new-year-holiday-closed", time: time.Date(2028, 1, 1, 10, 30, 0, 0, berlin), allowed: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			allowed, err := schedule.Allowed(tc.time)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.allowed, allowed)
+		})
+	}
+
+	end, ok := schedule.WindowEnd(time.Date(2024, 6, 3, 12, 0, 0, 0, berlin))
+	assert.True(t, ok)
+	assert.True(t, end.Equal(time.Date(2024, 6, 3, 17, 0, 0, 0, berlin)))
+
+	err = schedule.Check(time.Date(2024, 6, 1, 12, 0, 0, 0, berlin))
+	assert.ErrorContains(t, err, "the next window opens at 2024-06-03T09:00:00+02:00")
+	assert.NoError(t, schedule.Check(time.Date(2024, 6, 3, 12, 0, 0, 0, berlin)))
+}
+
+func TestSchedule_Invalid(t *testing.T) {
+	cases := []struct {
+		name     string
+		schedule Schedule
+		wantErr  string
+	}{
+		{name: "no-windows", schedule: Schedule{}, wantErr: "at least one window"},
+		{name: "timezone", schedule: Schedule{Timezone: "Mars/Olympus", Windows: []string{"* * * * *"}}, wantErr: "timezone"},
+		{name: "fields", schedule: Schedule{Windows: []string{"* * *"}}, wantErr: "expected 5 fields"},
+		{name: "range", schedule: Schedule{Windows: []string{"* 17-9 * * *"}}, wantErr: "invalid range"},
+		{name: "value", schedule: Schedule{Windows: []string{"* 24 * * *"}}, wantErr: "out of range"},
+		{name: "name", schedule: Schedule{Windows: []string{"* * * * funday"}}, wantErr: "invalid value"},
+		{name: "step", schedule: Schedule{Windows: []string{"*/0 * * * *"}}, wantErr: "invalid step"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.ErrorContains(t, tc.schedule.Validate(), tc.wantErr)
+		})
+	}
+}
+
+func TestConfig_GetSchedule(t *testing.T) {
+	config, err := New(libconfig.Options{
+		Path: "testdata/schedule.yaml",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "UTC", config.GetSchedule("555133742").Timezone)
+	assert.False(t, config.GetSchedule("555133742").StopAtWindowEnd)
+	assert.Equal(t, "Europe/Berlin", config.GetSchedule("555133743").Timezone)
+	assert.True(t, config.GetSchedule("555133743").StopAtWindowEnd)
+}
@@ -0,0 +1,85 @@
+package docs
+
+import (
+	"strings"
+)
+
+// settingPlaceholder is the text of a generated section that is replaced by the description that is written by hand
+const settingPlaceholder = "There is currently no description for this setting."
+
+// section is a heading of a markdown document along with everything up to the next heading, the first section of a
+// document has no heading
+type section struct {
+	heading string
+	text    string
+}
+
+// splitSections splits the markdown document at its headings, the headings inside code blocks are ignored
+func splitSections(doc string) []section {
+	sections := []section{{}}
+	fenced := false
+
+	for _, line := range strings.SplitAfter(doc, "\n") {
+		trimmed := strings.TrimRight(line, "\n")
+
+		if strings.HasPrefix(strings.TrimSpace(trimmed), "```") {
+			fenced = !fenced
+		}
+
+		if !fenced && strings.HasPrefix(trimmed, "#") {
+			sections = append(sections, section{heading: trimmed})
+		}
+
+		sections[len(sections)-1].text += line
+	}
+
+	return sections
+}
+
+// MergeResource merges the generated docs of a resource into its existing docs. The generated sections replace the
+// sections of the existing docs, except for the sections whose generated text is a placeholder, e.g. the description
+// of a setting, which are kept as they are. The sections that are not generated, e.g. a `## Deletion Behavior`
+// section, are kept after the generated section they follow.
+func MergeResource(generated, existing []byte) []byte {
+	existingSections := splitSections(string(existing))
+
+	kept := make(map[string]string)
+	for _, s := range existingSections {
+		kept[s.heading] = s.text
+	}
+
+	generatedSections := splitSections(string(generated))
+
+	isGenerated := make(map[string]bool)
+	for _, s := range generatedSections {
+		isGenerated[s.heading] = true
+	}
+
+	// Note: the sections that are written by hand are attached to the last generated section before them
+	handWritten := make(map[string][]string)
+	anchor := ""
+	for _, s := range existingSections {
+		if isGenerated[s.heading] {
+			anchor = s.heading
+			continue
+		}
+
+		handWritten[anchor] = append(handWritten[anchor], s.text)
+	}
+
+	var b strings.Builder
+	for _, s := range generatedSections {
+		text, ok := kept[s.heading]
+		if !ok || !strings.Contains(s.text, settingPlaceholder) {
+			text = s.text
+		}
+
+		b.WriteString(text)
+
+		for _, hand := range handWritten[s.heading] {
+			b.WriteString(hand)
+		}
+	}
+
+	return []byte(b.String())
+}
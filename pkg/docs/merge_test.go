@@ -0,0 +1,52 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeResource(t *testing.T) {
+	generated := "# Test\n\n## Properties\n\n- `Name`: No Description\n\n" +
+		"## Settings\n\n- `Force`\n\n### Force\n\n!!! note\n    " + settingPlaceholder + "\n\n" +
+		"```text\nForce\n```\n\n" +
+		"### DependsOn\n\n- [Other](./other.md)\n"
+
+	cases := []struct {
+		name     string
+		existing string
+		want     string
+	}{
+		{
+			name:     "generated",
+			existing: generated,
+			want:     generated,
+		},
+		{
+			name:     "out of date",
+			existing: "# Test\n\n## Properties\n\n- `ID`: No Description\n\n",
+			want:     generated,
+		},
+		{
+			name: "hand written",
+			existing: "# Test\n\n## Properties\n\n- `ID`: No Description\n\n" +
+				"## Deletion Behavior\n\nThe resource is stopped first.\n\n```yaml\n# not a heading\n```\n\n" +
+				"## Settings\n\n- `Force`\n\n### Force\n\nForces the removal.\n\n" +
+				"### DependsOn\n\n- [Other](./other.md)\n",
+			want: "# Test\n\n## Properties\n\n- `Name`: No Description\n\n" +
+				"## Deletion Behavior\n\nThe resource is stopped first.\n\n```yaml\n# not a heading\n```\n\n" +
+				"## Settings\n\n- `Force`\n\n### Force\n\nForces the removal.\n\n" +
+				"### DependsOn\n\n- [Other](./other.md)\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := MergeResource([]byte(generated), []byte(tc.existing))
+			assert.Equal(t, tc.want, string(got))
+
+			// Note: the merged docs have to be up to date
+			assert.Equal(t, string(got), string(MergeResource([]byte(generated), got)))
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// Package docs generates the documentation of the resources from their registrations.
+package docs
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/ekristen/libnuke/pkg/docs"
+	"github.com/ekristen/libnuke/pkg/registry"
+)
+
+//go:embed files/*
+var ResourceTemplates embed.FS
+
+var SkipCamelCase = []string{
+	"ELBv2",
+	"IoT",
+}
+
+type TemplateData struct {
+	Name                string
+	Description         string
+	Properties          map[string]string
+	Settings            []string
+	DependsOn           []string
+	DeprecatedAliases   []string
+	AlternativeResource string
+}
+
+// ResourceFilename returns the name of the docs file of the resource, without the extension, e.g. ec2-instance
+func ResourceFilename(name string) string {
+	filename := KebabCase(strings.ToLower(SplitCamelCase(name)))
+	filename = strings.Replace(filename, "io-t", "iot-", 1)
+	filename = strings.Replace(filename, "iamsaml-", "iam-saml-", 1)
+	filename = strings.Replace(filename, "wa-fv2-", "wafv2-", 1)
+	filename = strings.Replace(filename, "f-sx", "fsx-", 1)
+	filename = strings.Replace(filename, "el-bv2-", "elbv2-", 1)
+	filename = strings.Replace(filename, "x-ray-", "xray-", 1)
+	filename = strings.Replace(filename, "x-ray-", "xray-", 1)
+
+	return filename
+}
+
+// RenderResource renders the docs of the resource of the registration
+func RenderResource(reg *registry.Registration) ([]byte, error) {
+	name := reg.Name
+	description := ""
+	if strings.HasPrefix(name, "AWS::") {
+		description = `This is a resource that is access and controlled via the Cloud Control API, as such it's name
+and properties do not match the standard format for aws-nuke resources. Furthermore, the resource properties are
+dynamically populated and therefore cannot be documented here.`
+	}
+
+	data := TemplateData{
+		Name:                name,
+		Description:         description,
+		Properties:          docs.GeneratePropertiesMap(reg.Resource),
+		Settings:            reg.Settings,
+		DependsOn:           reg.DependsOn,
+		DeprecatedAliases:   reg.DeprecatedAliases,
+		AlternativeResource: reg.AlternativeResource,
+	}
+
+	rawTmpl, err := ResourceTemplates.ReadFile("files/resource.gomd")
+	if err != nil {
+		return nil, err
+	}
+
+	funcMap := template.FuncMap{
+		"KebabCase":      KebabCase,
+		"SplitCamelCase": SplitCamelCase,
+		"ToLower":        toLower,
+	}
+
+	tmpl, err := template.New("example").Funcs(funcMap).Parse(string(rawTmpl))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("unable to render the docs of %s: %w", name, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+var (
+	spaces      = regexp.MustCompile(`\s+`)
+	nonAlphaNum = regexp.MustCompile(`[^\pL\pN]+`)
+)
+
+// KebabCase -
+func KebabCase(in string) string {
+	s := casePrepare(in)
+	return spaces.ReplaceAllString(s, "-")
+}
+
+func casePrepare(in string) string {
+	in = strings.TrimSpace(in)
+	s := strings.ToLower(in)
+	// make sure the first letter remains lower- or upper-cased
+	s = strings.Replace(s, string(s[0]), string(in[0]), 1)
+	s = nonAlphaNum.ReplaceAllString(s, " ")
+	return strings.TrimSpace(s)
+}
+
+func toLower(in string) string {
+	return strings.ToLower(in)
+}
+
+func SplitCamelCase(input string) string {
+	if slices.Contains(SkipCamelCase, input) {
+		return input
+	}
+
+	// Regular expression to find boundaries between lowercase and uppercase letters,
+	// and between sequences of uppercase letters followed by lowercase letters.
+	re := regexp.MustCompile(`([a-z])([A-Z0-9])|([A-Z]+)([A-Z][a-z])|(\d)([A-Z])`)
+	// Replace boundaries with a space followed by the uppercase letter.
+
+	boundaries := re.ReplaceAllString(input, "${1}${3}${5} ${2}${4}${6}")
+	boundaries = strings.Replace(boundaries, "io-t", "iot-", 1)
+	boundaries = strings.Replace(boundaries, "iamsaml-", "iam-saml-", 1)
+	boundaries = strings.Replace(boundaries, "wa-fv2-", "wafv2-", 1)
+	boundaries = strings.Replace(boundaries, "f-sx", "fsx-", 1)
+	boundaries = strings.Replace(boundaries, "el-bv2-", "elbv2-", 1)
+	boundaries = strings.Replace(boundaries, "x-ray-", "xray-", 1)
+	boundaries = strings.Replace(boundaries, "x-ray-", "xray-", 1)
+
+	return boundaries
+}
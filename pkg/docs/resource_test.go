@@ -0,0 +1,28 @@
+package docs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResourceFilename(t *testing.T) {
+	cases := []struct {
+		name string
+		want string
+	}{
+		{name: "EC2Instance", want: "ec2-instance"},
+		{name: "IAMSAMLProvider", want: "iam-saml-provider"},
+		{name: "IoTThing", want: "iot-thing"},
+		{name: "WAFv2WebACL", want: "wafv2-web-acl"},
+		{name: "FSxFileSystem", want: "fsx-file-system"},
+		{name: "ELBv2TargetGroup", want: "elbv2-target-group"},
+		{name: "XRayGroup", want: "xray-group"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, ResourceFilename(tc.name))
+		})
+	}
+}
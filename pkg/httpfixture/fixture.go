@@ -0,0 +1,150 @@
+// Package httpfixture records the HTTP exchanges of the AWS SDKs into fixture files and replays them, so that resources
+// can be tested without an AWS account.
+package httpfixture
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+)
+
+// File is the content of a fixture file, the interactions are in the order they were recorded in
+type File struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request that was sent to AWS along with the response that was received
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a request that was sent to AWS, only the headers that identify the operation are kept
+type Request struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// Response is a response that was received from AWS
+type Response struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+	// Base64 is set if the body is not valid UTF-8 and therefore is base64 encoded
+	Base64 bool `json:"base64,omitempty"`
+}
+
+// Load reads the fixture file
+func Load(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file := &File{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// Save writes the fixture file, the directory of the file is created if it does not exist
+func (f *File) Save(path string) error {
+	// Note: HTML is not escaped to keep the XML bodies readable
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(f); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, buf.Bytes(), 0600)
+}
+
+// requestHeaders are the headers of a request that are recorded, the other headers either hold credentials or change
+// with every request, e.g. X-Amz-Date
+var requestHeaders = []string{
+	"Content-Type",
+	"X-Amz-Target",
+}
+
+// droppedResponseHeaders are the headers of a response that are not recorded, the length is set during the replay as
+// the body may be changed by scrubbing it
+var droppedResponseHeaders = map[string]bool{
+	"Content-Length": true,
+	"Date":           true,
+	"Set-Cookie":     true,
+}
+
+func newRequest(method, url string, header http.Header, body []byte) Request {
+	req := Request{
+		Method: method,
+		URL:    Scrub(url),
+		Body:   Scrub(string(body)),
+	}
+
+	for _, name := range requestHeaders {
+		if value := header.Get(name); value != "" {
+			if req.Headers == nil {
+				req.Headers = make(map[string]string)
+			}
+			req.Headers[name] = value
+		}
+	}
+
+	return req
+}
+
+func newResponse(statusCode int, header http.Header, body []byte) Response {
+	resp := Response{
+		StatusCode: statusCode,
+	}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if droppedResponseHeaders[http.CanonicalHeaderKey(name)] || isSecretHeader(name) {
+			continue
+		}
+
+		if resp.Headers == nil {
+			resp.Headers = make(map[string]string)
+		}
+		resp.Headers[http.CanonicalHeaderKey(name)] = Scrub(header.Get(name))
+	}
+
+	if utf8.Valid(body) {
+		resp.Body = Scrub(string(body))
+	} else {
+		resp.Body = base64.StdEncoding.EncodeToString(body)
+		resp.Base64 = true
+	}
+
+	return resp
+}
+
+// body returns the decoded body of the response
+func (r *Response) body() ([]byte, error) {
+	if r.Base64 {
+		return base64.StdEncoding.DecodeString(r.Body)
+	}
+
+	return []byte(r.Body), nil
+}
@@ -0,0 +1,142 @@
+package httpfixture
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws/request" //nolint:staticcheck
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// Recorder records the HTTP exchanges of the SDKs, it hooks into the same places as the trace logging of the
+// sessions and configs in awsutil
+type Recorder struct {
+	mu   sync.Mutex
+	file File
+}
+
+// NewRecorder creates a recorder without any interactions
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// File returns the recorded interactions
+func (r *Recorder) File() *File {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return &File{
+		Interactions: append([]Interaction{}, r.file.Interactions...),
+	}
+}
+
+func (r *Recorder) record(req Request, resp Response) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.file.Interactions = append(r.file.Interactions, Interaction{
+		Request:  req,
+		Response: resp,
+	})
+}
+
+// Handlers adds the recorder to the handlers of a session of SDK v1
+func (r *Recorder) Handlers(handlers *request.Handlers) {
+	handlers.ValidateResponse.PushFront(r.recordV1)
+}
+
+func (r *Recorder) recordV1(req *request.Request) {
+	var reqBody []byte
+	if req.Body != nil {
+		// Note: the body was already sent, it is read from the start again and then reset by the retries, if any
+		if _, err := req.Body.Seek(req.BodyStart, io.SeekStart); err == nil {
+			reqBody, _ = io.ReadAll(req.Body)
+		}
+	}
+
+	respBody, err := readBody(req.HTTPResponse)
+	if err != nil {
+		req.Error = err
+		return
+	}
+
+	r.record(
+		newRequest(req.HTTPRequest.Method, req.HTTPRequest.URL.String(), req.HTTPRequest.Header, reqBody),
+		newResponse(req.HTTPResponse.StatusCode, req.HTTPResponse.Header, respBody),
+	)
+}
+
+// APIOption adds the recorder to the middleware stack of SDK v2, e.g. with config.WithAPIOptions
+func (r *Recorder) APIOption(stack *middleware.Stack) error {
+	return stack.Deserialize.Add(&recordResponse{recorder: r}, middleware.After)
+}
+
+type recordResponse struct {
+	recorder *Recorder
+}
+
+func (*recordResponse) ID() string {
+	return "aws-nuke::recordResponse"
+}
+
+func (m *recordResponse) HandleDeserialize(
+	ctx context.Context, in middleware.DeserializeInput, next middleware.DeserializeHandler,
+) (
+	middleware.DeserializeOutput, middleware.Metadata, error,
+) {
+	req, ok := in.Request.(*smithyhttp.Request)
+	if !ok {
+		return next.HandleDeserialize(ctx, in)
+	}
+
+	var reqBody []byte
+	if req.IsStreamSeekable() {
+		var err error
+		if reqBody, err = io.ReadAll(req.GetStream()); err != nil {
+			return middleware.DeserializeOutput{}, middleware.Metadata{}, err
+		}
+		if err := req.RewindStream(); err != nil {
+			return middleware.DeserializeOutput{}, middleware.Metadata{}, err
+		}
+	}
+
+	out, md, err := next.HandleDeserialize(ctx, in)
+
+	resp, ok := out.RawResponse.(*smithyhttp.Response)
+	if !ok {
+		return out, md, err
+	}
+
+	respBody, readErr := readBody(resp.Response)
+	if readErr != nil {
+		return out, md, readErr
+	}
+
+	m.recorder.record(
+		newRequest(req.Method, req.URL.String(), req.Header, reqBody),
+		newResponse(resp.StatusCode, resp.Header, respBody),
+	)
+
+	return out, md, err
+}
+
+// readBody reads the body of the response and replaces it, so it can be read again by the SDK
+func readBody(resp *http.Response) ([]byte, error) {
+	if resp == nil || resp.Body == nil {
+		return nil, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
@@ -0,0 +1,142 @@
+package httpfixture
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	credentialsv2 "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go/aws"             //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/credentials" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/session"     //nolint:staticcheck
+)
+
+// Replayer is a http.RoundTripper that serves the responses of a fixture file instead of sending the requests to AWS
+type Replayer struct {
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// NewReplayer creates a replayer of the interactions of the fixture file
+func NewReplayer(file *File) *Replayer {
+	return &Replayer{
+		interactions: file.Interactions,
+		used:         make([]bool, len(file.Interactions)),
+	}
+}
+
+// RoundTrip serves the response of the first interaction that was not served yet and whose request matches the
+// request. The request is scrubbed before it is matched, so it matches even if it has e.g. a real account ID.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	want := newRequest(req.Method, req.URL.String(), req.Header, body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.interactions {
+		if r.used[i] || !matches(interaction.Request, want) {
+			continue
+		}
+
+		respBody, err := interaction.Response.body()
+		if err != nil {
+			return nil, err
+		}
+
+		r.used[i] = true
+
+		header := make(http.Header)
+		for name, value := range interaction.Response.Headers {
+			header.Set(name, value)
+		}
+
+		statusCode := interaction.Response.StatusCode
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+			StatusCode:    statusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(respBody)),
+			ContentLength: int64(len(respBody)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("no recorded response for %s %s %s", want.Method, want.URL, want.Body)
+}
+
+// Unused returns the interactions whose response was not served
+func (r *Replayer) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var unused []Interaction
+	for i, interaction := range r.interactions {
+		if !r.used[i] {
+			unused = append(unused, interaction)
+		}
+	}
+
+	return unused
+}
+
+func matches(recorded, req Request) bool {
+	if recorded.Method != req.Method || recorded.URL != req.URL {
+		return false
+	}
+
+	for _, name := range requestHeaders {
+		if recorded.Headers[name] != req.Headers[name] {
+			return false
+		}
+	}
+
+	return normalizeBody(recorded.Body) == normalizeBody(req.Body)
+}
+
+// NewReplaySession creates a session of SDK v1 whose requests are served by the replayer
+func NewReplaySession(replayer *Replayer, region string) (*session.Session, error) {
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials(AccessKeyID, SecretAccessKey, ""),
+		MaxRetries:  aws.Int(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Note: the client is only set on the copy, as the session does not accept other transports if a custom CA
+	// bundle is configured, e.g. with AWS_CA_BUNDLE
+	return sess.Copy(&aws.Config{
+		HTTPClient: &http.Client{Transport: replayer},
+	}), nil
+}
+
+// NewReplayConfig creates a config of SDK v2 whose requests are served by the replayer
+func NewReplayConfig(replayer *Replayer, region string) *awsv2.Config {
+	return &awsv2.Config{
+		Region:      region,
+		Credentials: credentialsv2.NewStaticCredentialsProvider(AccessKeyID, SecretAccessKey, ""),
+		HTTPClient:  &http.Client{Transport: replayer},
+		Retryer: func() awsv2.Retryer {
+			return awsv2.NopRetryer{}
+		},
+	}
+}
@@ -0,0 +1,132 @@
+package httpfixture
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	awsv2 "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/session" //nolint:staticcheck
+	"github.com/aws/smithy-go/middleware"
+)
+
+// RecordEnv is the environment variable that makes the fixtures record the HTTP exchanges with AWS instead of
+// replaying them, the credentials are taken from the environment or the shared config as usual
+const RecordEnv = "AWS_NUKE_RECORD_FIXTURES"
+
+// Fixture provides the sessions and configs of a test, which either record their HTTP exchanges into the fixture file
+// or replay them from it
+type Fixture struct {
+	t        *testing.T
+	recorder *Recorder
+	replayer *Replayer
+}
+
+// New creates the fixture of a test, the fixture file is written once the test is done when recording, otherwise it
+// is loaded and the test fails if any of its interactions is not replayed
+func New(t *testing.T, path string) *Fixture {
+	t.Helper()
+
+	f := &Fixture{t: t}
+
+	if os.Getenv(RecordEnv) != "" {
+		f.recorder = NewRecorder()
+
+		t.Cleanup(func() {
+			if err := f.recorder.File().Save(path); err != nil {
+				t.Errorf("unable to save the fixture %s: %v", path, err)
+			}
+		})
+
+		return f
+	}
+
+	file, err := Load(path)
+	if err != nil {
+		t.Fatalf("unable to load the fixture %s, record it with %s=1: %v", path, RecordEnv, err)
+	}
+
+	f.replayer = NewReplayer(file)
+
+	t.Cleanup(func() {
+		for _, interaction := range f.replayer.Unused() {
+			t.Errorf("the recorded request %s %s was not sent", interaction.Request.Method, interaction.Request.URL)
+		}
+	})
+
+	return f
+}
+
+// Recording returns true if the fixture records the HTTP exchanges with AWS
+func (f *Fixture) Recording() bool {
+	return f.recorder != nil
+}
+
+// Session returns a session of SDK v1 in the region
+func (f *Fixture) Session(region string) *session.Session {
+	f.t.Helper()
+
+	if f.Recording() {
+		sess, err := session.NewSessionWithOptions(session.Options{
+			Config:            aws.Config{Region: aws.String(region)},
+			SharedConfigState: session.SharedConfigEnable,
+		})
+		if err != nil {
+			f.t.Fatalf("unable to create the session: %v", err)
+		}
+
+		f.recorder.Handlers(&sess.Handlers)
+
+		return sess
+	}
+
+	sess, err := NewReplaySession(f.replayer, region)
+	if err != nil {
+		f.t.Fatalf("unable to create the session: %v", err)
+	}
+
+	return sess
+}
+
+// Config returns a config of SDK v2 in the region
+func (f *Fixture) Config(region string) *awsv2.Config {
+	f.t.Helper()
+
+	if f.Recording() {
+		cfg, err := config.LoadDefaultConfig(context.TODO(),
+			config.WithRegion(region),
+			config.WithAPIOptions([]func(*middleware.Stack) error{f.recorder.APIOption}))
+		if err != nil {
+			f.t.Fatalf("unable to create the config: %v", err)
+		}
+
+		return &cfg
+	}
+
+	return NewReplayConfig(f.replayer, region)
+}
+
+// AccountID returns the ID of the account the fixture is recorded in, it is AccountID during the replay
+func (f *Fixture) AccountID() string {
+	f.t.Helper()
+
+	if !f.Recording() {
+		return AccountID
+	}
+
+	// Note: the request is not recorded, as the account ID is not looked up during the replay
+	cfg, err := config.LoadDefaultConfig(context.TODO(), config.WithRegion("us-east-1"))
+	if err != nil {
+		f.t.Fatalf("unable to create the config: %v", err)
+	}
+
+	identity, err := sts.NewFromConfig(cfg).GetCallerIdentity(context.TODO(), &sts.GetCallerIdentityInput{})
+	if err != nil {
+		f.t.Fatalf("unable to get the account ID: %v", err)
+	}
+
+	return awsv2.ToString(identity.Account)
+}
@@ -0,0 +1,306 @@
+package nuke
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// approvalVersion is the version of the signed payload, it is part of the payload so that signatures can not be
+// reused if the format changes
+const approvalVersion = "aws-nuke-approval-v1"
+
+// ErrApprovalRequired is returned when a run requires an approval, but none was given
+var ErrApprovalRequired = errors.New("an approval is required to run with --no-dry-run")
+
+// ErrApprovalInvalid is returned when the approval does not permit the run
+var ErrApprovalInvalid = errors.New("invalid approval")
+
+// ApprovalRequest is the result of a dry run that a second person approves by signing it. The signature binds the
+// account, the hash of the resources that would be removed and the expiry.
+type ApprovalRequest struct {
+	AccountID    string    `json:"account_id"`
+	ResourceHash string    `json:"resource_hash"`
+	CreatedAt    time.Time `json:"created_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+
+	// Resources is the list of resources the hash was calculated from, it is informational only
+	Resources []string `json:"resources"`
+}
+
+// ApprovalSignature is a signature of the approval request by a single approver
+type ApprovalSignature struct {
+	PublicKey string `json:"public_key"`
+	Signature string `json:"signature"`
+}
+
+// Approval is an approval request along with the signatures of the approvers
+type Approval struct {
+	Request    ApprovalRequest     `json:"request"`
+	Signatures []ApprovalSignature `json:"signatures"`
+}
+
+// NewApproval returns an unsigned approval for the resources of the queue that would be removed
+func NewApproval(accountID string, items []*queue.Item, ttl time.Duration) *Approval {
+	resources := approvalResources(items)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	return &Approval{
+		Request: ApprovalRequest{
+			AccountID:    accountID,
+			ResourceHash: approvalHash(resources),
+			CreatedAt:    now,
+			ExpiresAt:    now.Add(ttl),
+			Resources:    resources,
+		},
+		Signatures: []ApprovalSignature{},
+	}
+}
+
+// ReadApproval reads an approval from the path
+func ReadApproval(path string) (*Approval, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	approval := &Approval{}
+	if err := json.Unmarshal(data, approval); err != nil {
+		return nil, fmt.Errorf("unable to parse approval %s: %w", path, err)
+	}
+
+	return approval, nil
+}
+
+// Write writes the approval as JSON to the path
+func (a *Approval) Write(path string) error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// Payload returns the bytes that are signed
+func (r *ApprovalRequest) Payload() []byte {
+	return []byte(strings.Join([]string{
+		approvalVersion,
+		r.AccountID,
+		r.ResourceHash,
+		r.ExpiresAt.UTC().Format(time.RFC3339),
+	}, "\n"))
+}
+
+// Sign adds the signature of the private key, an existing signature of the same key is replaced
+func (a *Approval) Sign(key ed25519.PrivateKey) {
+	publicKey := base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+
+	a.Signatures = slices.DeleteFunc(a.Signatures, func(s ApprovalSignature) bool {
+		return s.PublicKey == publicKey
+	})
+
+	a.Signatures = append(a.Signatures, ApprovalSignature{
+		PublicKey: publicKey,
+		Signature: base64.StdEncoding.EncodeToString(ed25519.Sign(key, a.Request.Payload())),
+	})
+}
+
+// Verify checks that the approval is for the account, has not expired and is signed by at least the minimum number
+// of distinct trusted keys.
+func (a *Approval) Verify(accountID string, trusted []ed25519.PublicKey, minApprovals int, now time.Time) error {
+	if a.Request.AccountID != accountID {
+		return fmt.Errorf("%w: it is for account %s, not %s", ErrApprovalInvalid, a.Request.AccountID, accountID)
+	}
+
+	if !now.Before(a.Request.ExpiresAt) {
+		return fmt.Errorf("%w: it expired at %s", ErrApprovalInvalid, a.Request.ExpiresAt.Format(time.RFC3339))
+	}
+
+	// Note: approvers are identified by the index of their trusted key, so that a key encoded in different ways
+	// counts only once
+	payload := a.Request.Payload()
+	approvers := map[int]bool{}
+	for _, signature := range a.Signatures {
+		publicKey, err := base64.StdEncoding.Strict().DecodeString(signature.PublicKey)
+		if err != nil || len(publicKey) != ed25519.PublicKeySize {
+			continue
+		}
+
+		index := slices.IndexFunc(trusted, func(key ed25519.PublicKey) bool {
+			return key.Equal(ed25519.PublicKey(publicKey))
+		})
+		if index < 0 {
+			continue
+		}
+
+		sig, err := base64.StdEncoding.Strict().DecodeString(signature.Signature)
+		if err != nil || !ed25519.Verify(publicKey, payload, sig) {
+			return fmt.Errorf("%w: the signature of %s is not valid", ErrApprovalInvalid, signature.PublicKey)
+		}
+
+		approvers[index] = true
+	}
+
+	if len(approvers) < minApprovals {
+		return fmt.Errorf("%w: it is signed by %d trusted approver(s), %d required", ErrApprovalInvalid,
+			len(approvers), minApprovals)
+	}
+
+	return nil
+}
+
+// VerifyHash checks that the resource hash is the hash of the listed resources. The list is not signed, so it must be
+// checked before it is shown to an approver or used to describe a difference.
+func (r *ApprovalRequest) VerifyHash() error {
+	if approvalHash(r.Resources) != r.ResourceHash {
+		return fmt.Errorf("%w: the resource hash does not match the listed resources", ErrApprovalInvalid)
+	}
+
+	return nil
+}
+
+// VerifyResources checks that the resources that would be removed are the resources that were approved
+func (a *Approval) VerifyResources(items []*queue.Item) error {
+	resources := approvalResources(items)
+	if approvalHash(resources) == a.Request.ResourceHash {
+		return nil
+	}
+
+	// Note: the list of resources is only used to describe the difference if it matches the hash
+	if a.Request.VerifyHash() != nil {
+		return fmt.Errorf("%w: the resources found by the scan do not match the approved resources", ErrApprovalInvalid)
+	}
+
+	added, removed := 0, 0
+	for _, resource := range resources {
+		if !slices.Contains(a.Request.Resources, resource) {
+			added++
+		}
+	}
+
+	for _, resource := range a.Request.Resources {
+		if !slices.Contains(resources, resource) {
+			removed++
+		}
+	}
+
+	return fmt.Errorf("%w: the resources found by the scan do not match the approved resources, "+
+		"%d not approved and %d approved, but no longer found", ErrApprovalInvalid, added, removed)
+}
+
+// ApprovalGate wraps the prompt of a run, the run is refused unless the approval is valid and the resources found by
+// the scan are the approved resources.
+type ApprovalGate struct {
+	Nuke         *Nuke
+	Approval     *Approval
+	AccountID    string
+	Trusted      []ed25519.PublicKey
+	MinApprovals int
+
+	// reviewed are the approved resources that were verified before the review
+	reviewed []string
+}
+
+// Wrap returns a prompt that checks the approval before calling the prompt. The prompt is called before and after
+// the scan, the resources are only verified once the scan has filled the queue. Dry runs do not require an approval.
+func (g *ApprovalGate) Wrap(prompt func() error) func() error {
+	return func() error {
+		if !g.Nuke.Parameters.NoDryRun {
+			return prompt()
+		}
+
+		var items []*queue.Item
+		if g.Nuke.Queue != nil {
+			items = g.Nuke.Queue.GetItems()
+		}
+
+		if err := g.verify(items); err != nil {
+			return err
+		}
+
+		return prompt()
+	}
+}
+
+// WrapReview returns a review that checks the approval before calling the review, so the resources found by the scan
+// are verified before any of them are excluded. The review only excludes resources, the prompt after the review
+// checks that the resources that are left were approved.
+func (g *ApprovalGate) WrapReview(review Review) Review {
+	return func(items []*queue.Item) error {
+		if g.Nuke.Parameters.NoDryRun {
+			if err := g.verify(items); err != nil {
+				return err
+			}
+
+			g.reviewed = approvalResources(items)
+		}
+
+		return review(items)
+	}
+}
+
+// verify checks the approval and that the resources that would be removed are approved, once the scan has found them
+func (g *ApprovalGate) verify(items []*queue.Item) error {
+	if g.Approval == nil {
+		return ErrApprovalRequired
+	}
+
+	if err := g.Approval.Verify(g.AccountID, g.Trusted, g.MinApprovals, time.Now()); err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	if g.reviewed == nil {
+		return g.Approval.VerifyResources(items)
+	}
+
+	for _, resource := range approvalResources(items) {
+		if !slices.Contains(g.reviewed, resource) {
+			return fmt.Errorf("%w: %s was not approved", ErrApprovalInvalid, resource)
+		}
+	}
+
+	return nil
+}
+
+// approvalResources returns the sorted list of resources that would be removed, each resource is identified by its
+// region, type and name. Resources that wait on their dependencies are removed as well.
+func approvalResources(items []*queue.Item) []string {
+	resources := make([]string, 0, len(items))
+	for _, item := range items {
+		state := item.GetState()
+		if state != queue.ItemStateNew && state != queue.ItemStateNewDependency {
+			continue
+		}
+
+		resources = append(resources, fmt.Sprintf("%s %s %s", item.Owner, item.Type, reviewDisplayName(item)))
+	}
+
+	slices.Sort(resources)
+
+	return resources
+}
+
+func approvalHash(resources []string) string {
+	hash := sha256.New()
+	for _, resource := range resources {
+		hash.Write([]byte(resource))
+		hash.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
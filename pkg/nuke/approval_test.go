@@ -0,0 +1,247 @@
+package nuke
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+func testApprovalKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	return publicKey, privateKey
+}
+
+func TestApproval_Verify(t *testing.T) {
+	alicePublic, alicePrivate := testApprovalKey(t)
+	bobPublic, bobPrivate := testApprovalKey(t)
+	_, evePrivate := testApprovalKey(t)
+	trusted := []ed25519.PublicKey{alicePublic, bobPublic}
+
+	approval := NewApproval("012345678901", testReviewItems(), time.Hour)
+	assert.Len(t, approval.Request.Resources, 4)
+	assert.ErrorIs(t, approval.Verify("012345678901", trusted, 1, time.Now()), ErrApprovalInvalid)
+
+	approval.Sign(evePrivate)
+	assert.EqualError(t, approval.Verify("012345678901", trusted, 1, time.Now()),
+		"invalid approval: it is signed by 0 trusted approver(s), 1 required")
+
+	approval.Sign(alicePrivate)
+	approval.Sign(alicePrivate)
+	assert.Len(t, approval.Signatures, 2)
+	assert.NoError(t, approval.Verify("012345678901", trusted, 1, time.Now()))
+	assert.EqualError(t, approval.Verify("012345678901", trusted, 2, time.Now()),
+		"invalid approval: it is signed by 1 trusted approver(s), 2 required")
+
+	approval.Sign(bobPrivate)
+	assert.NoError(t, approval.Verify("012345678901", trusted, 2, time.Now()))
+
+	assert.EqualError(t, approval.Verify("999999999999", trusted, 1, time.Now()),
+		"invalid approval: it is for account 012345678901, not 999999999999")
+	assert.ErrorContains(t, approval.Verify("012345678901", trusted, 1, time.Now().Add(2*time.Hour)),
+		"invalid approval: it expired at")
+
+	// Note: extending the expiry invalidates the signatures
+	approval.Request.ExpiresAt = approval.Request.ExpiresAt.Add(time.Hour)
+	assert.ErrorContains(t, approval.Verify("012345678901", trusted, 1, time.Now()), "is not valid")
+}
+
+func TestApproval_VerifyResources(t *testing.T) {
+	items := testReviewItems()
+	approval := NewApproval("012345678901", items, time.Hour)
+
+	assert.NoError(t, approval.VerifyResources(testReviewItems()))
+
+	// Note: resources that are filtered are not part of the approval
+	items = append(items, &queue.Item{Resource: &testResource{name: "other"}, State: queue.ItemStateFiltered,
+		Type: "S3Bucket", Owner: "global"})
+	assert.NoError(t, approval.VerifyResources(items))
+
+	items[0].State = queue.ItemStateFiltered
+	items = append(items, &queue.Item{Resource: &testNamedResource{testResource{name: "i-3"}},
+		State: queue.ItemStateNew, Type: "EC2Instance", Owner: "us-east-1"})
+	assert.EqualError(t, approval.VerifyResources(items), "invalid approval: the resources found by the scan do not "+
+		"match the approved resources, 1 not approved and 1 approved, but no longer found")
+
+	approval.Request.Resources = nil
+	assert.EqualError(t, approval.VerifyResources(items),
+		"invalid approval: the resources found by the scan do not match the approved resources")
+}
+
+func TestApproval_VerifyDistinctApprovers(t *testing.T) {
+	alicePublic, alicePrivate := testApprovalKey(t)
+	bobPublic, _ := testApprovalKey(t)
+	trusted := []ed25519.PublicKey{alicePublic, bobPublic}
+
+	approval := NewApproval("012345678901", testReviewItems(), time.Hour)
+	approval.Sign(alicePrivate)
+
+	// Note: the padding of a base64 encoded ed25519 key has two unused bits, setting them encodes the same key
+	// differently, which must neither count as a second approver nor be accepted at all
+	const alphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+	signature := approval.Signatures[0]
+	encoded := []byte(signature.PublicKey)
+	encoded[len(encoded)-2] = alphabet[strings.IndexByte(alphabet, encoded[len(encoded)-2])^1]
+
+	decoded, err := base64.StdEncoding.DecodeString(string(encoded))
+	assert.NoError(t, err)
+	assert.Equal(t, []byte(alicePublic), decoded)
+
+	approval.Signatures = append(approval.Signatures, ApprovalSignature{
+		PublicKey: string(encoded),
+		Signature: signature.Signature,
+	})
+
+	assert.NoError(t, approval.Verify("012345678901", trusted, 1, time.Now()))
+	assert.EqualError(t, approval.Verify("012345678901", trusted, 2, time.Now()),
+		"invalid approval: it is signed by 1 trusted approver(s), 2 required")
+}
+
+func TestApprovalRequest_VerifyHash(t *testing.T) {
+	approval := NewApproval("012345678901", testReviewItems(), time.Hour)
+	assert.NoError(t, approval.Request.VerifyHash())
+
+	approval.Request.Resources = approval.Request.Resources[1:]
+	assert.EqualError(t, approval.Request.VerifyHash(),
+		"invalid approval: the resource hash does not match the listed resources")
+}
+
+func TestApproval_WaitOnDependencies(t *testing.T) {
+	items := testReviewItems()
+	items[0].State = queue.ItemStateNewDependency
+
+	approval := NewApproval("012345678901", items, time.Hour)
+	assert.Len(t, approval.Request.Resources, 4)
+	assert.Contains(t, approval.Request.Resources, "us-east-1 EC2Instance i-2")
+
+	// Note: a resource that waits on its dependencies would be removed without being approved otherwise
+	assert.Error(t, approval.VerifyResources(testReviewItems()[1:]))
+	assert.NoError(t, approval.VerifyResources(items))
+}
+
+func TestApproval_ReadWrite(t *testing.T) {
+	_, privateKey := testApprovalKey(t)
+
+	approval := NewApproval("012345678901", testReviewItems(), time.Hour)
+	approval.Sign(privateKey)
+
+	path := filepath.Join(t.TempDir(), "approval.json")
+	assert.NoError(t, approval.Write(path))
+
+	read, err := ReadApproval(path)
+	assert.NoError(t, err)
+	assert.Equal(t, approval.Request.ResourceHash, read.Request.ResourceHash)
+	assert.True(t, approval.Request.ExpiresAt.Equal(read.Request.ExpiresAt))
+	assert.Equal(t, approval.Signatures, read.Signatures)
+}
+
+func TestApprovalGate_Wrap(t *testing.T) {
+	publicKey, privateKey := testApprovalKey(t)
+
+	approval := NewApproval("012345678901", testReviewItems(), time.Hour)
+	approval.Sign(privateKey)
+
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	gate := &ApprovalGate{
+		Nuke:         n,
+		AccountID:    "012345678901",
+		Trusted:      []ed25519.PublicKey{publicKey},
+		MinApprovals: 1,
+	}
+
+	prompted := 0
+	prompt := gate.Wrap(func() error {
+		prompted++
+		return nil
+	})
+
+	assert.ErrorIs(t, prompt(), ErrApprovalRequired)
+
+	gate.Approval = approval
+	assert.NoError(t, prompt())
+
+	n.Queue.Items = testReviewItems()
+	assert.NoError(t, prompt())
+
+	n.Queue.Items[0].State = queue.ItemStateFiltered
+	assert.True(t, errors.Is(prompt(), ErrApprovalInvalid))
+	assert.Equal(t, 2, prompted)
+
+	n.Parameters.NoDryRun = false
+	gate.Approval = nil
+	assert.NoError(t, prompt())
+	assert.Equal(t, 3, prompted)
+}
+
+func TestApprovalGate_WrapReview(t *testing.T) {
+	publicKey, privateKey := testApprovalKey(t)
+
+	// Note: the approval is taken from a dry run without a review
+	approval := NewApproval("012345678901", testReviewItems(), time.Hour)
+	approval.Sign(privateKey)
+
+	newGate := func() (*Nuke, *ApprovalGate) {
+		n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+		return n, &ApprovalGate{
+			Nuke:         n,
+			Approval:     approval,
+			AccountID:    "012345678901",
+			Trusted:      []ed25519.PublicKey{publicKey},
+			MinApprovals: 1,
+		}
+	}
+
+	review := (&Reviewer{
+		In:        strings.NewReader("exclude-type IAMRole\ndone\n"),
+		Out:       &bytes.Buffer{},
+		AccountID: "012345678901",
+	}).Review
+
+	// The prompt is called before the scan, then the review runs and the prompt is called again
+	n, gate := newGate()
+	prompt := gate.Wrap(func() error { return nil })
+	assert.NoError(t, prompt())
+
+	n.Queue.Items = testReviewItems()
+	assert.NoError(t, gate.WrapReview(review)(n.Queue.GetItems()))
+	assert.Equal(t, queue.ItemStateFiltered, n.Queue.Items[3].GetState())
+	assert.NoError(t, prompt())
+
+	// Note: a resource that is found by the scan, but not approved, is refused before the review
+	n, gate = newGate()
+	n.Queue.Items = append(testReviewItems(), &queue.Item{
+		Resource: &testNamedResource{testResource{name: "i-3"}}, State: queue.ItemStateNew, Type: "EC2Instance",
+		Owner: "us-east-1",
+	})
+
+	reviewed := false
+	err := gate.WrapReview(func(_ []*queue.Item) error {
+		reviewed = true
+		return nil
+	})(n.Queue.GetItems())
+	assert.ErrorIs(t, err, ErrApprovalInvalid)
+	assert.False(t, reviewed)
+
+	// Dry runs are reviewed without an approval
+	n, gate = newGate()
+	n.Parameters.NoDryRun = false
+	gate.Approval = nil
+	assert.NoError(t, gate.WrapReview(func(_ []*queue.Item) error {
+		reviewed = true
+		return nil
+	})(testReviewItems()))
+	assert.True(t, reviewed)
+}
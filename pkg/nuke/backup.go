@@ -0,0 +1,185 @@
+package nuke
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// BackupPrefix is the prefix of every artifact created before a resource is removed, it can be used to filter the
+// artifacts from subsequent runs.
+const BackupPrefix = "aws-nuke-backup"
+
+// BackupTagKey is the tag added to artifacts that support tags, the value is the identifier of the removed resource
+const BackupTagKey = "aws-nuke:backup-of"
+
+const (
+	backupMagic      = "AWSNUKE1"
+	backupSaltSize   = 16
+	backupKeySize    = 32
+	backupIterations = 600000
+)
+
+// ErrBackupPassphrase is returned when an export can not be encrypted or decrypted with the passphrase
+var ErrBackupPassphrase = errors.New("invalid backup passphrase or corrupt export")
+
+var backupNameInvalid = regexp.MustCompile(`[^a-zA-Z0-9]+`)
+
+// BackupOptions are the options for creating a recoverable artifact before a resource is removed.
+type BackupOptions struct {
+	// ResourceTypes is the list of resource types that are backed up
+	ResourceTypes []string
+
+	// ExportDirectory is the local directory encrypted exports are written to
+	ExportDirectory string
+
+	// Passphrase is the passphrase encrypted exports are encrypted with
+	Passphrase string
+}
+
+// Enabled returns true if the resource type should be backed up, it is safe to call on nil options.
+func (o *BackupOptions) Enabled(resourceType string) bool {
+	if o == nil {
+		return false
+	}
+
+	return slices.Contains(o.ResourceTypes, resourceType)
+}
+
+// BackupArtifact is a recoverable artifact that was created before a resource was removed.
+type BackupArtifact struct {
+	// Kind is the kind of artifact, e.g. ebs-snapshot or encrypted-export
+	Kind string `json:"kind"`
+
+	// ID is the identifier of the artifact, e.g. the snapshot ID or the path of the export
+	ID string `json:"id"`
+}
+
+// BackupArtifactGetter is implemented by resources that create an artifact before they are removed.
+type BackupArtifactGetter interface {
+	BackupArtifacts() []BackupArtifact
+}
+
+// BackupName returns a name for an artifact of the resource that is valid for snapshot and backup identifiers. It
+// only contains letters, digits and hyphens, and starts with a letter.
+func BackupName(id string, t time.Time) string {
+	sanitized := strings.Trim(backupNameInvalid.ReplaceAllString(id, "-"), "-")
+
+	// Note: most identifiers are limited to 255 characters, leave room for the prefix and the timestamp
+	if len(sanitized) > 200 {
+		sanitized = strings.TrimRight(sanitized[:200], "-")
+	}
+
+	return fmt.Sprintf("%s-%s-%s", BackupPrefix, sanitized, t.UTC().Format("20060102150405"))
+}
+
+// WriteExport encrypts the value as JSON and writes it to a new file in the export directory, it returns the path of
+// the file. The file can be decrypted with DecryptExport.
+func (o *BackupOptions) WriteExport(resourceType, id string, value interface{}) (string, error) {
+	if o.Passphrase == "" {
+		return "", fmt.Errorf("%w: passphrase is empty", ErrBackupPassphrase)
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := EncryptExport(data, o.Passphrase)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(o.ExportDirectory, 0700); err != nil {
+		return "", err
+	}
+
+	name := fmt.Sprintf("%s-%s.json.enc", resourceType, BackupName(id, time.Now()))
+	path := filepath.Join(o.ExportDirectory, name)
+
+	if err := os.WriteFile(path, sealed, 0600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// EncryptExport encrypts the data with AES-256-GCM, the key is derived from the passphrase with PBKDF2. The result
+// contains a header, the salt and the nonce, so it can be decrypted with only the passphrase.
+func EncryptExport(data []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, backupSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	gcm, err := backupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	out := make([]byte, 0, len(backupMagic)+len(salt)+len(nonce)+len(data)+gcm.Overhead())
+	out = append(out, backupMagic...)
+	out = append(out, salt...)
+	out = append(out, nonce...)
+
+	return gcm.Seal(out, nonce, data, []byte(backupMagic)), nil
+}
+
+// DecryptExport decrypts data created by EncryptExport
+func DecryptExport(sealed []byte, passphrase string) ([]byte, error) {
+	if !bytes.HasPrefix(sealed, []byte(backupMagic)) || len(sealed) < len(backupMagic)+backupSaltSize {
+		return nil, ErrBackupPassphrase
+	}
+
+	sealed = sealed[len(backupMagic):]
+	salt, sealed := sealed[:backupSaltSize], sealed[backupSaltSize:]
+
+	gcm, err := backupCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrBackupPassphrase
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+
+	data, err := gcm.Open(nil, nonce, ciphertext, []byte(backupMagic))
+	if err != nil {
+		return nil, ErrBackupPassphrase
+	}
+
+	return data, nil
+}
+
+func backupCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, backupIterations, backupKeySize)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
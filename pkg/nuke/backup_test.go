@@ -0,0 +1,77 @@
+package nuke
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackupOptions_Enabled(t *testing.T) {
+	var disabled *BackupOptions
+	assert.False(t, disabled.Enabled("EC2Volume"))
+
+	opts := &BackupOptions{ResourceTypes: []string{"EC2Volume"}}
+	assert.True(t, opts.Enabled("EC2Volume"))
+	assert.False(t, opts.Enabled("RDSInstance"))
+}
+
+func TestBackupName(t *testing.T) {
+	now := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+
+	cases := []struct {
+		id   string
+		want string
+	}{
+		{id: "vol-0123456789", want: "aws-nuke-backup-vol-0123456789-20240506070809"},
+		{id: "my_table.v2", want: "aws-nuke-backup-my-table-v2-20240506070809"},
+		{id: "--db--", want: "aws-nuke-backup-db-20240506070809"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.id, func(t *testing.T) {
+			assert.Equal(t, tc.want, BackupName(tc.id, now))
+		})
+	}
+
+	long := BackupName(strings.Repeat("a", 300), now)
+	assert.LessOrEqual(t, len(long), 255)
+	assert.True(t, strings.HasSuffix(long, "-20240506070809"))
+}
+
+func TestBackupOptions_WriteExport(t *testing.T) {
+	opts := &BackupOptions{
+		ExportDirectory: t.TempDir(),
+		Passphrase:      "correct horse battery staple",
+	}
+
+	path, err := opts.WriteExport("SSMParameter", "/app/db/password", map[string]string{"value": "hunter2"})
+	assert.NoError(t, err)
+
+	info, err := os.Stat(path)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	sealed, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(sealed), "hunter2")
+
+	data, err := DecryptExport(sealed, "correct horse battery staple")
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"value":"hunter2"}`, string(data))
+
+	_, err = DecryptExport(sealed, "wrong")
+	assert.ErrorIs(t, err, ErrBackupPassphrase)
+
+	_, err = DecryptExport([]byte("garbage"), "correct horse battery staple")
+	assert.ErrorIs(t, err, ErrBackupPassphrase)
+}
+
+func TestBackupOptions_WriteExportNoPassphrase(t *testing.T) {
+	opts := &BackupOptions{ExportDirectory: t.TempDir()}
+
+	_, err := opts.WriteExport("SSMParameter", "/app/db/password", "hunter2")
+	assert.ErrorIs(t, err, ErrBackupPassphrase)
+}
@@ -0,0 +1,150 @@
+package nuke
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+// ExpiryState is the outcome of evaluating the expiry policy against a resource.
+type ExpiryState int
+
+const (
+	// ExpiryNotApplicable means the resource does not carry any expiry tag, the policy does not apply.
+	ExpiryNotApplicable ExpiryState = iota
+	// ExpiryExpired means the resource has expired and is eligible for removal.
+	ExpiryExpired
+	// ExpiryUnexpired means the resource has not expired yet and must be kept.
+	ExpiryUnexpired
+	// ExpiryMalformed means the resource carries an expiry tag that could not be evaluated.
+	ExpiryMalformed
+)
+
+// DefaultExpiryCreationProperties is the list of properties that are checked, in order, for the creation time of a
+// resource when the ttl tag is used.
+var DefaultExpiryCreationProperties = []string{
+	"CreationDate",
+	"CreationTime",
+	"CreationTimestamp",
+	"CreatedAt",
+	"CreatedTime",
+	"CreatedDate",
+	"CreateDate",
+	"CreateTime",
+	"LaunchTime",
+}
+
+// ExpiryPolicy decides if a resource is eligible for removal based on tags set on the resource by its owner. The
+// ExpiresAtTag holds an absolute RFC3339 timestamp, the TTLTag holds a duration (e.g. 72h) that is added to the
+// creation time of the resource. If both tags are present the ExpiresAtTag takes precedence.
+type ExpiryPolicy struct {
+	// ExpiresAtTag is the tag key holding an absolute RFC3339 expiry timestamp
+	ExpiresAtTag string
+
+	// TTLTag is the tag key holding a duration relative to the creation time of the resource
+	TTLTag string
+
+	// CreationProperties is the ordered list of properties used to find the creation time of the resource, if it is
+	// empty DefaultExpiryCreationProperties is used
+	CreationProperties []string
+
+	// Now returns the current time, it is only overridden for testing
+	Now func() time.Time
+}
+
+// Evaluate evaluates the policy against the properties of a resource and returns the state along with a human
+// readable reason.
+func (p *ExpiryPolicy) Evaluate(props types.Properties) (ExpiryState, string) {
+	now := time.Now()
+	if p.Now != nil {
+		now = p.Now()
+	}
+
+	expiresAt, state, reason := p.resolveExpiresAt(props)
+	if state != ExpiryExpired {
+		return state, reason
+	}
+
+	if now.Before(expiresAt) {
+		return ExpiryUnexpired, fmt.Sprintf("not expired until %s", expiresAt.UTC().Format(time.RFC3339))
+	}
+
+	return ExpiryExpired, fmt.Sprintf("expired at %s", expiresAt.UTC().Format(time.RFC3339))
+}
+
+// resolveExpiresAt returns the expiry time of the resource, the returned state is ExpiryExpired when the expiry time
+// could be resolved, it is up to the caller to compare it against the current time.
+func (p *ExpiryPolicy) resolveExpiresAt(props types.Properties) (time.Time, ExpiryState, string) {
+	if p.ExpiresAtTag != "" {
+		key := fmt.Sprintf("tag:%s", p.ExpiresAtTag)
+		if value, ok := props[key]; ok {
+			expiresAt, err := time.Parse(time.RFC3339, value)
+			if err != nil {
+				return time.Time{}, ExpiryMalformed,
+					fmt.Sprintf("malformed expiry tag %s=%q: expected RFC3339 timestamp", p.ExpiresAtTag, value)
+			}
+
+			return expiresAt, ExpiryExpired, ""
+		}
+	}
+
+	if p.TTLTag != "" {
+		key := fmt.Sprintf("tag:%s", p.TTLTag)
+		if value, ok := props[key]; ok {
+			ttl, err := time.ParseDuration(value)
+			if err != nil || ttl < 0 {
+				return time.Time{}, ExpiryMalformed,
+					fmt.Sprintf("malformed ttl tag %s=%q: expected positive duration", p.TTLTag, value)
+			}
+
+			createdAt, found := p.creationTime(props)
+			if !found {
+				return time.Time{}, ExpiryMalformed,
+					fmt.Sprintf("ttl tag %s is set, but the resource has no creation time property", p.TTLTag)
+			}
+
+			return createdAt.Add(ttl), ExpiryExpired, ""
+		}
+	}
+
+	return time.Time{}, ExpiryNotApplicable, ""
+}
+
+// creationTime returns the creation time of the resource from the first creation property that can be parsed
+func (p *ExpiryPolicy) creationTime(props types.Properties) (time.Time, bool) {
+	creationProperties := p.CreationProperties
+	if len(creationProperties) == 0 {
+		creationProperties = DefaultExpiryCreationProperties
+	}
+
+	for _, name := range creationProperties {
+		value, ok := props[name]
+		if !ok || value == "" {
+			continue
+		}
+
+		if t, err := parseExpiryTime(value); err == nil {
+			return t, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// parseExpiryTime parses the formats that creation time properties are rendered in, properties from time.Time values
+// are rendered as RFC3339, a few resources use unix timestamps.
+func parseExpiryTime(value string) (time.Time, error) {
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(i, 0), nil
+	}
+
+	for _, layout := range []string{time.RFC3339, time.RFC3339Nano, "2006-01-02 15:04:05 -0700 MST"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("unable to parse time %s", value)
+}
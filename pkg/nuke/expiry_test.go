@@ -0,0 +1,167 @@
+package nuke
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+func TestExpiryPolicy_Evaluate(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := &ExpiryPolicy{
+		ExpiresAtTag: "nuke:expires-at",
+		TTLTag:       "nuke:ttl",
+		Now:          func() time.Time { return now },
+	}
+
+	cases := []struct {
+		name  string
+		props types.Properties
+		want  ExpiryState
+	}{
+		{
+			name:  "no-tags",
+			props: types.NewProperties().Set("Name", "foo"),
+			want:  ExpiryNotApplicable,
+		},
+		{
+			name:  "expires-at-past",
+			props: types.NewProperties().Set("tag:nuke:expires-at", "2024-05-01T00:00:00Z"),
+			want:  ExpiryExpired,
+		},
+		{
+			name:  "expires-at-future",
+			props: types.NewProperties().Set("tag:nuke:expires-at", "2024-07-01T00:00:00Z"),
+			want:  ExpiryUnexpired,
+		},
+		{
+			name:  "expires-at-malformed",
+			props: types.NewProperties().Set("tag:nuke:expires-at", "next tuesday"),
+			want:  ExpiryMalformed,
+		},
+		{
+			name: "ttl-expired",
+			props: types.NewProperties().
+				Set("tag:nuke:ttl", "72h").
+				Set("LaunchTime", now.Add(-96*time.Hour)),
+			want: ExpiryExpired,
+		},
+		{
+			name: "ttl-unexpired",
+			props: types.NewProperties().
+				Set("tag:nuke:ttl", "72h").
+				Set("CreationDate", now.Add(-24*time.Hour)),
+			want: ExpiryUnexpired,
+		},
+		{
+			name: "ttl-unix-creation-time",
+			props: types.NewProperties().
+				Set("tag:nuke:ttl", "1h").
+				Set("CreatedAt", now.Add(-2*time.Hour).Unix()),
+			want: ExpiryExpired,
+		},
+		{
+			name: "ttl-malformed",
+			props: types.NewProperties().
+				Set("tag:nuke:ttl", "three days").
+				Set("LaunchTime", now),
+			want: ExpiryMalformed,
+		},
+		{
+			name:  "ttl-no-creation-time",
+			props: types.NewProperties().Set("tag:nuke:ttl", "72h"),
+			want:  ExpiryMalformed,
+		},
+		{
+			name: "expires-at-precedence",
+			props: types.NewProperties().
+				Set("tag:nuke:expires-at", "2024-07-01T00:00:00Z").
+				Set("tag:nuke:ttl", "1h").
+				Set("LaunchTime", now.Add(-96*time.Hour)),
+			want: ExpiryUnexpired,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state, _ := policy.Evaluate(tc.props)
+			assert.Equal(t, tc.want, state)
+		})
+	}
+}
+
+func TestExpiryPolicy_CreationProperties(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	policy := &ExpiryPolicy{
+		TTLTag:             "nuke:ttl",
+		CreationProperties: []string{"Born"},
+		Now:                func() time.Time { return now },
+	}
+
+	state, _ := policy.Evaluate(types.NewProperties().
+		Set("tag:nuke:ttl", "1h").
+		Set("LaunchTime", now.Add(-2*time.Hour)))
+	assert.Equal(t, ExpiryMalformed, state)
+
+	state, _ = policy.Evaluate(types.NewProperties().
+		Set("tag:nuke:ttl", "1h").
+		Set("Born", now.Add(-2*time.Hour)))
+	assert.Equal(t, ExpiryExpired, state)
+}
+
+func TestNuke_FilterExpiry(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{
+		"TestResource": {
+			filter.Filter{Property: "Name", Type: filter.Exact, Value: "keep"},
+		},
+	}, nil)
+
+	n.RegisterExpiryPolicy(&ExpiryPolicy{ExpiresAtTag: "nuke:expires-at"})
+
+	unexpired := &queue.Item{
+		Resource: &testTaggedResource{name: "other", expiresAt: "2999-01-01T00:00:00Z"},
+		State:    queue.ItemStateNew,
+		Type:     "TestResource",
+	}
+	assert.NoError(t, n.Filter(unexpired))
+	assert.Equal(t, queue.ItemStateFiltered, unexpired.State)
+	assert.Contains(t, unexpired.Reason, "not expired until")
+
+	expired := &queue.Item{
+		Resource: &testTaggedResource{name: "other", expiresAt: "2000-01-01T00:00:00Z"},
+		State:    queue.ItemStateNew,
+		Type:     "TestResource",
+	}
+	assert.NoError(t, n.Filter(expired))
+	assert.Equal(t, queue.ItemStateNew, expired.State)
+
+	// Note: filters from the config always take precedence over the expiry
+	kept := &queue.Item{
+		Resource: &testTaggedResource{name: "keep", expiresAt: "2000-01-01T00:00:00Z"},
+		State:    queue.ItemStateNew,
+		Type:     "TestResource",
+	}
+	assert.NoError(t, n.Filter(kept))
+	assert.Equal(t, queue.ItemStateFiltered, kept.State)
+	assert.Equal(t, "filtered by config", kept.Reason)
+}
+
+type testTaggedResource struct {
+	testResource
+	name      string
+	expiresAt string
+}
+
+func (r *testTaggedResource) Properties() types.Properties {
+	return types.NewProperties().
+		Set("Name", r.name).
+		Set("tag:nuke:expires-at", r.expiresAt)
+}
@@ -0,0 +1,330 @@
+package nuke
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// DependencyRule declares that a resource of the dependent type must be removed before the resource of the dependency
+// type, if the value of the dependent property matches the value of the dependency property. An empty property is the
+// name of the resource. Values that are a comma separated list match any of their entries. Only resources in the same
+// region are matched.
+type DependencyRule struct {
+	Dependent          string
+	DependentProperty  string
+	Dependency         string
+	DependencyProperty string
+}
+
+// DependencyRules are the known dependencies between resources, resources without a known dependency fall back to
+// being retried until their dependencies are gone
+var DependencyRules = []DependencyRule{
+	{Dependent: "EC2NetworkInterface", DependentProperty: "SubnetID", Dependency: "EC2Subnet"},
+	{Dependent: "EC2NetworkInterface", DependentProperty: "VPC", Dependency: "EC2VPC", DependencyProperty: "ID"},
+	{Dependent: "EC2Subnet", DependentProperty: "VpcID", Dependency: "EC2VPC", DependencyProperty: "ID"},
+	{Dependent: "EC2RouteTable", DependentProperty: "vpc:ID", Dependency: "EC2VPC", DependencyProperty: "ID"},
+	{Dependent: "ELBv2", DependentProperty: "ARN", Dependency: "ELBv2TargetGroup",
+		DependencyProperty: "LoadBalancerARNs"},
+	{Dependent: "IAMRolePolicyAttachment", DependentProperty: "RoleName", Dependency: "IAMRole"},
+	{Dependent: "IAMRolePolicyAttachment", DependentProperty: "PolicyArn", Dependency: "IAMPolicy"},
+	{Dependent: "IAMRolePolicy", DependentProperty: "role:RoleName", Dependency: "IAMRole"},
+	{Dependent: "IAMInstanceProfileRole", DependentProperty: "InstanceRole", Dependency: "IAMRole"},
+	{Dependent: "IAMInstanceProfileRole", DependentProperty: "InstanceProfile", Dependency: "IAMInstanceProfile",
+		DependencyProperty: "Name"},
+	{Dependent: "IAMUserPolicyAttachment", DependentProperty: "UserName", Dependency: "IAMUser"},
+	{Dependent: "IAMUserPolicyAttachment", DependentProperty: "PolicyArn", Dependency: "IAMPolicy"},
+	{Dependent: "IAMUserGroupAttachment", DependentProperty: "UserName", Dependency: "IAMUser"},
+	{Dependent: "IAMUserGroupAttachment", DependentProperty: "GroupName", Dependency: "IAMGroup"},
+	{Dependent: "IAMGroupPolicyAttachment", DependentProperty: "GroupName", Dependency: "IAMGroup"},
+	{Dependent: "IAMGroupPolicyAttachment", DependentProperty: "PolicyArn", Dependency: "IAMPolicy"},
+}
+
+// Graph is the instance level dependency graph of the resources that would be removed
+type Graph struct {
+	// Order is the removal order of the resources, dependents come before their dependencies
+	Order []*queue.Item
+
+	// Cycles are the resources whose dependencies form a cycle, the dependencies between them are ignored
+	Cycles []*queue.Item
+
+	dependents   map[*queue.Item][]*queue.Item
+	dependencies map[*queue.Item][]*queue.Item
+}
+
+// BuildGraph returns the dependency graph of the items that would be removed, using the rules to find the
+// dependencies between them
+func BuildGraph(items []*queue.Item, rules []DependencyRule) *Graph {
+	g := &Graph{
+		dependents:   make(map[*queue.Item][]*queue.Item),
+		dependencies: make(map[*queue.Item][]*queue.Item),
+	}
+
+	nodes := make([]*queue.Item, 0, len(items))
+	for _, item := range items {
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateNewDependency:
+			nodes = append(nodes, item)
+		}
+	}
+
+	for _, rule := range rules {
+		index := map[string][]*queue.Item{}
+		for _, item := range nodes {
+			if item.Type != rule.Dependency {
+				continue
+			}
+
+			for _, value := range graphValues(item, rule.DependencyProperty) {
+				key := item.Owner + "\x00" + value
+				index[key] = append(index[key], item)
+			}
+		}
+
+		for _, item := range nodes {
+			if item.Type != rule.Dependent {
+				continue
+			}
+
+			for _, value := range graphValues(item, rule.DependentProperty) {
+				for _, dependency := range index[item.Owner+"\x00"+value] {
+					g.addEdge(item, dependency)
+				}
+			}
+		}
+	}
+
+	g.sort(nodes)
+
+	return g
+}
+
+// Dependents returns the resources that must be removed before the item
+func (g *Graph) Dependents(item *queue.Item) []*queue.Item {
+	return g.dependents[item]
+}
+
+// Dependencies returns the resources that can only be removed after the item
+func (g *Graph) Dependencies(item *queue.Item) []*queue.Item {
+	return g.dependencies[item]
+}
+
+func (g *Graph) addEdge(dependent, dependency *queue.Item) {
+	if dependent == dependency {
+		return
+	}
+
+	for _, existing := range g.dependencies[dependent] {
+		if existing == dependency {
+			return
+		}
+	}
+
+	g.dependencies[dependent] = append(g.dependencies[dependent], dependency)
+	g.dependents[dependency] = append(g.dependents[dependency], dependent)
+}
+
+func (g *Graph) removeEdge(dependent, dependency *queue.Item) {
+	g.dependencies[dependent] = removeItem(g.dependencies[dependent], dependency)
+	g.dependents[dependency] = removeItem(g.dependents[dependency], dependent)
+}
+
+// sort orders the nodes topologically, the order of the nodes is kept where possible. Nodes that are part of a cycle
+// are appended in their original order and the edges between them are dropped.
+func (g *Graph) sort(nodes []*queue.Item) {
+	remaining := make(map[*queue.Item]int, len(nodes))
+	for _, node := range nodes {
+		remaining[node] = len(g.dependents[node])
+	}
+
+	done := make(map[*queue.Item]bool, len(nodes))
+	for len(g.Order) < len(nodes) {
+		progress := false
+		for _, node := range nodes {
+			if done[node] || remaining[node] > 0 {
+				continue
+			}
+
+			done[node] = true
+			progress = true
+			g.Order = append(g.Order, node)
+
+			for _, dependency := range g.dependencies[node] {
+				remaining[dependency]--
+			}
+		}
+
+		if !progress {
+			break
+		}
+	}
+
+	for _, node := range nodes {
+		if !done[node] {
+			g.Cycles = append(g.Cycles, node)
+		}
+	}
+
+	for _, node := range g.Cycles {
+		for _, dependency := range g.dependencies[node] {
+			if !done[dependency] {
+				g.removeEdge(node, dependency)
+			}
+		}
+
+		g.Order = append(g.Order, node)
+	}
+}
+
+// graphNode is a resource in the JSON export of the graph
+type graphNode struct {
+	ID           int    `json:"id"`
+	Owner        string `json:"owner"`
+	Type         string `json:"type"`
+	Name         string `json:"name"`
+	Dependencies []int  `json:"dependencies,omitempty"`
+}
+
+func (g *Graph) ids() map[*queue.Item]int {
+	ids := make(map[*queue.Item]int, len(g.Order))
+	for i, item := range g.Order {
+		ids[item] = i
+	}
+
+	return ids
+}
+
+// WriteJSON writes the graph as JSON, the resources are in removal order and list the ids of the resources that can
+// only be removed after them
+func (g *Graph) WriteJSON(w io.Writer) error {
+	ids := g.ids()
+
+	nodes := make([]graphNode, 0, len(g.Order))
+	for i, item := range g.Order {
+		node := graphNode{
+			ID:    i,
+			Owner: item.Owner,
+			Type:  item.Type,
+			Name:  reviewDisplayName(item),
+		}
+
+		for _, dependency := range g.dependencies[item] {
+			node.Dependencies = append(node.Dependencies, ids[dependency])
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(map[string]interface{}{"nodes": nodes})
+}
+
+// WriteDOT writes the graph in the DOT format of Graphviz, an edge points from a resource to a resource that can only
+// be removed after it
+func (g *Graph) WriteDOT(w io.Writer) error {
+	ids := g.ids()
+
+	var b strings.Builder
+	b.WriteString("digraph aws_nuke {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box];\n")
+
+	for i, item := range g.Order {
+		fmt.Fprintf(&b, "  n%d [label=%q];\n", i, fmt.Sprintf("%s\n%s\n%s", item.Owner, item.Type,
+			reviewDisplayName(item)))
+	}
+
+	for i, item := range g.Order {
+		for _, dependency := range g.dependencies[item] {
+			fmt.Fprintf(&b, "  n%d -> n%d;\n", i, ids[dependency])
+		}
+	}
+
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// graphValues returns the values of the property of the item, a comma separated list is split into its entries
+func graphValues(item *queue.Item, property string) []string {
+	value, err := item.GetProperty(property)
+	if err != nil || value == "" {
+		return nil
+	}
+
+	values := []string{}
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			values = append(values, entry)
+		}
+	}
+
+	return values
+}
+
+func removeItem(items []*queue.Item, item *queue.Item) []*queue.Item {
+	result := items[:0]
+	for _, existing := range items {
+		if existing != item {
+			result = append(result, existing)
+		}
+	}
+
+	return result
+}
+
+// removalOrder returns the items of the queue in the order of the dependency graph, items that are not part of the
+// graph follow in their original order
+func (n *Nuke) removalOrder() []*queue.Item {
+	if n.graph == nil {
+		return n.Queue.GetItems()
+	}
+
+	items := make([]*queue.Item, 0, n.Queue.Total())
+	items = append(items, n.graph.Order...)
+
+	inGraph := make(map[*queue.Item]bool, len(n.graph.Order))
+	for _, item := range n.graph.Order {
+		inGraph[item] = true
+	}
+
+	for _, item := range n.Queue.GetItems() {
+		if !inGraph[item] {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// holdForDependents puts the item on hold while resources that depend on it are still being removed. Dependents that
+// failed do not hold the item, it is removed by retrying instead.
+func (n *Nuke) holdForDependents(item *queue.Item) bool {
+	if n.graph == nil {
+		return false
+	}
+
+	count := 0
+	for _, dependent := range n.graph.Dependents(item) {
+		switch dependent.GetState() {
+		case queue.ItemStateNew, queue.ItemStateNewDependency, queue.ItemStateHold, queue.ItemStatePending,
+			queue.ItemStatePendingDependency, queue.ItemStateWaiting:
+			count++
+		}
+	}
+
+	if count == 0 {
+		return false
+	}
+
+	item.State = queue.ItemStateHold
+	item.Reason = fmt.Sprintf("waiting for %d dependent resources to be removed", count)
+
+	return true
+}
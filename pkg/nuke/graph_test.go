@@ -0,0 +1,164 @@
+package nuke
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+type testGraphResource struct {
+	testResource
+	properties map[string]string
+}
+
+func (r *testGraphResource) String() string {
+	return r.name
+}
+
+func (r *testGraphResource) Properties() types.Properties {
+	properties := types.NewProperties().Set("Name", r.name)
+	for key, value := range r.properties {
+		properties.Set(key, value)
+	}
+
+	return properties
+}
+
+func testGraphItem(owner, resourceType, name string, properties map[string]string) *queue.Item {
+	return &queue.Item{
+		Resource: &testGraphResource{testResource: testResource{name: name}, properties: properties},
+		State:    queue.ItemStateNew,
+		Type:     resourceType,
+		Owner:    owner,
+	}
+}
+
+func testGraphNames(items []*queue.Item) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.Owner+"/"+reviewDisplayName(item))
+	}
+
+	return names
+}
+
+func TestBuildGraph(t *testing.T) {
+	vpc := testGraphItem("us-east-1", "EC2VPC", "vpc-1", map[string]string{"ID": "vpc-1"})
+	subnet := testGraphItem("us-east-1", "EC2Subnet", "subnet-1", map[string]string{"VpcID": "vpc-1"})
+	eni := testGraphItem("us-east-1", "EC2NetworkInterface", "eni-1",
+		map[string]string{"SubnetID": "subnet-1", "VPC": "vpc-1"})
+	otherRegion := testGraphItem("us-west-2", "EC2Subnet", "subnet-2", map[string]string{"VpcID": "vpc-1"})
+	filtered := testGraphItem("us-east-1", "EC2Subnet", "subnet-3", map[string]string{"VpcID": "vpc-1"})
+	filtered.State = queue.ItemStateFiltered
+
+	graph := BuildGraph([]*queue.Item{vpc, subnet, filtered, eni, otherRegion}, DependencyRules)
+
+	assert.Equal(t, []string{"us-east-1/eni-1", "us-west-2/subnet-2", "us-east-1/subnet-1", "us-east-1/vpc-1"},
+		testGraphNames(graph.Order))
+	assert.Empty(t, graph.Cycles)
+
+	assert.ElementsMatch(t, []*queue.Item{subnet, eni}, graph.Dependents(vpc))
+	assert.ElementsMatch(t, []*queue.Item{subnet, vpc}, graph.Dependencies(eni))
+	assert.Empty(t, graph.Dependencies(otherRegion))
+	assert.Empty(t, graph.Dependents(filtered))
+}
+
+func TestBuildGraph_ListProperty(t *testing.T) {
+	lb1 := testGraphItem("us-east-1", "ELBv2", "lb-1", map[string]string{"ARN": "arn:lb-1"})
+	lb2 := testGraphItem("us-east-1", "ELBv2", "lb-2", map[string]string{"ARN": "arn:lb-2"})
+	tg := testGraphItem("us-east-1", "ELBv2TargetGroup", "tg",
+		map[string]string{"LoadBalancerARNs": "arn:lb-1, arn:lb-2"})
+
+	graph := BuildGraph([]*queue.Item{tg, lb1, lb2}, DependencyRules)
+
+	assert.Equal(t, []string{"us-east-1/lb-1", "us-east-1/lb-2", "us-east-1/tg"}, testGraphNames(graph.Order))
+	assert.ElementsMatch(t, []*queue.Item{lb1, lb2}, graph.Dependents(tg))
+}
+
+func TestBuildGraph_Cycle(t *testing.T) {
+	rules := []DependencyRule{
+		{Dependent: "A", DependentProperty: "Uses", Dependency: "B"},
+		{Dependent: "B", DependentProperty: "Uses", Dependency: "A"},
+		{Dependent: "C", DependentProperty: "Uses", Dependency: "A"},
+	}
+
+	a := testGraphItem("global", "A", "a", map[string]string{"Uses": "b"})
+	b := testGraphItem("global", "B", "b", map[string]string{"Uses": "a"})
+	c := testGraphItem("global", "C", "c", map[string]string{"Uses": "a"})
+
+	graph := BuildGraph([]*queue.Item{a, b, c}, rules)
+
+	assert.Equal(t, []string{"global/c", "global/a", "global/b"}, testGraphNames(graph.Order))
+	assert.Equal(t, []*queue.Item{a, b}, graph.Cycles)
+
+	// Note: the edges of the cycle are dropped, the edge from outside the cycle is kept
+	assert.Equal(t, []*queue.Item{c}, graph.Dependents(a))
+	assert.Empty(t, graph.Dependents(b))
+}
+
+func TestNuke_HoldForDependents(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+
+	vpc := testGraphItem("us-east-1", "EC2VPC", "vpc-1", map[string]string{"ID": "vpc-1"})
+	subnet := testGraphItem("us-east-1", "EC2Subnet", "subnet-1", map[string]string{"VpcID": "vpc-1"})
+	unrelated := testGraphItem("us-east-1", "S3Bucket", "bucket", nil)
+	n.Queue = &queue.Queue{Items: []*queue.Item{vpc, unrelated, subnet}}
+
+	assert.False(t, n.holdForDependents(vpc), "without a graph nothing is held")
+	assert.Equal(t, []*queue.Item{vpc, unrelated, subnet}, n.removalOrder())
+
+	n.graph = BuildGraph([]*queue.Item{vpc, subnet}, DependencyRules)
+	assert.Equal(t, []*queue.Item{subnet, vpc, unrelated}, n.removalOrder())
+
+	assert.True(t, n.holdForDependents(vpc))
+	assert.Equal(t, queue.ItemStateHold, vpc.GetState())
+	assert.Equal(t, "waiting for 1 dependent resources to be removed", vpc.GetReason())
+	assert.False(t, n.holdForDependents(subnet))
+
+	subnet.State = queue.ItemStateWaiting
+	assert.True(t, n.holdForDependents(vpc))
+
+	subnet.State = queue.ItemStateFinished
+	assert.False(t, n.holdForDependents(vpc))
+
+	// Note: a failed dependent does not hold its dependencies, they are retried instead
+	subnet.State = queue.ItemStateFailed
+	assert.False(t, n.holdForDependents(vpc))
+}
+
+func TestGraph_Write(t *testing.T) {
+	vpc := testGraphItem("us-east-1", "EC2VPC", "vpc-1", map[string]string{"ID": "vpc-1"})
+	subnet := testGraphItem("us-east-1", "EC2Subnet", "subnet-1", map[string]string{"VpcID": "vpc-1"})
+
+	graph := BuildGraph([]*queue.Item{vpc, subnet}, DependencyRules)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, graph.WriteJSON(out))
+
+	var data struct {
+		Nodes []graphNode `json:"nodes"`
+	}
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &data))
+	assert.Equal(t, []graphNode{
+		{ID: 0, Owner: "us-east-1", Type: "EC2Subnet", Name: "subnet-1", Dependencies: []int{1}},
+		{ID: 1, Owner: "us-east-1", Type: "EC2VPC", Name: "vpc-1"},
+	}, data.Nodes)
+
+	out.Reset()
+	assert.NoError(t, graph.WriteDOT(out))
+
+	dot := out.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph aws_nuke {\n"))
+	assert.Contains(t, dot, `  n0 [label="us-east-1\nEC2Subnet\nsubnet-1"];`)
+	assert.Contains(t, dot, `  n1 [label="us-east-1\nEC2VPC\nvpc-1"];`)
+	assert.Contains(t, dot, "  n0 -> n1;\n")
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+}
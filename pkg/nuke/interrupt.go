@@ -0,0 +1,73 @@
+package nuke
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// Interrupt stops the run gracefully, no new removals are triggered, but resources that are already being removed
+// are waited on until the context of the run is cancelled. It is safe to call from another goroutine, e.g. a signal
+// handler, and more than once.
+func (n *Nuke) Interrupt() {
+	n.interruptOnce.Do(func() {
+		close(n.interrupted)
+	})
+}
+
+// Interrupted returns true once the run has been interrupted
+func (n *Nuke) Interrupted() bool {
+	select {
+	case <-n.interrupted:
+		return true
+	default:
+		return false
+	}
+}
+
+// Removing returns true once the removal of resources has started, before that an interrupted run has nothing to
+// wait on
+func (n *Nuke) Removing() bool {
+	return n.removing.Load()
+}
+
+// printInterrupted prints the final status of the resources of an interrupted run
+func (n *Nuke) printInterrupted() {
+	if n.Queue == nil {
+		return
+	}
+
+	remaining := n.Queue.Count(queue.ItemStateNew, queue.ItemStateNewDependency, queue.ItemStateHold,
+		queue.ItemStatePendingDependency, queue.ItemStatePending, queue.ItemStateWaiting)
+
+	n.log.WithField("_handler", "println").
+		WithFields(logrus.Fields{
+			"failed":    n.Queue.Count(queue.ItemStateFailed),
+			"skipped":   n.Queue.Count(queue.ItemStateFiltered),
+			"finished":  n.Queue.Count(queue.ItemStateFinished),
+			"remaining": remaining,
+		}).
+		Infof("Nuke interrupted: %d failed, %d skipped, %d finished, %d remaining.\n",
+			n.Queue.Count(queue.ItemStateFailed), n.Queue.Count(queue.ItemStateFiltered),
+			n.Queue.Count(queue.ItemStateFinished), remaining)
+}
+
+// handleInterrupt stops the run once it has been interrupted and no removals are in progress anymore, or the context
+// was cancelled because the grace period expired. The remaining resources are left in their current state with the
+// reason updated.
+func (n *Nuke) handleInterrupt(ctx context.Context) error {
+	if !n.Interrupted() {
+		return nil
+	}
+
+	if ctx.Err() == nil && n.Queue.Count(queue.ItemStatePending, queue.ItemStateWaiting) > 0 {
+		return nil
+	}
+
+	n.stop(fmt.Sprintf("not removed: %s", ErrInterrupted))
+
+	return ErrInterrupted
+}
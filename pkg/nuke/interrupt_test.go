@@ -0,0 +1,109 @@
+package nuke
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+func TestNuke_Interrupt(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	n.SetRunSleep(time.Millisecond)
+
+	assert.False(t, n.Interrupted())
+	n.Interrupt()
+	n.Interrupt()
+	assert.True(t, n.Interrupted())
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testResource{name: "remove-me"},
+				State:    queue.ItemStateNew,
+				Type:     "TestResource",
+				Owner:    "us-east-1",
+			},
+		},
+	}
+
+	err := n.run(context.TODO())
+	assert.ErrorIs(t, err, ErrInterrupted)
+
+	item := n.Queue.GetItems()[0]
+	assert.Equal(t, queue.ItemStateNew, item.GetState())
+	assert.Equal(t, "not removed: interrupted", item.GetReason())
+
+	report := n.Report(err)
+	assert.Equal(t, ReportStatusInterrupted, report.Status)
+	assert.Equal(t, map[string]int{"new": 1}, report.Summary)
+}
+
+func TestNuke_InterruptWaitOnDependencies(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true, WaitOnDependencies: true}, filter.Filters{}, nil)
+	n.SetRunSleep(time.Millisecond)
+	n.Interrupt()
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testResource{name: "new"},
+				State:    queue.ItemStateNewDependency,
+				Type:     dependentResourceType,
+				Owner:    "us-east-1",
+			},
+			{
+				Resource: &testResource{name: "pending"},
+				State:    queue.ItemStatePendingDependency,
+				Reason:   "left: 1",
+				Type:     dependentResourceType,
+				Owner:    "us-east-1",
+			},
+		},
+	}
+
+	// Note: the dependencies of the items are gone, they must still not be removed once the run was interrupted
+	err := n.run(context.TODO())
+	assert.ErrorIs(t, err, ErrInterrupted)
+
+	items := n.Queue.GetItems()
+	assert.Equal(t, queue.ItemStateNewDependency, items[0].GetState())
+	assert.Equal(t, "not removed: interrupted", items[0].GetReason())
+	assert.Equal(t, queue.ItemStatePendingDependency, items[1].GetState())
+	assert.Equal(t, "not removed: interrupted", items[1].GetReason())
+
+	report := n.Report(err)
+	assert.Equal(t, ReportStatusInterrupted, report.Status)
+}
+
+func TestNuke_HandleInterrupt(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testResource{name: "in-progress"},
+				State:    queue.ItemStateWaiting,
+				Type:     "TestResource",
+				Owner:    "us-east-1",
+			},
+		},
+	}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	assert.NoError(t, n.handleInterrupt(ctx))
+
+	// Note: removals in progress are waited on until the grace period expires and the context is cancelled
+	n.Interrupt()
+	assert.NoError(t, n.handleInterrupt(ctx))
+
+	cancel()
+	assert.ErrorIs(t, n.handleInterrupt(ctx), ErrInterrupted)
+	assert.Equal(t, queue.ItemStateWaiting, n.Queue.GetItems()[0].GetState())
+}
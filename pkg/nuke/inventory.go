@@ -0,0 +1,273 @@
+package nuke
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+// Snapshot is the inventory of an account, it is the result of a scan without removing anything. Two snapshots of
+// the same account are compared with DiffSnapshots.
+type Snapshot struct {
+	AccountID string             `json:"account_id"`
+	CreatedAt time.Time          `json:"created_at"`
+	Resources []SnapshotResource `json:"resources"`
+}
+
+// SnapshotResource is a single resource in the snapshot, the name is the name of the resource, or its identifying
+// property if it has no name
+type SnapshotResource struct {
+	Owner      string            `json:"owner"`
+	Type       string            `json:"type"`
+	Name       string            `json:"name"`
+	State      string            `json:"state"`
+	Reason     string            `json:"reason,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// NewSnapshot returns the snapshot of the items found by a scan, the resources are sorted by region, type and name
+func NewSnapshot(accountID string, items []*queue.Item) *Snapshot {
+	snapshot := &Snapshot{
+		AccountID: accountID,
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+		Resources: make([]SnapshotResource, 0, len(items)),
+	}
+
+	for _, item := range items {
+		snapshotResource := SnapshotResource{
+			Owner:  item.Owner,
+			Type:   item.Type,
+			Name:   reviewDisplayName(item),
+			State:  item.GetState().String(),
+			Reason: item.GetReason(),
+		}
+
+		if getter, ok := item.Resource.(resource.PropertyGetter); ok {
+			snapshotResource.Properties = snapshotProperties(getter.Properties())
+		}
+
+		snapshot.Resources = append(snapshot.Resources, snapshotResource)
+	}
+
+	slices.SortStableFunc(snapshot.Resources, func(a, b SnapshotResource) int {
+		return strings.Compare(a.Owner+"\x00"+a.Type+"\x00"+a.Name, b.Owner+"\x00"+b.Type+"\x00"+b.Name)
+	})
+
+	return snapshot
+}
+
+// ReadSnapshot reads a snapshot from the path
+func ReadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return nil, fmt.Errorf("unable to parse snapshot %s: %w", path, err)
+	}
+
+	return snapshot, nil
+}
+
+// Write writes the snapshot as JSON to the path
+func (s *Snapshot) Write(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// SnapshotDiff is the difference between two snapshots, grouped by region and resource type
+type SnapshotDiff struct {
+	Groups []SnapshotDiffGroup `json:"groups"`
+}
+
+// SnapshotDiffGroup are the resources of a single type in a single region that were added, removed or changed
+type SnapshotDiffGroup struct {
+	Owner   string             `json:"owner"`
+	Type    string             `json:"type"`
+	Added   []SnapshotResource `json:"added,omitempty"`
+	Removed []SnapshotResource `json:"removed,omitempty"`
+	Changed []SnapshotChange   `json:"changed,omitempty"`
+}
+
+// SnapshotChange is a resource that is in both snapshots, but whose state or properties changed
+type SnapshotChange struct {
+	Name    string           `json:"name"`
+	Changes []PropertyChange `json:"changes"`
+}
+
+// PropertyChange is the old and new value of a property, an empty value means the property was not set. The state of
+// the resource is reported as the property "(state)".
+type PropertyChange struct {
+	Property string `json:"property"`
+	Old      string `json:"old"`
+	New      string `json:"new"`
+}
+
+// snapshotStateProperty is the name under which a change of the state of a resource is reported
+const snapshotStateProperty = "(state)"
+
+// Empty returns true if there is no difference between the snapshots
+func (d *SnapshotDiff) Empty() bool {
+	return len(d.Groups) == 0
+}
+
+// DiffSnapshots compares two snapshots. Resources are matched by region, type and name, resources with the same name
+// are matched in the order they appear in the snapshots.
+func DiffSnapshots(before, after *Snapshot) *SnapshotDiff {
+	beforeIndex := snapshotIndex(before)
+	afterIndex := snapshotIndex(after)
+
+	groups := map[string]*SnapshotDiffGroup{}
+	group := func(r SnapshotResource) *SnapshotDiffGroup {
+		key := r.Owner + "\x00" + r.Type
+		if _, ok := groups[key]; !ok {
+			groups[key] = &SnapshotDiffGroup{Owner: r.Owner, Type: r.Type}
+		}
+
+		return groups[key]
+	}
+
+	for key, r := range beforeIndex {
+		other, ok := afterIndex[key]
+		if !ok {
+			g := group(r)
+			g.Removed = append(g.Removed, r)
+			continue
+		}
+
+		if changes := snapshotChanges(r, other); len(changes) > 0 {
+			g := group(r)
+			g.Changed = append(g.Changed, SnapshotChange{Name: r.Name, Changes: changes})
+		}
+	}
+
+	for key, r := range afterIndex {
+		if _, ok := beforeIndex[key]; !ok {
+			g := group(r)
+			g.Added = append(g.Added, r)
+		}
+	}
+
+	diff := &SnapshotDiff{Groups: make([]SnapshotDiffGroup, 0, len(groups))}
+	for _, g := range groups {
+		slices.SortFunc(g.Added, func(a, b SnapshotResource) int { return strings.Compare(a.Name, b.Name) })
+		slices.SortFunc(g.Removed, func(a, b SnapshotResource) int { return strings.Compare(a.Name, b.Name) })
+		slices.SortFunc(g.Changed, func(a, b SnapshotChange) int { return strings.Compare(a.Name, b.Name) })
+
+		diff.Groups = append(diff.Groups, *g)
+	}
+
+	slices.SortFunc(diff.Groups, func(a, b SnapshotDiffGroup) int {
+		return strings.Compare(a.Owner+"\x00"+a.Type, b.Owner+"\x00"+b.Type)
+	})
+
+	return diff
+}
+
+// Print writes the difference in a human-readable format, added resources are prefixed with "+", removed resources
+// with "-" and changed resources with "~"
+func (d *SnapshotDiff) Print(w io.Writer) error {
+	var b strings.Builder
+
+	if d.Empty() {
+		b.WriteString("No differences found.\n")
+	}
+
+	for _, g := range d.Groups {
+		fmt.Fprintf(&b, "%s - %s: %d added, %d removed, %d changed\n", g.Owner, g.Type,
+			len(g.Added), len(g.Removed), len(g.Changed))
+
+		for _, r := range g.Added {
+			fmt.Fprintf(&b, "  + %s\n", r.Name)
+		}
+
+		for _, r := range g.Removed {
+			fmt.Fprintf(&b, "  - %s\n", r.Name)
+		}
+
+		for _, change := range g.Changed {
+			fmt.Fprintf(&b, "  ~ %s\n", change.Name)
+			for _, c := range change.Changes {
+				fmt.Fprintf(&b, "      %s: %q -> %q\n", c.Property, c.Old, c.New)
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// snapshotProperties returns the properties of a resource without the internal ones, such as the tag prefix, which
+// start with an underscore
+func snapshotProperties(properties types.Properties) map[string]string {
+	result := make(map[string]string, len(properties))
+	for key, value := range properties {
+		if strings.HasPrefix(key, "_") {
+			continue
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+// snapshotIndex returns the resources of the snapshot by region, type, name and the occurrence of the name
+func snapshotIndex(snapshot *Snapshot) map[string]SnapshotResource {
+	index := make(map[string]SnapshotResource, len(snapshot.Resources))
+	occurrences := map[string]int{}
+
+	for _, r := range snapshot.Resources {
+		key := r.Owner + "\x00" + r.Type + "\x00" + r.Name
+		index[fmt.Sprintf("%s\x00%d", key, occurrences[key])] = r
+		occurrences[key]++
+	}
+
+	return index
+}
+
+// snapshotChanges returns the changes of the state and the properties of the resource, sorted by property
+func snapshotChanges(before, after SnapshotResource) []PropertyChange {
+	var changes []PropertyChange
+
+	if before.State != after.State {
+		changes = append(changes, PropertyChange{Property: snapshotStateProperty, Old: before.State, New: after.State})
+	}
+
+	properties := map[string]bool{}
+	for property := range before.Properties {
+		properties[property] = true
+	}
+
+	for property := range after.Properties {
+		properties[property] = true
+	}
+
+	for property := range properties {
+		if before.Properties[property] != after.Properties[property] {
+			changes = append(changes, PropertyChange{
+				Property: property,
+				Old:      before.Properties[property],
+				New:      after.Properties[property],
+			})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b PropertyChange) int { return strings.Compare(a.Property, b.Property) })
+
+	return changes
+}
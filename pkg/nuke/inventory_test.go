@@ -0,0 +1,130 @@
+package nuke
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	snapshot := NewSnapshot("123456789012", testReviewItems())
+
+	assert.Equal(t, "123456789012", snapshot.AccountID)
+	assert.Len(t, snapshot.Resources, 5)
+	assert.Equal(t, SnapshotResource{
+		Owner:      "global",
+		Type:       "IAMRole",
+		Name:       "kept",
+		State:      "filtered",
+		Properties: map[string]string{"Name": "kept"},
+	}, snapshot.Resources[0])
+	assert.Equal(t, "us-east-1", snapshot.Resources[3].Owner)
+	assert.Equal(t, "i-1", snapshot.Resources[3].Name)
+
+	path := filepath.Join(t.TempDir(), "inventory.json")
+	assert.NoError(t, snapshot.Write(path))
+
+	read, err := ReadSnapshot(path)
+	assert.NoError(t, err)
+	assert.Equal(t, snapshot, read)
+}
+
+func TestDiffSnapshots(t *testing.T) {
+	before := &Snapshot{
+		AccountID: "123456789012",
+		Resources: []SnapshotResource{
+			{Owner: "global", Type: "IAMRole", Name: "role", State: "new"},
+			{Owner: "us-east-1", Type: "EC2Instance", Name: "i-1", State: "new",
+				Properties: map[string]string{"InstanceType": "t3.micro", "tag:Team": "a"}},
+			{Owner: "us-east-1", Type: "EC2Instance", Name: "i-2", State: "new"},
+			{Owner: "us-east-1", Type: "S3Bucket", Name: "bucket", State: "new"},
+		},
+	}
+
+	after := &Snapshot{
+		AccountID: "123456789012",
+		Resources: []SnapshotResource{
+			{Owner: "global", Type: "IAMRole", Name: "role", State: "filtered"},
+			{Owner: "us-east-1", Type: "EC2Instance", Name: "i-1", State: "new",
+				Properties: map[string]string{"InstanceType": "t3.large"}},
+			{Owner: "us-east-1", Type: "EC2Instance", Name: "i-3", State: "new"},
+			{Owner: "us-west-2", Type: "S3Bucket", Name: "bucket", State: "new"},
+		},
+	}
+
+	diff := DiffSnapshots(before, after)
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []SnapshotDiffGroup{
+		{
+			Owner: "global",
+			Type:  "IAMRole",
+			Changed: []SnapshotChange{{Name: "role", Changes: []PropertyChange{
+				{Property: "(state)", Old: "new", New: "filtered"},
+			}}},
+		},
+		{
+			Owner:   "us-east-1",
+			Type:    "EC2Instance",
+			Added:   []SnapshotResource{after.Resources[2]},
+			Removed: []SnapshotResource{before.Resources[2]},
+			Changed: []SnapshotChange{{Name: "i-1", Changes: []PropertyChange{
+				{Property: "InstanceType", Old: "t3.micro", New: "t3.large"},
+				{Property: "tag:Team", Old: "a", New: ""},
+			}}},
+		},
+		{
+			Owner:   "us-east-1",
+			Type:    "S3Bucket",
+			Removed: []SnapshotResource{before.Resources[3]},
+		},
+		{
+			Owner: "us-west-2",
+			Type:  "S3Bucket",
+			Added: []SnapshotResource{after.Resources[3]},
+		},
+	}, diff.Groups)
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, diff.Print(out))
+	assert.Equal(t, `global - IAMRole: 0 added, 0 removed, 1 changed
+  ~ role
+      (state): "new" -> "filtered"
+us-east-1 - EC2Instance: 1 added, 1 removed, 1 changed
+  + i-3
+  - i-2
+  ~ i-1
+      InstanceType: "t3.micro" -> "t3.large"
+      tag:Team: "a" -> ""
+us-east-1 - S3Bucket: 0 added, 1 removed, 0 changed
+  - bucket
+us-west-2 - S3Bucket: 1 added, 0 removed, 0 changed
+  + bucket
+`, out.String())
+}
+
+func TestDiffSnapshots_SameName(t *testing.T) {
+	items := []*queue.Item{
+		{Resource: &testResource{name: "a"}, State: queue.ItemStateNew, Type: "Unnamed", Owner: "global"},
+		{Resource: &testResource{name: "b"}, State: queue.ItemStateNew, Type: "Unnamed", Owner: "global"},
+	}
+
+	before := NewSnapshot("123456789012", items)
+	after := NewSnapshot("123456789012", items[:1])
+
+	diff := DiffSnapshots(before, before)
+	assert.True(t, diff.Empty())
+
+	out := &bytes.Buffer{}
+	assert.NoError(t, diff.Print(out))
+	assert.Equal(t, "No differences found.\n", out.String())
+
+	// Note: resources without a name are matched in order, the second one is reported as removed
+	diff = DiffSnapshots(before, after)
+	assert.Len(t, diff.Groups, 1)
+	assert.Len(t, diff.Groups[0].Removed, 1)
+	assert.Empty(t, diff.Groups[0].Added)
+}
@@ -0,0 +1,722 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	"github.com/ekristen/libnuke/pkg/scanner"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// ErrRemovalWindowClosed is returned by Run when the removal window closed before all resources were removed.
+var ErrRemovalWindowClosed = errors.New("removal window closed")
+
+// ErrValidation is returned by Run when the validation before the scan failed, e.g. the account is blocklisted.
+var ErrValidation = errors.New("validation failed")
+
+// ErrRemovalFailed is returned by Run when resources could not be removed.
+var ErrRemovalFailed = errors.New("failed")
+
+// ErrInterrupted is returned by Run when the run was interrupted, e.g. by a signal, before all resources were removed.
+var ErrInterrupted = errors.New("interrupted")
+
+// RemovalWindow returns an error if new removals must not be triggered at the given time.
+type RemovalWindow func(now time.Time) error
+
+// Nuke wraps the libnuke Nuke implementation to add behaviors that are specific to aws-nuke. The libnuke
+// implementation evaluates a single set of filters and settings for every resource, whereas aws-nuke supports filters
+// and settings that are scoped to the region (scanner owner) that a resource was discovered in. To do so the scan and
+// filter are implemented here. The removal, the dependency and the wait handling of the items are delegated to
+// libnuke, only the order of the queue and the checks between its iterations are implemented here, as libnuke does not
+// expose hooks into its run loop.
+type Nuke struct {
+	*libnuke.Nuke
+
+	regionFilters       map[string]filter.Filters
+	regionFilterSources map[string]config.FilterSources
+	regionSettings      map[string]*libsettings.Settings
+	expiryPolicy        *ExpiryPolicy
+	removalWindow       RemovalWindow
+	policyExport        *PolicyExportOptions
+	review              Review
+	removeTimeout       RemoveTimeout
+	runTimeout          time.Duration
+	graph               *Graph // graph is the dependency graph of the resources, it is built when the removal starts
+
+	deadlines map[*queue.Item]time.Time // deadlines are the removal deadlines of items with a removal timeout
+	timedOut  map[*queue.Item]bool      // timedOut are the items whose removal timed out, they are not retried
+
+	// artifacts are the artifacts created for an item by aws-nuke itself rather than by the resource, e.g. the
+	// exported policies, they are added to the report
+	artifacts map[*queue.Item][]BackupArtifact
+
+	startedAt time.Time // startedAt is the time the run was started, it is used for the report
+	stopped   bool      // stopped is set when the run was stopped before all resources were removed
+
+	interrupted   chan struct{} // interrupted is closed once the run is interrupted
+	interruptOnce sync.Once
+	removing      atomic.Bool // removing is set once the removal of resources has started
+
+	log      *logrus.Entry
+	runSleep time.Duration
+
+	failedCount  int // failedCount is used to track how many times we've retried all failed resources
+	waitingCount int // waitingCount is used to track how many times we've waiting for resources to move states
+}
+
+// New returns an instance of Nuke that is properly configured for initial use. The filters and settings are the
+// defaults used for any region that does not have region scoped filters or settings registered.
+func New(params *libnuke.Parameters, filters filter.Filters, settings *libsettings.Settings) *Nuke {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	return &Nuke{
+		Nuke:                libnuke.New(params, filters, settings),
+		regionFilters:       make(map[string]filter.Filters),
+		regionFilterSources: make(map[string]config.FilterSources),
+		regionSettings:      make(map[string]*libsettings.Settings),
+		artifacts:           make(map[*queue.Item][]BackupArtifact),
+		interrupted:         make(chan struct{}),
+		deadlines:           make(map[*queue.Item]time.Time),
+		timedOut:            make(map[*queue.Item]bool),
+		log:                 logger.WithField("component", "nuke"),
+		runSleep:            5 * time.Second,
+	}
+}
+
+// SetLogger sets the logger for both aws-nuke and the underlying libnuke instance.
+func (n *Nuke) SetLogger(logger *logrus.Entry) {
+	n.log = logger
+	n.Nuke.SetLogger(logger)
+}
+
+// SetRunSleep sets the sleep duration between runs of the queue.
+func (n *Nuke) SetRunSleep(duration time.Duration) {
+	n.runSleep = duration
+	n.Nuke.SetRunSleep(duration)
+}
+
+// RegisterRegionFilters registers the filters to use for resources discovered by the scanner with the region as
+// its owner. They replace the default filters for that region entirely.
+func (n *Nuke) RegisterRegionFilters(region string, filters filter.Filters) {
+	n.regionFilters[region] = filters
+}
+
+// RegisterRegionFilterSources registers the filters to use for resources discovered by the scanner with the region as
+// its owner, along with where each filter was defined in the configuration. This allows the reason of a filtered
+// resource to name the filter that matched it.
+func (n *Nuke) RegisterRegionFilterSources(region string, sources config.FilterSources) {
+	n.regionFilters[region] = sources.Filters()
+	n.regionFilterSources[region] = sources
+}
+
+// RegisterRegionSettings registers the settings to use for resources discovered by the scanner with the region as
+// its owner. They replace the default settings for that region entirely.
+func (n *Nuke) RegisterRegionSettings(region string, settings *libsettings.Settings) {
+	n.regionSettings[region] = settings
+}
+
+// RegisterExpiryPolicy registers the policy used to decide if a resource carrying an expiry tag is eligible for
+// removal. The policy is only evaluated for resources that were not filtered by config.
+func (n *Nuke) RegisterExpiryPolicy(policy *ExpiryPolicy) {
+	n.expiryPolicy = policy
+}
+
+// RegisterRemovalWindow registers a check that is evaluated before triggering removals. Once it returns an error no
+// new removals are triggered, resources that are already being removed are waited on, after which the run stops
+// with ErrRemovalWindowClosed.
+func (n *Nuke) RegisterRemovalWindow(window RemovalWindow) {
+	n.removalWindow = window
+}
+
+// FiltersFor returns the filters that apply to resources owned by the given region.
+func (n *Nuke) FiltersFor(owner string) filter.Filters {
+	if filters, ok := n.regionFilters[owner]; ok {
+		return filters
+	}
+
+	return n.Filters
+}
+
+// SettingsFor returns the settings that apply to resources owned by the given region.
+func (n *Nuke) SettingsFor(owner string) *libsettings.Settings {
+	if settings, ok := n.regionSettings[owner]; ok {
+		return settings
+	}
+
+	return n.Settings
+}
+
+// Run is the main entry point, it mirrors the libnuke Run, but uses the region aware Scan and queue handling. If the
+// run is interrupted, the error returned wraps ErrInterrupted.
+func (n *Nuke) Run(ctx context.Context) (err error) {
+	n.startedAt = time.Now().UTC()
+
+	defer func() {
+		if err == nil || !n.Interrupted() {
+			return
+		}
+
+		if !errors.Is(err, ErrInterrupted) {
+			err = fmt.Errorf("%w: %w", ErrInterrupted, err)
+		}
+
+		n.printInterrupted()
+	}()
+
+	n.Version()
+
+	printLog := n.log.WithField("_handler", "println")
+
+	if err := n.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrValidation, err)
+	}
+
+	if err := n.Prompt(); err != nil {
+		return err
+	}
+
+	printLog.Info("starting scan for resources")
+
+	if err := n.Scan(ctx); err != nil {
+		return err
+	}
+
+	if n.Interrupted() {
+		return ErrInterrupted
+	}
+
+	if n.Queue.Count(queue.ItemStateNew, queue.ItemStateNewDependency) == 0 {
+		printLog.Info("No resource to delete.")
+		return nil
+	}
+
+	if n.review != nil {
+		if err := n.review(n.Queue.GetItems()); err != nil {
+			return err
+		}
+
+		if n.Queue.Count(queue.ItemStateNew, queue.ItemStateNewDependency) == 0 {
+			printLog.Info("No resource to delete after the review.")
+			return nil
+		}
+	}
+
+	if !n.Parameters.NoDryRun {
+		printLog.Info("The above resources would be deleted with the supplied configuration. " +
+			"Provide --no-dry-run to actually destroy resources.")
+		return nil
+	}
+
+	if err := n.Prompt(); err != nil {
+		return err
+	}
+
+	if n.Interrupted() {
+		return ErrInterrupted
+	}
+
+	n.removing.Store(true)
+
+	if err := n.run(ctx); err != nil {
+		return err
+	}
+
+	printLog.
+		WithFields(logrus.Fields{
+			"failed":   n.Queue.Count(queue.ItemStateFailed),
+			"skipped":  n.Queue.Count(queue.ItemStateFiltered),
+			"finished": n.Queue.Count(queue.ItemStateFinished),
+		}).
+		Infof("Nuke complete: %d failed, %d skipped, %d finished.\n",
+			n.Queue.Count(queue.ItemStateFailed), n.Queue.Count(queue.ItemStateFiltered),
+			n.Queue.Count(queue.ItemStateFinished))
+
+	return nil
+}
+
+// Validate runs the libnuke validation and additionally validates all the region scoped filters.
+func (n *Nuke) Validate() error {
+	if err := n.Nuke.Validate(); err != nil {
+		return err
+	}
+
+	for region, filters := range n.regionFilters {
+		if err := filters.Validate(); err != nil {
+			return fmt.Errorf("region %s: %w", region, err)
+		}
+	}
+
+	return nil
+}
+
+// Scan runs all registered scanners, filters the discovered resources using the filters for the region of the
+// scanner and prints the current status of the resources.
+func (n *Nuke) Scan(ctx context.Context) error {
+	itemQueue := queue.New()
+
+	for _, scanners := range n.Scanners {
+		for _, actualScanner := range scanners {
+			if err := n.runScanner(ctx, actualScanner, itemQueue); err != nil {
+				return err
+			}
+		}
+	}
+
+	n.log.WithField("_handler", "println").
+		WithFields(logrus.Fields{
+			"total":    itemQueue.Total(),
+			"nukeable": itemQueue.Count(queue.ItemStateNew, queue.ItemStateNewDependency),
+			"filtered": itemQueue.Count(queue.ItemStateFiltered),
+		}).
+		Infof("Scan complete: %d total, %d nukeable, %d filtered.\n",
+			itemQueue.Total(), itemQueue.Count(queue.ItemStateNew, queue.ItemStateNewDependency),
+			itemQueue.Count(queue.ItemStateFiltered))
+
+	n.Queue = itemQueue
+
+	return nil
+}
+
+// runScanner runs a single scanner and processes the items that are returned from it
+func (n *Nuke) runScanner(ctx context.Context, resourceScanner *scanner.Scanner, itemQueue *queue.Queue) error {
+	if err := resourceScanner.Run(ctx); err != nil {
+		return err
+	}
+
+	for item := range resourceScanner.Items {
+		// Experimental Feature
+		if n.Parameters.WaitOnDependencies {
+			reg := registry.GetRegistration(item.Type)
+			if len(reg.DependsOn) > 0 {
+				item.State = queue.ItemStateNewDependency
+			}
+		}
+
+		if sGetter, ok := item.Resource.(resource.SettingsGetter); ok {
+			sGetter.Settings(n.SettingsFor(item.Owner).Get(item.Type))
+		}
+
+		itemQueue.Items = append(itemQueue.Items, item)
+		if err := n.Filter(item); err != nil {
+			return err
+		}
+
+		// If quiet and filtered, skip printing to screen
+		if n.Parameters.Quiet && item.State == queue.ItemStateFiltered {
+			continue
+		}
+
+		item.Print()
+	}
+
+	return nil
+}
+
+// Filter filters a resource using the resource filter function and the filters of the region that owns the item.
+func (n *Nuke) Filter(item *queue.Item) error {
+	log := n.log.
+		WithField("handler", "Filter").
+		WithField("type", item.Type).
+		WithField("owner", item.Owner)
+
+	if r, ok := item.Resource.(resource.LegacyStringer); ok {
+		log = log.WithField("item", r.String())
+	}
+
+	if checker, ok := item.Resource.(resource.Filter); ok {
+		log.Trace("resource had filter function")
+		if err := checker.Filter(); err != nil {
+			log.Trace("resource was filtered by resource filter")
+			item.State = queue.ItemStateFiltered
+			item.Reason = err.Error()
+
+			// Not returning the error, since it could be because of a failed request to the API. We do not want
+			// to block the whole nuking, because of an issue on AWS side.
+			return nil
+		}
+	}
+
+	matched, source, err := n.filterByConfig(item, log)
+	if err != nil {
+		return err
+	}
+
+	if matched {
+		item.State = queue.ItemStateFiltered
+		item.Reason = "filtered by config"
+
+		if source != nil {
+			item.Reason = fmt.Sprintf("filtered by config: %s", source)
+			log = log.WithFields(logrus.Fields{
+				"filter_source": source.Source,
+				"filter_type":   source.ResourceType,
+				"filter_index":  source.Index,
+				"filter_file":   source.File,
+				"filter_line":   source.Line,
+			})
+		}
+
+		log.Trace("resource was filtered by config")
+		return nil
+	}
+
+	n.filterByExpiry(item, log)
+
+	return nil
+}
+
+// filterByConfig returns true if any of the configured filters for the region that owns the item match, along with
+// the source of the filter that matched if it is known. When filter groups are used, no single filter is responsible
+// for the match, so no source is returned.
+func (n *Nuke) filterByConfig(item *queue.Item, log *logrus.Entry) (bool, *config.FilterSource, error) {
+	filters := n.FiltersFor(item.Owner)
+
+	if n.Parameters.UseFilterGroups {
+		matched, err := filters.Match(item.Type, item, log)
+		return matched, nil, err
+	}
+
+	for i, f := range filters.Get(item.Type) {
+		prop, err := item.GetProperty(f.Property)
+		if err != nil {
+			log.WithError(err).Warnf("unable to get property: %s", f.Property)
+			continue
+		}
+
+		match, err := f.Match(prop)
+		if err != nil {
+			return false, nil, err
+		}
+
+		if f.Invert {
+			match = !match
+		}
+
+		if match {
+			log.Trace("filter matched")
+			return true, n.filterSource(item.Owner, item.Type, i), nil
+		}
+	}
+
+	return false, nil, nil
+}
+
+// filterSource returns the source of the filter at the index of the filters for the resource type, the index is
+// relative to the global filters followed by the resource type filters, the same order as filter.Filters Get.
+func (n *Nuke) filterSource(owner, resourceType string, index int) *config.FilterSource {
+	sources, ok := n.regionFilterSources[owner]
+	if !ok {
+		return nil
+	}
+
+	list := sources.Get(resourceType)
+	if index >= len(list) {
+		return nil
+	}
+
+	return &list[index]
+}
+
+// filterByExpiry applies the expiry policy, if one is registered, to an item that was not filtered by config.
+// Resources that have not expired yet, or that have a malformed expiry, are filtered.
+func (n *Nuke) filterByExpiry(item *queue.Item, log *logrus.Entry) {
+	if n.expiryPolicy == nil {
+		return
+	}
+
+	getter, ok := item.Resource.(resource.PropertyGetter)
+	if !ok {
+		return
+	}
+
+	state, reason := n.expiryPolicy.Evaluate(getter.Properties())
+	switch state {
+	case ExpiryUnexpired:
+		log.Trace("resource was filtered by expiry policy")
+		item.State = queue.ItemStateFiltered
+		item.Reason = reason
+	case ExpiryMalformed:
+		log.Warnf("resource has a malformed expiry: %s", reason)
+		item.State = queue.ItemStateFiltered
+		item.Reason = reason
+	case ExpiryExpired:
+		log.Tracef("resource has expired: %s", reason)
+	}
+}
+
+// run handles the processing and loop of the queue of items
+func (n *Nuke) run(ctx context.Context) error {
+	if n.runSleep == 0 {
+		n.runSleep = 5 * time.Second
+	}
+
+	if err := n.exportPolicies(ctx); err != nil {
+		return err
+	}
+
+	n.graph = BuildGraph(n.Queue.GetItems(), DependencyRules)
+	if len(n.graph.Cycles) > 0 {
+		n.log.Warnf("the dependencies of %d resources form a cycle, they are removed by retrying", len(n.graph.Cycles))
+	}
+
+	if n.runTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, n.runTimeout)
+		defer cancel()
+	}
+
+	for {
+		if err := n.handleRunTimeout(ctx); err != nil {
+			return err
+		}
+
+		n.HandleQueue(ctx)
+
+		if err := n.handleRemovalWindow(); err != nil {
+			return err
+		}
+
+		if err := n.handleInterrupt(ctx); err != nil {
+			return err
+		}
+
+		if err := n.handleFailure(); err != nil {
+			return err
+		}
+
+		if err := n.handleWaiting(); err != nil {
+			return err
+		}
+
+		unfinishedCount := n.Queue.Count(queue.ItemStateNew, queue.ItemStateNewDependency,
+			queue.ItemStatePending, queue.ItemStatePendingDependency, queue.ItemStateFailed,
+			queue.ItemStateWaiting, queue.ItemStateHold,
+		)
+
+		if unfinishedCount == 0 {
+			break
+		}
+
+		// Note: the context is cancelled once the grace period of an interrupt has expired
+		select {
+		case <-time.After(n.runSleep):
+		case <-ctx.Done():
+		}
+	}
+
+	return nil
+}
+
+// handleRemovalWindow stops the run once the removal window has closed and no removals are in progress anymore, the
+// remaining resources are left in their current state with the reason updated.
+func (n *Nuke) handleRemovalWindow() error {
+	windowErr := n.checkRemovalWindow()
+	if windowErr == nil {
+		return nil
+	}
+
+	if n.Queue.Count(queue.ItemStatePending, queue.ItemStateWaiting) > 0 {
+		return nil
+	}
+
+	n.stop(fmt.Sprintf("not removed: %s", windowErr))
+
+	return fmt.Errorf("%w: %s", ErrRemovalWindowClosed, windowErr)
+}
+
+// stop marks the run as stopped and updates the reason of the resources whose removal was not triggered
+func (n *Nuke) stop(reason string) {
+	for _, item := range n.Queue.GetItems() {
+		if n.timedOut[item] {
+			continue
+		}
+
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateNewDependency, queue.ItemStateHold, queue.ItemStatePendingDependency,
+			queue.ItemStateFailed:
+			item.Reason = reason
+		}
+	}
+
+	n.stopped = true
+}
+
+// checkRemovalWindow returns the error of the removal window, if one is registered
+func (n *Nuke) checkRemovalWindow() error {
+	if n.removalWindow == nil {
+		return nil
+	}
+
+	return n.removalWindow(time.Now())
+}
+
+// handleFailure determines if there have been too many failures and exits accordingly, writing to screen the
+// failure state of each resource.
+func (n *Nuke) handleFailure() error {
+	printLog := n.log.WithField("_handler", "println")
+
+	processingCount := n.Queue.Count(queue.ItemStatePending, queue.ItemStatePendingDependency, queue.ItemStateHold,
+		queue.ItemStateWaiting, queue.ItemStateNew, queue.ItemStateNewDependency)
+
+	failedCount := n.Queue.Count(queue.ItemStateFailed)
+
+	if processingCount == 0 && failedCount > 0 {
+		if n.failedCount >= 2 {
+			printLog.Errorf("There are resources in failed state, but none are ready for deletion, anymore.")
+
+			for _, item := range n.Queue.GetItems() {
+				if item.GetState() != queue.ItemStateFailed {
+					continue
+				}
+
+				item.Print()
+				printLog.Error(item.GetReason())
+			}
+
+			return ErrRemovalFailed
+		}
+
+		n.failedCount++
+	} else {
+		n.failedCount = 0
+	}
+
+	return nil
+}
+
+// handleWaiting determines if there have been too many wait retries and exits accordingly.
+func (n *Nuke) handleWaiting() error {
+	if n.Parameters.MaxWaitRetries == 0 {
+		return nil
+	}
+
+	pendingCount := n.Queue.Count(queue.ItemStateWaiting, queue.ItemStatePending,
+		queue.ItemStatePendingDependency, queue.ItemStateHold)
+
+	newCount := n.Queue.Count(queue.ItemStateNew, queue.ItemStateNewDependency)
+
+	if pendingCount > 0 && newCount == 0 {
+		if n.waitingCount >= n.Parameters.MaxWaitRetries {
+			return fmt.Errorf("%w: max wait retries of %d exceeded", ErrRemovalFailed, n.Parameters.MaxWaitRetries)
+		}
+		n.waitingCount++
+	} else {
+		n.waitingCount = 0
+	}
+
+	return nil
+}
+
+// HandleQueue iterates over the queue and triggers the appropriate handlers based on the state of the resource.
+func (n *Nuke) HandleQueue(ctx context.Context) {
+	listCache := make(libnuke.ListCache)
+
+	// Note: once the removal window closes or the run is interrupted, no new removals are triggered, but resources
+	// that are already being removed are still waited on
+	windowErr := n.checkRemovalWindow()
+	if windowErr != nil {
+		n.log.WithError(windowErr).Warn("removal window closed, no new removals are triggered")
+	} else if n.Interrupted() {
+		windowErr = ErrInterrupted
+		n.log.Warn("run interrupted, no new removals are triggered")
+	}
+
+	for _, item := range n.removalOrder() {
+		if n.checkTimedOut(item) {
+			continue
+		}
+
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateHold:
+			if windowErr != nil {
+				continue
+			}
+
+			if n.holdForDependents(item) {
+				item.Print()
+				continue
+			}
+
+			itemCtx, cancel := n.itemContext(ctx, item)
+			n.HandleRemove(itemCtx, item)
+			cancel()
+			item.Print()
+		case queue.ItemStateNewDependency, queue.ItemStatePendingDependency:
+			// Note: the dependency handler removes the item once its dependencies are gone
+			if windowErr != nil {
+				continue
+			}
+
+			n.handleWaitDependency(ctx, item)
+			item.Print()
+		case queue.ItemStateFailed:
+			itemCtx, cancel := n.itemContext(ctx, item)
+			if windowErr == nil {
+				n.HandleRemove(itemCtx, item)
+			}
+
+			n.HandleWait(itemCtx, item, listCache)
+			cancel()
+			item.Print()
+		case queue.ItemStatePending:
+			itemCtx, cancel := n.itemContext(ctx, item)
+			n.HandleWait(itemCtx, item, listCache)
+			cancel()
+			item.State = queue.ItemStateWaiting
+			item.Print()
+		case queue.ItemStateWaiting:
+			itemCtx, cancel := n.itemContext(ctx, item)
+			n.HandleWait(itemCtx, item, listCache)
+			cancel()
+			item.Print()
+		}
+	}
+
+	countWaiting := n.Queue.Count(
+		queue.ItemStateWaiting,
+		queue.ItemStatePending,
+		queue.ItemStatePendingDependency,
+		queue.ItemStateNewDependency,
+		queue.ItemStateHold,
+	)
+	countFailed := n.Queue.Count(queue.ItemStateFailed)
+	countSkipped := n.Queue.Count(queue.ItemStateFiltered)
+	countFinished := n.Queue.Count(queue.ItemStateFinished)
+
+	n.log.WithField("_handler", "println").
+		WithFields(logrus.Fields{
+			"waiting":  countWaiting,
+			"failed":   countFailed,
+			"skipped":  countSkipped,
+			"finished": countFinished,
+		}).
+		Infof("Removal requested: %d waiting, %d failed, %d skipped, %d finished\n\n",
+			countWaiting, countFailed, countSkipped, countFinished)
+}
+
+// HandleWait checks if the resource has been removed using the libnuke implementation. The settings of the region
+// that owns the item are used while it runs, as libnuke applies its settings when re-checking the resource filter.
+func (n *Nuke) HandleWait(ctx context.Context, item *queue.Item, cache libnuke.ListCache) {
+	settings := n.Settings
+	n.Settings = n.SettingsFor(item.Owner)
+	defer func() {
+		n.Settings = settings
+	}()
+
+	n.Nuke.HandleWait(ctx, item, cache)
+}
@@ -0,0 +1,273 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	libconfig "github.com/ekristen/libnuke/pkg/config"
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+	libsettings "github.com/ekristen/libnuke/pkg/settings"
+	"github.com/ekristen/libnuke/pkg/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
+)
+
+// dependentResourceType is the type of test resources that depend on resources of the type TestResource, it is
+// removed after them when WaitOnDependencies is set
+const dependentResourceType = "DependentTestResource"
+
+func init() {
+	registry.Register(&registry.Registration{
+		Name:      dependentResourceType,
+		Scope:     "account",
+		Resource:  &testResource{},
+		DependsOn: []string{"TestResource"},
+	})
+}
+
+type testResource struct {
+	name string
+}
+
+func (r *testResource) Remove(_ context.Context) error {
+	return nil
+}
+
+func (r *testResource) Properties() types.Properties {
+	return types.NewProperties().Set("Name", r.name)
+}
+
+type settingsResource struct {
+	testResource
+	setting *libsettings.Setting
+}
+
+func (r *settingsResource) Settings(setting *libsettings.Setting) {
+	r.setting = setting
+}
+
+func (r *settingsResource) Filter() error {
+	if r.setting.GetBool("Skip") {
+		return errors.New("skipped by setting")
+	}
+
+	return nil
+}
+
+func TestNuke_FilterRegionScoped(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{
+		"TestResource": {
+			filter.NewExactFilter("default"),
+		},
+	}, nil)
+
+	n.RegisterRegionFilters("eu-west-1", filter.Filters{
+		"TestResource": {
+			{Property: "Name", Type: filter.Exact, Value: "regional"},
+		},
+	})
+
+	cases := []struct {
+		name     string
+		owner    string
+		resource string
+		filtered bool
+	}{
+		{name: "default-region-match", owner: "us-east-1", resource: "regional", filtered: false},
+		{name: "scoped-region-match", owner: "eu-west-1", resource: "regional", filtered: true},
+		{name: "scoped-region-no-match", owner: "eu-west-1", resource: "other", filtered: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := &queue.Item{
+				Resource: &testResource{name: tc.resource},
+				State:    queue.ItemStateNew,
+				Type:     "TestResource",
+				Owner:    tc.owner,
+			}
+
+			assert.NoError(t, n.Filter(item))
+			assert.Equal(t, tc.filtered, item.State == queue.ItemStateFiltered)
+		})
+	}
+}
+
+func TestNuke_SettingsFor(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{}, nil)
+
+	regional := &libsettings.Settings{
+		"TestResource": &libsettings.Setting{"DisableDeletionProtection": true},
+	}
+	n.RegisterRegionSettings("eu-west-1", regional)
+
+	assert.Same(t, n.Settings, n.SettingsFor("us-east-1"))
+	assert.Same(t, regional, n.SettingsFor("eu-west-1"))
+	assert.True(t, n.SettingsFor("eu-west-1").Get("TestResource").GetBool("DisableDeletionProtection"))
+}
+
+func TestNuke_FilterProvenance(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{}, nil)
+
+	n.RegisterRegionFilterSources("eu-west-1", config.FilterSources{
+		filter.Global: {
+			{
+				Filter:       filter.Filter{Property: "Name", Type: filter.Exact, Value: "global"},
+				ResourceType: filter.Global,
+				Source:       "account",
+				Index:        0,
+				File:         "config.yaml",
+				Line:         10,
+			},
+		},
+		"TestResource": {
+			{
+				Filter:       filter.Filter{Property: "Name", Type: filter.Exact, Value: "keep"},
+				ResourceType: "TestResource",
+				Source:       "preset:terraform",
+				Index:        0,
+				File:         "config.yaml",
+				Line:         42,
+			},
+		},
+	})
+
+	cases := []struct {
+		name     string
+		resource string
+		reason   string
+	}{
+		{name: "global", resource: "global", reason: "filtered by config: account __global__[0] (config.yaml:10)"},
+		{name: "preset", resource: "keep", reason: "filtered by config: preset:terraform TestResource[0] (config.yaml:42)"},
+		{name: "no-match", resource: "other", reason: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := &queue.Item{
+				Resource: &testResource{name: tc.resource},
+				State:    queue.ItemStateNew,
+				Type:     "TestResource",
+				Owner:    "eu-west-1",
+			}
+
+			assert.NoError(t, n.Filter(item))
+			assert.Equal(t, tc.reason, item.Reason)
+		})
+	}
+}
+
+func TestNuke_FilterProvenancePresets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cfg.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`regions:
+  - us-east-1
+  - eu-west-1
+presets:
+  keep:
+    filters:
+      TestResource:
+        - keep-me
+blocklist:
+  - "1234567890"
+accounts:
+  "012345678901":
+    presets:
+      - keep
+    filters:
+      TestResource:
+        - admin
+`), 0600))
+
+	cfg, err := config.New(libconfig.Options{Path: path})
+	require.NoError(t, err)
+
+	// Note: the filters are resolved the same way as the run does, the account filters first, then the filters of
+	// every region along with their sources
+	filters, err := cfg.Filters("012345678901")
+	require.NoError(t, err)
+
+	n := New(&libnuke.Parameters{}, filters, nil)
+	for _, region := range cfg.Regions {
+		sources, err := cfg.RegionFilterSources("012345678901", region)
+		require.NoError(t, err)
+
+		n.RegisterRegionFilterSources(region, sources)
+	}
+
+	cases := []struct {
+		resource string
+		reason   string
+	}{
+		{resource: "admin", reason: "filtered by config: account TestResource[0] (cfg.yaml:17)"},
+		{resource: "keep-me", reason: "filtered by config: preset:keep TestResource[0] (cfg.yaml:8)"},
+		{resource: "other", reason: ""},
+	}
+
+	for _, region := range cfg.Regions {
+		for _, tc := range cases {
+			t.Run(region+"/"+tc.resource, func(t *testing.T) {
+				item := &queue.Item{
+					Resource: &testNamedResource{testResource{name: tc.resource}},
+					State:    queue.ItemStateNew,
+					Type:     "TestResource",
+					Owner:    region,
+				}
+
+				assert.NoError(t, n.Filter(item))
+				assert.Equal(t, tc.reason, item.Reason)
+			})
+		}
+
+		assert.Len(t, n.regionFilterSources[region].Get("TestResource"), 2)
+	}
+}
+
+func TestNuke_HandleWaitRegionSettings(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{}, &libsettings.Settings{})
+	n.RegisterRegionSettings("eu-west-1", &libsettings.Settings{
+		"TestResource": &libsettings.Setting{"Skip": true},
+	})
+
+	cases := []struct {
+		name  string
+		owner string
+		state queue.ItemState
+	}{
+		{name: "default-settings", owner: "us-east-1", state: queue.ItemStateWaiting},
+		{name: "region-settings", owner: "eu-west-1", state: queue.ItemStateFinished},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			item := &queue.Item{
+				Resource: &settingsResource{testResource: testResource{name: "remove-me"}},
+				State:    queue.ItemStateWaiting,
+				Type:     "TestResource",
+				Owner:    tc.owner,
+			}
+
+			// Note: the cache is filled, so the resource is still listed without a registered lister
+			cache := libnuke.ListCache{
+				tc.owner: {
+					"TestResource": []resource.Resource{
+						&settingsResource{testResource: testResource{name: "remove-me"}},
+					},
+				},
+			}
+
+			n.HandleWait(context.TODO(), item, cache)
+			assert.Equal(t, tc.state, item.GetState())
+			assert.Same(t, n.Nuke.Settings, n.SettingsFor("us-east-1"))
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package nuke
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// PolicyExporter is implemented by resources that carry IAM or resource policies, the definition it returns is
+// written as JSON before any resource is removed.
+type PolicyExporter interface {
+	ExportPolicy(ctx context.Context) (interface{}, error)
+}
+
+// PolicyExportOptions are the options for exporting the policies of resources before they are removed.
+type PolicyExportOptions struct {
+	// ResourceTypes limits the export to the given resource types, if it is empty every resource that implements
+	// PolicyExporter is exported
+	ResourceTypes []string
+
+	// Directory is the local directory the definitions are written to
+	Directory string
+}
+
+// Enabled returns true if the policies of the resource type should be exported, it is safe to call on nil options.
+func (o *PolicyExportOptions) Enabled(resourceType string) bool {
+	if o == nil {
+		return false
+	}
+
+	return len(o.ResourceTypes) == 0 || slices.Contains(o.ResourceTypes, resourceType)
+}
+
+// Write writes the definition as JSON to `<directory>/<owner>/<resource type>/<id>.json` and returns the path
+func (o *PolicyExportOptions) Write(owner, resourceType, id string, definition interface{}) (string, error) {
+	data, err := json.MarshalIndent(definition, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	dir := filepath.Join(o.Directory, owner, resourceType)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", err
+	}
+
+	name := strings.Trim(backupNameInvalid.ReplaceAllString(id, "-"), "-")
+	path := filepath.Join(dir, name+".json")
+
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
+
+// RegisterPolicyExport registers the options for exporting policies before resources are removed
+func (n *Nuke) RegisterPolicyExport(opts *PolicyExportOptions) {
+	n.policyExport = opts
+}
+
+// exportPolicies exports the policies of every resource that is about to be removed. It runs before the first
+// removal, as dependent resources such as inline policies and attachments are removed before the resource itself.
+// Any failure aborts the run, as no resource has been removed at this point.
+func (n *Nuke) exportPolicies(ctx context.Context) error {
+	if n.policyExport == nil {
+		return nil
+	}
+
+	for _, item := range n.Queue.GetItems() {
+		state := item.GetState()
+		if state != queue.ItemStateNew && state != queue.ItemStateNewDependency {
+			continue
+		}
+
+		if !n.policyExport.Enabled(item.Type) {
+			continue
+		}
+
+		exporter, ok := item.Resource.(PolicyExporter)
+		if !ok {
+			continue
+		}
+
+		definition, err := exporter.ExportPolicy(ctx)
+		if err != nil {
+			return fmt.Errorf("unable to export the policy of %s %s: %w", item.Type, itemName(item), err)
+		}
+
+		path, err := n.policyExport.Write(item.Owner, item.Type, itemName(item), definition)
+		if err != nil {
+			return fmt.Errorf("unable to write the policy of %s %s: %w", item.Type, itemName(item), err)
+		}
+
+		n.log.WithField("path", path).Debugf("exported the policy of %s %s", item.Type, itemName(item))
+
+		n.artifacts[item] = append(n.artifacts[item], BackupArtifact{Kind: "policy-export", ID: path})
+	}
+
+	return nil
+}
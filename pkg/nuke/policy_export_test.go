@@ -0,0 +1,129 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+type testPolicyResource struct {
+	testResource
+	policy string
+	err    error
+}
+
+func (r *testPolicyResource) String() string {
+	return r.name
+}
+
+func (r *testPolicyResource) ExportPolicy(_ context.Context) (interface{}, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return map[string]string{"policy": r.policy}, nil
+}
+
+func TestNuke_ExportPolicies(t *testing.T) {
+	dir := t.TempDir()
+
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	n.RegisterPolicyExport(&PolicyExportOptions{Directory: dir})
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testPolicyResource{testResource: testResource{name: "role/admin"}, policy: "allow"},
+				State:    queue.ItemStateNew,
+				Type:     "IAMRole",
+				Owner:    "global",
+			},
+			{
+				Resource: &testPolicyResource{testResource: testResource{name: "keep"}, policy: "deny"},
+				State:    queue.ItemStateFiltered,
+				Type:     "IAMRole",
+				Owner:    "global",
+			},
+		},
+	}
+
+	assert.NoError(t, n.exportPolicies(context.TODO()))
+
+	path := filepath.Join(dir, "global", "IAMRole", "role-admin.json")
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"policy":"allow"}`, string(data))
+
+	_, err = os.Stat(filepath.Join(dir, "global", "IAMRole", "keep.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	report := n.Report(nil)
+	assert.Equal(t, []BackupArtifact{{Kind: "policy-export", ID: path}}, report.Items[0].Artifacts)
+	assert.Empty(t, report.Items[1].Artifacts)
+}
+
+func TestNuke_ExportPoliciesWaitOnDependencies(t *testing.T) {
+	dir := t.TempDir()
+
+	n := New(&libnuke.Parameters{NoDryRun: true, WaitOnDependencies: true}, filter.Filters{}, nil)
+	n.RegisterPolicyExport(&PolicyExportOptions{Directory: dir})
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testPolicyResource{testResource: testResource{name: "dependent"}, policy: "allow"},
+				State:    queue.ItemStateNewDependency,
+				Type:     dependentResourceType,
+				Owner:    "global",
+			},
+		},
+	}
+
+	assert.NoError(t, n.exportPolicies(context.TODO()))
+
+	path := filepath.Join(dir, "global", dependentResourceType, "dependent.json")
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"policy":"allow"}`, string(data))
+
+	assert.Equal(t, []BackupArtifact{{Kind: "policy-export", ID: path}}, n.Report(nil).Items[0].Artifacts)
+}
+
+func TestNuke_ExportPoliciesFailure(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	n.SetRunSleep(time.Millisecond)
+	n.RegisterPolicyExport(&PolicyExportOptions{Directory: t.TempDir(), ResourceTypes: []string{"IAMRole"}})
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testPolicyResource{testResource: testResource{name: "admin"}, err: errors.New("access denied")},
+				State:    queue.ItemStateNew,
+				Type:     "IAMRole",
+				Owner:    "global",
+			},
+		},
+	}
+
+	// Note: run stops before any removal is triggered
+	err := n.run(context.TODO())
+	assert.EqualError(t, err, "unable to export the policy of IAMRole admin: access denied")
+	assert.Equal(t, queue.ItemStateNew, n.Queue.GetItems()[0].GetState())
+}
+
+func TestPolicyExportOptions_Enabled(t *testing.T) {
+	var disabled *PolicyExportOptions
+	assert.False(t, disabled.Enabled("IAMRole"))
+
+	assert.True(t, (&PolicyExportOptions{}).Enabled("IAMRole"))
+	assert.False(t, (&PolicyExportOptions{ResourceTypes: []string{"KMSKey"}}).Enabled("IAMRole"))
+}
@@ -0,0 +1,142 @@
+package nuke
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"go.uber.org/ratelimit"
+
+	"github.com/aws/aws-sdk-go/aws/awserr" //nolint:staticcheck
+)
+
+// minBackoff is the delay that is added to the requests of a region after it was throttled for the first time
+const minBackoff = time.Second
+
+// maxThrottleRetries is the number of times a throttled request is retried before the error is returned
+const maxThrottleRetries = 5
+
+// RateLimiter limits the requests to an API, e.g. the Cloud Control API, per region. Every region has its own limit,
+// so scanning multiple regions is not slowed down by a single limit. When a region is throttled, a backoff delay is
+// added to its requests, which doubles every time it is throttled and halves after every successful request.
+type RateLimiter struct {
+	requestsPerMinute int
+	maxBackoff        time.Duration
+
+	mu      sync.Mutex
+	regions map[string]*regionLimiter
+
+	sleep func(time.Duration)
+}
+
+type regionLimiter struct {
+	limiter ratelimit.Limiter
+	backoff time.Duration
+}
+
+// NewRateLimiter returns a rate limiter with the number of requests per minute for every region, the backoff after
+// throttling is at most maxBackoff
+func NewRateLimiter(requestsPerMinute int, maxBackoff time.Duration) *RateLimiter {
+	return &RateLimiter{
+		requestsPerMinute: requestsPerMinute,
+		maxBackoff:        maxBackoff,
+		regions:           make(map[string]*regionLimiter),
+		sleep:             time.Sleep,
+	}
+}
+
+// Take blocks until a request to the region is allowed and returns how long it was blocked
+func (l *RateLimiter) Take(region string) time.Duration {
+	start := time.Now()
+
+	r, backoff := l.region(region)
+	if backoff > 0 {
+		l.sleep(backoff)
+	}
+
+	r.limiter.Take()
+
+	return time.Since(start)
+}
+
+// Do runs the request for the region once it is allowed, throttled requests are retried with an increasing backoff
+func (l *RateLimiter) Do(region string, request func() error) error {
+	for attempt := 0; ; attempt++ {
+		l.Take(region)
+
+		err := request()
+		if !IsThrottlingError(err) {
+			l.succeeded(region)
+			return err
+		}
+
+		l.throttled(region)
+
+		if attempt >= maxThrottleRetries {
+			return err
+		}
+	}
+}
+
+// Backoff returns the current backoff delay of the region
+func (l *RateLimiter) Backoff(region string) time.Duration {
+	_, backoff := l.region(region)
+	return backoff
+}
+
+// region returns the limiter of the region, along with its current backoff, it is created on first use
+func (l *RateLimiter) region(region string) (*regionLimiter, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r, ok := l.regions[region]
+	if !ok {
+		r = &regionLimiter{
+			limiter: ratelimit.New(l.requestsPerMinute, ratelimit.Per(time.Minute), ratelimit.WithoutSlack),
+		}
+		l.regions[region] = r
+	}
+
+	return r, r.backoff
+}
+
+// throttled doubles the backoff of the region, up to the maximum backoff
+func (l *RateLimiter) throttled(region string) {
+	r, _ := l.region(region)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r.backoff = max(r.backoff*2, minBackoff)
+	if r.backoff > l.maxBackoff {
+		r.backoff = l.maxBackoff
+	}
+}
+
+// succeeded halves the backoff of the region, once it is below the minimum backoff it is removed
+func (l *RateLimiter) succeeded(region string) {
+	r, _ := l.region(region)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	r.backoff /= 2
+	if r.backoff < minBackoff {
+		r.backoff = 0
+	}
+}
+
+// IsThrottlingError returns true if the error is a throttling error of the AWS API
+func IsThrottlingError(err error) bool {
+	var awsErr awserr.Error
+	if !errors.As(err, &awsErr) {
+		return false
+	}
+
+	switch awsErr.Code() {
+	case "ThrottlingException", "Throttling", "TooManyRequestsException", "RequestLimitExceeded":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,68 @@
+package nuke
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/aws/awserr" //nolint:staticcheck
+)
+
+func TestRateLimiter_Do(t *testing.T) {
+	limiter := NewRateLimiter(6000, 5*time.Second)
+
+	var sleeps []time.Duration
+	limiter.sleep = func(d time.Duration) {
+		sleeps = append(sleeps, d)
+	}
+
+	throttled := awserr.New("ThrottlingException", "rate exceeded", nil)
+
+	attempts := 0
+	err := limiter.Do("us-east-1", func() error {
+		attempts++
+		if attempts < 4 {
+			return throttled
+		}
+
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, attempts)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, sleeps)
+
+	// Note: the backoff halves after the successful request and only applies to the throttled region
+	assert.Equal(t, 2*time.Second, limiter.Backoff("us-east-1"))
+	assert.Zero(t, limiter.Backoff("eu-west-1"))
+
+	err = limiter.Do("us-east-1", func() error { return errors.New("failed") })
+	assert.EqualError(t, err, "failed")
+	assert.Equal(t, time.Second, limiter.Backoff("us-east-1"))
+
+	assert.NoError(t, limiter.Do("us-east-1", func() error { return nil }))
+	assert.Zero(t, limiter.Backoff("us-east-1"))
+}
+
+func TestRateLimiter_Do_MaxRetries(t *testing.T) {
+	limiter := NewRateLimiter(6000, 3*time.Second)
+	limiter.sleep = func(time.Duration) {}
+
+	attempts := 0
+	err := limiter.Do("us-east-1", func() error {
+		attempts++
+		return awserr.New("ThrottlingException", "rate exceeded", nil)
+	})
+	assert.True(t, IsThrottlingError(err))
+	assert.Equal(t, maxThrottleRetries+1, attempts)
+	assert.Equal(t, 3*time.Second, limiter.Backoff("us-east-1"))
+}
+
+func TestIsThrottlingError(t *testing.T) {
+	assert.True(t, IsThrottlingError(awserr.New("ThrottlingException", "", nil)))
+	assert.True(t, IsThrottlingError(awserr.New("RequestLimitExceeded", "", nil)))
+	assert.False(t, IsThrottlingError(awserr.New("AccessDeniedException", "", nil)))
+	assert.False(t, IsThrottlingError(errors.New("ThrottlingException")))
+	assert.False(t, IsThrottlingError(nil))
+}
@@ -0,0 +1,123 @@
+package nuke
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// Report is the machine-readable summary of a run, it is written at the end of a run regardless of the outcome so
+// that it is always possible to find out what was, and what was not, removed.
+type Report struct {
+	StartedAt  time.Time      `json:"started_at"`
+	FinishedAt time.Time      `json:"finished_at"`
+	DryRun     bool           `json:"dry_run"`
+	Status     string         `json:"status"`
+	Error      string         `json:"error,omitempty"`
+	Summary    map[string]int `json:"summary"`
+	Items      []ReportItem   `json:"items"`
+}
+
+// ReportItem is a single resource in the report
+type ReportItem struct {
+	Owner      string            `json:"owner"`
+	Type       string            `json:"type"`
+	Name       string            `json:"name,omitempty"`
+	State      string            `json:"state"`
+	Reason     string            `json:"reason,omitempty"`
+	Properties map[string]string `json:"properties,omitempty"`
+	Artifacts  []BackupArtifact  `json:"artifacts,omitempty"`
+}
+
+const (
+	// ReportStatusComplete means the run finished without errors
+	ReportStatusComplete = "complete"
+	// ReportStatusFailed means the run ended with an error
+	ReportStatusFailed = "failed"
+	// ReportStatusStopped means the run was stopped before all resources were removed, e.g. the maintenance window
+	// closed
+	ReportStatusStopped = "stopped"
+	// ReportStatusInterrupted means the run was interrupted, e.g. by a signal, before all resources were removed
+	ReportStatusInterrupted = "interrupted"
+)
+
+// Report builds the report for the run, the error is the error returned by Run, if any.
+func (n *Nuke) Report(runErr error) *Report {
+	report := &Report{
+		StartedAt:  n.startedAt,
+		FinishedAt: time.Now().UTC(),
+		DryRun:     !n.Parameters.NoDryRun,
+		Status:     ReportStatusComplete,
+		Summary:    map[string]int{},
+		Items:      []ReportItem{},
+	}
+
+	if runErr != nil {
+		report.Status = ReportStatusFailed
+		report.Error = runErr.Error()
+
+		if n.stopped {
+			report.Status = ReportStatusStopped
+		}
+
+		if errors.Is(runErr, ErrInterrupted) {
+			report.Status = ReportStatusInterrupted
+		}
+	}
+
+	if n.Queue == nil {
+		return report
+	}
+
+	for _, item := range n.Queue.GetItems() {
+		reportItem := ReportItem{
+			Owner:  item.Owner,
+			Type:   item.Type,
+			Name:   itemName(item),
+			State:  item.GetState().String(),
+			Reason: item.GetReason(),
+		}
+
+		if n.timedOut[item] {
+			reportItem.State = ReportStateTimedOut
+		}
+
+		if getter, ok := item.Resource.(resource.PropertyGetter); ok {
+			reportItem.Properties = getter.Properties()
+		}
+
+		if getter, ok := item.Resource.(BackupArtifactGetter); ok {
+			reportItem.Artifacts = getter.BackupArtifacts()
+		}
+
+		reportItem.Artifacts = append(reportItem.Artifacts, n.artifacts[item]...)
+
+		report.Summary[reportItem.State]++
+		report.Items = append(report.Items, reportItem)
+	}
+
+	return report
+}
+
+// itemName returns the name of the resource of the item, or an empty string if the resource has no name
+func itemName(item *queue.Item) string {
+	if stringer, ok := item.Resource.(resource.LegacyStringer); ok {
+		return stringer.String()
+	}
+
+	return ""
+}
+
+// Write writes the report as JSON to the path
+func (r *Report) Write(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
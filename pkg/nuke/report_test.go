@@ -0,0 +1,114 @@
+package nuke
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+func TestNuke_RemovalWindowClosed(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	n.SetRunSleep(time.Millisecond)
+
+	n.RegisterRemovalWindow(func(_ time.Time) error {
+		return errors.New("outside of the maintenance window")
+	})
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testResource{name: "remove-me"},
+				State:    queue.ItemStateNew,
+				Type:     "TestResource",
+				Owner:    "us-east-1",
+			},
+			{
+				Resource: &testResource{name: "keep-me"},
+				State:    queue.ItemStateFiltered,
+				Reason:   "filtered by config",
+				Type:     "TestResource",
+				Owner:    "us-east-1",
+			},
+		},
+	}
+
+	err := n.run(context.TODO())
+	assert.ErrorIs(t, err, ErrRemovalWindowClosed)
+
+	item := n.Queue.GetItems()[0]
+	assert.Equal(t, queue.ItemStateNew, item.GetState())
+	assert.Equal(t, "not removed: outside of the maintenance window", item.GetReason())
+
+	report := n.Report(err)
+	assert.Equal(t, ReportStatusStopped, report.Status)
+	assert.False(t, report.DryRun)
+	assert.Equal(t, map[string]int{"new": 1, "filtered": 1}, report.Summary)
+	assert.Len(t, report.Items, 2)
+	assert.Equal(t, "remove-me", report.Items[0].Properties["Name"])
+}
+
+func TestNuke_RemovalWindowClosedWaitOnDependencies(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true, WaitOnDependencies: true}, filter.Filters{}, nil)
+	n.SetRunSleep(time.Millisecond)
+
+	n.RegisterRemovalWindow(func(_ time.Time) error {
+		return errors.New("outside of the maintenance window")
+	})
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{
+				Resource: &testResource{name: "new"},
+				State:    queue.ItemStateNewDependency,
+				Type:     dependentResourceType,
+				Owner:    "us-east-1",
+			},
+			{
+				Resource: &testResource{name: "pending"},
+				State:    queue.ItemStatePendingDependency,
+				Reason:   "left: 1",
+				Type:     dependentResourceType,
+				Owner:    "us-east-1",
+			},
+		},
+	}
+
+	// Note: the dependencies of the items are gone, they must still not be removed once the window closed
+	err := n.run(context.TODO())
+	assert.ErrorIs(t, err, ErrRemovalWindowClosed)
+
+	items := n.Queue.GetItems()
+	assert.Equal(t, queue.ItemStateNewDependency, items[0].GetState())
+	assert.Equal(t, "not removed: outside of the maintenance window", items[0].GetReason())
+	assert.Equal(t, queue.ItemStatePendingDependency, items[1].GetState())
+	assert.Equal(t, "not removed: outside of the maintenance window", items[1].GetReason())
+}
+
+func TestReport_Write(t *testing.T) {
+	n := New(&libnuke.Parameters{}, filter.Filters{}, nil)
+
+	report := n.Report(nil)
+	assert.Equal(t, ReportStatusComplete, report.Status)
+	assert.True(t, report.DryRun)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	assert.NoError(t, report.Write(path))
+
+	data, err := os.ReadFile(path)
+	assert.NoError(t, err)
+
+	var decoded Report
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, ReportStatusComplete, decoded.Status)
+	assert.Empty(t, decoded.Items)
+}
@@ -21,6 +21,10 @@ type ListerOpts struct {
 	Config    *aws.Config      // SDK v2
 	AccountID *string
 	Logger    *logrus.Entry
+	Backup    *BackupOptions
+
+	// CloudControlLimiter limits the requests to the Cloud Control API per region, it is shared by all regions
+	CloudControlLimiter *RateLimiter
 }
 
 // MutateOpts is a function that will be called for each resource type to mutate the options for the scanner based on
@@ -0,0 +1,522 @@
+package nuke
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+	"github.com/ekristen/libnuke/pkg/resource"
+)
+
+// ErrReviewAborted is returned when the review is aborted, no resources are removed
+var ErrReviewAborted = errors.New("review aborted")
+
+// ReviewReason is the reason set on resources that were excluded during the review
+const ReviewReason = "excluded during review"
+
+// reviewIdentityProperties are the properties used to build a filter for resources that do not have a name, in order
+// of preference
+var reviewIdentityProperties = []string{"Name", "ID", "Identifier", "ARN", "Arn"}
+
+// Review is a function that is called after the scan with the resources that are about to be removed, it may exclude
+// resources by changing their state to filtered.
+type Review func(items []*queue.Item) error
+
+// RegisterReview registers the review that is run after the scan and before the final prompt
+func (n *Nuke) RegisterReview(review Review) {
+	n.review = review
+}
+
+// Reviewer is a line based interactive review of the resources that are about to be removed. Resources are grouped by
+// region and type, individual resources or whole types can be excluded and the exclusions exported as configuration.
+type Reviewer struct {
+	In        io.Reader
+	Out       io.Writer
+	AccountID string
+
+	items         []*queue.Item
+	excluded      map[*queue.Item]bool
+	excludedTypes map[string]bool
+}
+
+type reviewCommand struct {
+	usage       string
+	description string
+	run         func(r *Reviewer, args []string) (bool, error)
+}
+
+var reviewCommands map[string]*reviewCommand
+
+func init() {
+	reviewCommands = map[string]*reviewCommand{
+		"list": {
+			usage: "list [query]", description: "list the resources matching the query, aliases: ls, search",
+			run: (*Reviewer).cmdList,
+		},
+		"show": {
+			usage: "show <n>", description: "show the properties of a resource",
+			run: (*Reviewer).cmdShow,
+		},
+		"exclude": {
+			usage: "exclude <n|n-m>...", description: "exclude resources from removal",
+			run: (*Reviewer).cmdExclude,
+		},
+		"include": {
+			usage: "include <n|n-m>...", description: "undo the exclusion of resources",
+			run: (*Reviewer).cmdInclude,
+		},
+		"exclude-type": {
+			usage: "exclude-type <type> [region]", description: "exclude a resource type, optionally only in a region",
+			run: (*Reviewer).cmdExcludeType,
+		},
+		"include-type": {
+			usage: "include-type <type> [region]", description: "undo the exclusion of a resource type",
+			run: (*Reviewer).cmdIncludeType,
+		},
+		"export": {
+			usage: "export [file]", description: "print or write the exclusions as configuration",
+			run: (*Reviewer).cmdExport,
+		},
+		"summary": {
+			usage: "summary", description: "show the number of resources to remove and excluded",
+			run: (*Reviewer).cmdSummary,
+		},
+		"done": {
+			usage: "done", description: "finish the review and continue with the remaining resources",
+			run: func(_ *Reviewer, _ []string) (bool, error) { return true, nil },
+		},
+		"abort": {
+			usage: "abort", description: "abort the run, nothing is removed, alias: quit",
+			run: func(_ *Reviewer, _ []string) (bool, error) { return true, ErrReviewAborted },
+		},
+		"help": {
+			usage: "help", description: "show this help",
+			run: (*Reviewer).cmdHelp,
+		},
+	}
+
+	reviewCommands["ls"] = reviewCommands["list"]
+	reviewCommands["search"] = reviewCommands["list"]
+	reviewCommands["quit"] = reviewCommands["abort"]
+}
+
+// Review runs the interactive review until it is finished or aborted. The end of the input aborts the review.
+func (r *Reviewer) Review(items []*queue.Item) error {
+	r.excluded = map[*queue.Item]bool{}
+	r.excludedTypes = map[string]bool{}
+
+	// Note: resources that wait on their dependencies are removed as well, once WaitOnDependencies is set
+	r.items = make([]*queue.Item, 0, len(items))
+	for _, item := range items {
+		switch item.GetState() {
+		case queue.ItemStateNew, queue.ItemStateNewDependency:
+			r.items = append(r.items, item)
+		}
+	}
+
+	sort.SliceStable(r.items, func(i, j int) bool {
+		a, b := r.items[i], r.items[j]
+		if a.Owner != b.Owner {
+			return a.Owner < b.Owner
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return itemName(a) < itemName(b)
+	})
+
+	r.printf("Reviewing %d resources, type help for the list of commands.\n", len(r.items))
+	_, _ = r.cmdList(nil)
+
+	input := bufio.NewScanner(r.In)
+	for {
+		r.printf("review> ")
+
+		if !input.Scan() {
+			r.printf("\n")
+			return ErrReviewAborted
+		}
+
+		fields := strings.Fields(input.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		cmd, ok := reviewCommands[fields[0]]
+		if !ok {
+			r.printf("unknown command %s, type help for the list of commands\n", fields[0])
+			continue
+		}
+
+		finished, err := cmd.run(r, fields[1:])
+		if errors.Is(err, ErrReviewAborted) {
+			return err
+		}
+
+		if err != nil {
+			r.printf("error: %s\n", err)
+			continue
+		}
+
+		if finished {
+			r.apply()
+			return nil
+		}
+	}
+}
+
+// apply marks the excluded resources as filtered
+func (r *Reviewer) apply() {
+	for _, item := range r.items {
+		if r.isExcluded(item) {
+			item.State = queue.ItemStateFiltered
+			item.Reason = ReviewReason
+		}
+	}
+}
+
+func (r *Reviewer) printf(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(r.Out, format, args...)
+}
+
+func (r *Reviewer) isExcluded(item *queue.Item) bool {
+	return r.excluded[item] || r.excludedTypes[item.Type]
+}
+
+// matches returns true if the query is found in the region, type, name or any property of the item
+func (r *Reviewer) matches(item *queue.Item, query string) bool {
+	query = strings.ToLower(query)
+
+	values := []string{item.Owner, item.Type, itemName(item)}
+	if getter, ok := item.Resource.(resource.PropertyGetter); ok {
+		for key, value := range getter.Properties() {
+			values = append(values, key, value)
+		}
+	}
+
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), query) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Reviewer) cmdList(args []string) (bool, error) {
+	query := strings.Join(args, " ")
+
+	owner, resourceType := "", ""
+	for i, item := range r.items {
+		if query != "" && !r.matches(item, query) {
+			continue
+		}
+
+		if item.Owner != owner {
+			owner, resourceType = item.Owner, ""
+			r.printf("%s\n", owner)
+		}
+
+		if item.Type != resourceType {
+			resourceType = item.Type
+			r.printf("  %s\n", resourceType)
+		}
+
+		status := ""
+		if r.isExcluded(item) {
+			status = " (excluded)"
+		}
+
+		r.printf("    [%d] %s%s\n", i+1, reviewDisplayName(item), status)
+	}
+
+	return false, nil
+}
+
+func (r *Reviewer) cmdShow(args []string) (bool, error) {
+	indexes, err := r.parseIndexes(args)
+	if err != nil {
+		return false, err
+	}
+
+	for _, i := range indexes {
+		item := r.items[i]
+		r.printf("[%d] %s %s %s\n", i+1, item.Owner, item.Type, reviewDisplayName(item))
+
+		getter, ok := item.Resource.(resource.PropertyGetter)
+		if !ok {
+			continue
+		}
+
+		properties := getter.Properties()
+		keys := make([]string, 0, len(properties))
+		for key := range properties {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			r.printf("    %s: %s\n", key, properties[key])
+		}
+	}
+
+	return false, nil
+}
+
+func (r *Reviewer) cmdExclude(args []string) (bool, error) {
+	return r.setExcluded(args, true)
+}
+
+func (r *Reviewer) cmdInclude(args []string) (bool, error) {
+	return r.setExcluded(args, false)
+}
+
+func (r *Reviewer) setExcluded(args []string, excluded bool) (bool, error) {
+	indexes, err := r.parseIndexes(args)
+	if err != nil {
+		return false, err
+	}
+
+	for _, i := range indexes {
+		item := r.items[i]
+		if !excluded && r.excludedTypes[item.Type] {
+			return false, fmt.Errorf("the type %s is excluded, use include-type first", item.Type)
+		}
+
+		r.excluded[item] = excluded
+	}
+
+	return r.cmdSummary(nil)
+}
+
+func (r *Reviewer) cmdExcludeType(args []string) (bool, error) {
+	return r.setTypeExcluded(args, true)
+}
+
+func (r *Reviewer) cmdIncludeType(args []string) (bool, error) {
+	return r.setTypeExcluded(args, false)
+}
+
+// setTypeExcluded excludes a type in all regions, or only the resources of the type in a single region
+func (r *Reviewer) setTypeExcluded(args []string, excluded bool) (bool, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return false, fmt.Errorf("expected a resource type and an optional region")
+	}
+
+	resourceType := args[0]
+
+	found := false
+	for _, item := range r.items {
+		if item.Type == resourceType && (len(args) == 1 || item.Owner == args[1]) {
+			found = true
+			if len(args) == 2 || !excluded {
+				r.excluded[item] = excluded
+			}
+		}
+	}
+
+	if !found {
+		return false, fmt.Errorf("no resources of type %s found", strings.Join(args, " in "))
+	}
+
+	if len(args) == 1 {
+		r.excludedTypes[resourceType] = excluded
+	} else if !excluded && r.excludedTypes[resourceType] {
+		return false, fmt.Errorf("the type %s is excluded in all regions, use include-type %s", resourceType,
+			resourceType)
+	}
+
+	return r.cmdSummary(nil)
+}
+
+func (r *Reviewer) cmdSummary(_ []string) (bool, error) {
+	excluded := 0
+	for _, item := range r.items {
+		if r.isExcluded(item) {
+			excluded++
+		}
+	}
+
+	r.printf("%d resources will be removed, %d excluded\n", len(r.items)-excluded, excluded)
+
+	return false, nil
+}
+
+func (r *Reviewer) cmdExport(args []string) (bool, error) {
+	snippet, skipped, err := r.Snippet()
+	if err != nil {
+		return false, err
+	}
+
+	for _, item := range skipped {
+		r.printf("warning: %s %s %s can not be expressed as a filter\n", item.Owner, item.Type,
+			reviewDisplayName(item))
+	}
+
+	if len(args) == 0 {
+		r.printf("%s", snippet)
+		return false, nil
+	}
+
+	if err := os.WriteFile(args[0], snippet, 0600); err != nil {
+		return false, err
+	}
+
+	r.printf("exclusions written to %s\n", args[0])
+
+	return false, nil
+}
+
+func (r *Reviewer) cmdHelp(_ []string) (bool, error) {
+	names := make([]string, 0, len(reviewCommands))
+	for name, cmd := range reviewCommands {
+		// Note: aliases share the command of their target and are mentioned in its description
+		if strings.HasPrefix(cmd.usage, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		r.printf("  %-30s %s\n", reviewCommands[name].usage, reviewCommands[name].description)
+	}
+
+	return false, nil
+}
+
+// parseIndexes parses the 1-based indexes and ranges of indexes, e.g. `1 3-5`, into 0-based indexes
+func (r *Reviewer) parseIndexes(args []string) ([]int, error) {
+	if len(args) == 0 {
+		return nil, fmt.Errorf("expected at least one resource number")
+	}
+
+	var indexes []int
+	for _, arg := range args {
+		for _, part := range strings.Split(arg, ",") {
+			startPart, endPart, isRange := strings.Cut(part, "-")
+			if !isRange {
+				endPart = startPart
+			}
+
+			start, startErr := strconv.Atoi(startPart)
+			end, endErr := strconv.Atoi(endPart)
+			if startErr != nil || endErr != nil || start < 1 || end > len(r.items) || end < start {
+				return nil, fmt.Errorf("invalid resource number %s, expected 1-%d", part, len(r.items))
+			}
+
+			for i := start; i <= end; i++ {
+				indexes = append(indexes, i-1)
+			}
+		}
+	}
+
+	return indexes, nil
+}
+
+// Snippet returns the exclusions as configuration for the account. Types excluded in all regions are added to the
+// resource type excludes, individual resources are added as region scoped filters. Resources that can not be
+// identified by a filter are returned separately.
+func (r *Reviewer) Snippet() ([]byte, []*queue.Item, error) {
+	account := map[string]interface{}{}
+
+	var excludedTypes []string
+	for resourceType, excluded := range r.excludedTypes {
+		if excluded {
+			excludedTypes = append(excludedTypes, resourceType)
+		}
+	}
+
+	if len(excludedTypes) > 0 {
+		slices.Sort(excludedTypes)
+		account["resource-types"] = map[string]interface{}{"excludes": excludedTypes}
+	}
+
+	var skipped []*queue.Item
+	regions := map[string]map[string]interface{}{}
+	for _, item := range r.items {
+		if !r.excluded[item] || r.excludedTypes[item.Type] {
+			continue
+		}
+
+		entry, ok := reviewFilter(item)
+		if !ok {
+			skipped = append(skipped, item)
+			continue
+		}
+
+		if regions[item.Owner] == nil {
+			regions[item.Owner] = map[string]interface{}{"filters": map[string][]interface{}{}}
+		}
+
+		filters := regions[item.Owner]["filters"].(map[string][]interface{})
+		filters[item.Type] = append(filters[item.Type], entry)
+	}
+
+	if len(regions) > 0 {
+		account["regions"] = regions
+	}
+
+	if len(account) == 0 {
+		return []byte("# no exclusions\n"), skipped, nil
+	}
+
+	out := &bytes.Buffer{}
+	encoder := yaml.NewEncoder(out)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(map[string]interface{}{
+		"accounts": map[string]interface{}{r.AccountID: account},
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	return out.Bytes(), skipped, encoder.Close()
+}
+
+// reviewFilter returns a filter that matches the item exactly, the name of the item is preferred, otherwise the
+// first identifying property is used.
+func reviewFilter(item *queue.Item) (interface{}, bool) {
+	if name := itemName(item); name != "" {
+		return name, true
+	}
+
+	getter, ok := item.Resource.(resource.PropertyGetter)
+	if !ok {
+		return nil, false
+	}
+
+	properties := getter.Properties()
+	for _, property := range reviewIdentityProperties {
+		if value := properties.Get(property); value != "" {
+			return map[string]string{"property": property, "value": value}, true
+		}
+	}
+
+	return nil, false
+}
+
+// reviewDisplayName returns the name of the item, or its identifying property if it has no name
+func reviewDisplayName(item *queue.Item) string {
+	entry, ok := reviewFilter(item)
+	if !ok {
+		return "(unnamed)"
+	}
+
+	if name, ok := entry.(string); ok {
+		return name
+	}
+
+	property := entry.(map[string]string)
+
+	return fmt.Sprintf("%s=%s", property["property"], property["value"])
+}
@@ -0,0 +1,168 @@
+package nuke
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+type testNamedResource struct {
+	testResource
+}
+
+func (r *testNamedResource) String() string {
+	return r.name
+}
+
+func testReviewItems() []*queue.Item {
+	return []*queue.Item{
+		{Resource: &testNamedResource{testResource{name: "i-2"}}, State: queue.ItemStateNew, Type: "EC2Instance",
+			Owner: "us-east-1"},
+		{Resource: &testNamedResource{testResource{name: "i-1"}}, State: queue.ItemStateNew, Type: "EC2Instance",
+			Owner: "us-east-1"},
+		{Resource: &testResource{name: "bucket"}, State: queue.ItemStateNew, Type: "S3Bucket",
+			Owner: "global"},
+		{Resource: &testNamedResource{testResource{name: "role"}}, State: queue.ItemStateNew, Type: "IAMRole",
+			Owner: "global"},
+		{Resource: &testNamedResource{testResource{name: "kept"}}, State: queue.ItemStateFiltered, Type: "IAMRole",
+			Owner: "global"},
+	}
+}
+
+func TestReviewer_Review(t *testing.T) {
+	items := testReviewItems()
+
+	out := &bytes.Buffer{}
+	reviewer := &Reviewer{
+		In:        strings.NewReader("list i-1\nshow 3\nexclude 3-4\nexclude-type IAMRole\nexport\ndone\n"),
+		Out:       out,
+		AccountID: "012345678901",
+	}
+
+	assert.NoError(t, reviewer.Review(items))
+
+	// Note: items are sorted by region, type and name, i-1 is number 3 and i-2 is number 4
+	assert.Contains(t, out.String(), "    [3] i-1\n")
+	assert.Contains(t, out.String(), "    Name: i-1\n")
+	assert.Contains(t, out.String(), "1 resources will be removed, 3 excluded")
+	assert.Contains(t, out.String(), `accounts:
+  "012345678901":
+    regions:
+      us-east-1:
+        filters:
+          EC2Instance:
+            - i-1
+            - i-2
+    resource-types:
+      excludes:
+        - IAMRole
+`)
+
+	assert.Equal(t, queue.ItemStateFiltered, items[0].GetState())
+	assert.Equal(t, ReviewReason, items[0].GetReason())
+	assert.Equal(t, queue.ItemStateFiltered, items[1].GetState())
+	assert.Equal(t, queue.ItemStateNew, items[2].GetState())
+	assert.Equal(t, queue.ItemStateFiltered, items[3].GetState())
+	assert.NotEqual(t, ReviewReason, items[4].GetReason())
+}
+
+func TestReviewer_ReviewWaitOnDependencies(t *testing.T) {
+	items := []*queue.Item{
+		{Resource: &testNamedResource{testResource{name: "vpc-1"}}, State: queue.ItemStateNewDependency,
+			Type: "EC2VPC", Owner: "us-east-1"},
+		{Resource: &testNamedResource{testResource{name: "vpc-2"}}, State: queue.ItemStateNewDependency,
+			Type: "EC2VPC", Owner: "us-east-1"},
+		{Resource: &testNamedResource{testResource{name: "subnet-1"}}, State: queue.ItemStateNew,
+			Type: "EC2Subnet", Owner: "us-east-1"},
+	}
+
+	out := &bytes.Buffer{}
+	reviewer := &Reviewer{
+		In:  strings.NewReader("exclude 2\ndone\n"),
+		Out: out,
+	}
+
+	assert.NoError(t, reviewer.Review(items))
+
+	// Note: items are sorted by region, type and name, vpc-1 is number 2
+	assert.Contains(t, out.String(), "Reviewing 3 resources")
+	assert.Contains(t, out.String(), "    [2] vpc-1\n")
+	assert.Contains(t, out.String(), "2 resources will be removed, 1 excluded")
+
+	assert.Equal(t, queue.ItemStateFiltered, items[0].GetState())
+	assert.Equal(t, ReviewReason, items[0].GetReason())
+	assert.Equal(t, queue.ItemStateNewDependency, items[1].GetState())
+	assert.Equal(t, queue.ItemStateNew, items[2].GetState())
+}
+
+func TestReviewer_Snippet(t *testing.T) {
+	items := testReviewItems()
+
+	reviewer := &Reviewer{
+		In:        strings.NewReader("exclude 2\nexclude-type EC2Instance us-east-1\ninclude 4\ndone\n"),
+		Out:       &bytes.Buffer{},
+		AccountID: "012345678901",
+	}
+
+	assert.NoError(t, reviewer.Review(items))
+
+	snippet, skipped, err := reviewer.Snippet()
+	assert.NoError(t, err)
+	assert.Empty(t, skipped)
+	assert.Equal(t, `accounts:
+  "012345678901":
+    regions:
+      global:
+        filters:
+          S3Bucket:
+            - property: Name
+              value: bucket
+      us-east-1:
+        filters:
+          EC2Instance:
+            - i-1
+`, string(snippet))
+
+	assert.Equal(t, queue.ItemStateNew, items[0].GetState())
+}
+
+func TestReviewer_Abort(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+	}{
+		{name: "abort", input: "exclude 1\nabort\n"},
+		{name: "end of input", input: "exclude 1\n"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			items := testReviewItems()
+
+			reviewer := &Reviewer{In: strings.NewReader(tc.input), Out: &bytes.Buffer{}}
+
+			assert.ErrorIs(t, reviewer.Review(items), ErrReviewAborted)
+			for _, item := range items[:4] {
+				assert.Equal(t, queue.ItemStateNew, item.GetState())
+			}
+		})
+	}
+}
+
+func TestReviewer_InvalidInput(t *testing.T) {
+	out := &bytes.Buffer{}
+	reviewer := &Reviewer{
+		In:  strings.NewReader("exclude 9\nexclude a\nfoo\nexclude-type Missing\ndone\n"),
+		Out: out,
+	}
+
+	assert.NoError(t, reviewer.Review(testReviewItems()))
+	assert.Contains(t, out.String(), "error: invalid resource number 9, expected 1-4")
+	assert.Contains(t, out.String(), "error: invalid resource number a, expected 1-4")
+	assert.Contains(t, out.String(), "unknown command foo")
+	assert.Contains(t, out.String(), "error: no resources of type Missing found")
+}
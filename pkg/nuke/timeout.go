@@ -0,0 +1,118 @@
+package nuke
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+// ReportStateTimedOut is the state of resources in the report whose removal timed out
+const ReportStateTimedOut = "timed-out"
+
+// RemoveTimeout returns how long the removal of a resource of the type may take, zero means it is not limited.
+type RemoveTimeout func(resourceType string) time.Duration
+
+// RegisterRemoveTimeout registers the timeout of the removal of resources, it starts with the first removal attempt.
+// The context passed to Remove and HandleWait carries the deadline, resources that time out are failed and not
+// retried.
+func (n *Nuke) RegisterRemoveTimeout(timeout RemoveTimeout) {
+	n.removeTimeout = timeout
+}
+
+// RegisterRunTimeout registers the timeout of the removal phase of the run, once it is exceeded the run is stopped and
+// the resources that were not removed are reported as timed out.
+func (n *Nuke) RegisterRunTimeout(timeout time.Duration) {
+	n.runTimeout = timeout
+}
+
+// TimedOut returns true if the removal of the item timed out
+func (n *Nuke) TimedOut(item *queue.Item) bool {
+	return n.timedOut[item]
+}
+
+// itemContext returns the context for the removal of the item, it carries the deadline of the item if a removal
+// timeout applies to its type. The deadline is set on the first call for the item.
+func (n *Nuke) itemContext(ctx context.Context, item *queue.Item) (context.Context, context.CancelFunc) {
+	if n.removeTimeout == nil {
+		return ctx, func() {}
+	}
+
+	deadline, ok := n.deadlines[item]
+	if !ok {
+		timeout := n.removeTimeout(item.Type)
+		if timeout <= 0 {
+			return ctx, func() {}
+		}
+
+		deadline = time.Now().Add(timeout)
+		n.deadlines[item] = deadline
+	}
+
+	return context.WithDeadline(ctx, deadline)
+}
+
+// handleWaitDependency triggers the removal of the item once its dependencies are gone, the context passed to the
+// removal carries the deadline of the item. The deadline is only kept once the removal was attempted.
+func (n *Nuke) handleWaitDependency(ctx context.Context, item *queue.Item) {
+	_, started := n.deadlines[item]
+
+	itemCtx, cancel := n.itemContext(ctx, item)
+	defer cancel()
+
+	n.HandleWaitDependency(itemCtx, item)
+
+	if !started && item.GetState() == queue.ItemStatePendingDependency {
+		delete(n.deadlines, item)
+	}
+}
+
+// checkTimedOut returns true if the item has timed out, an item that exceeded its deadline is failed with the reason
+// updated and is not retried
+func (n *Nuke) checkTimedOut(item *queue.Item) bool {
+	if n.timedOut[item] {
+		return true
+	}
+
+	switch item.GetState() {
+	case queue.ItemStateFinished, queue.ItemStateFiltered:
+		return false
+	}
+
+	deadline, ok := n.deadlines[item]
+	if !ok || time.Now().Before(deadline) {
+		return false
+	}
+
+	n.markTimedOut(item, fmt.Sprintf("timed out: removal did not finish within %s", n.removeTimeout(item.Type)))
+
+	return true
+}
+
+func (n *Nuke) markTimedOut(item *queue.Item, reason string) {
+	n.timedOut[item] = true
+	item.State = queue.ItemStateFailed
+	item.Reason = reason
+	item.Print()
+}
+
+// handleRunTimeout stops the run once the run timeout is exceeded, resources that are being removed are reported as
+// timed out, the remaining resources are left in their current state with the reason updated.
+func (n *Nuke) handleRunTimeout(ctx context.Context) error {
+	if n.runTimeout <= 0 || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return nil
+	}
+
+	for _, item := range n.Queue.GetItems() {
+		switch item.GetState() {
+		case queue.ItemStatePending, queue.ItemStateWaiting:
+			n.markTimedOut(item, fmt.Sprintf("timed out: run timeout of %s exceeded", n.runTimeout))
+		}
+	}
+
+	n.stop(fmt.Sprintf("not removed: run timeout of %s exceeded", n.runTimeout))
+
+	return fmt.Errorf("%w: run timeout of %s exceeded", ErrRemovalFailed, n.runTimeout)
+}
@@ -0,0 +1,133 @@
+package nuke
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/filter"
+	libnuke "github.com/ekristen/libnuke/pkg/nuke"
+	"github.com/ekristen/libnuke/pkg/queue"
+)
+
+type deadlineResource struct {
+	testResource
+	deadline time.Time
+}
+
+func (r *deadlineResource) Remove(ctx context.Context) error {
+	r.deadline, _ = ctx.Deadline()
+	return nil
+}
+
+func TestNuke_RemoveTimeout(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	n.RegisterRemoveTimeout(func(resourceType string) time.Duration {
+		if resourceType == "SlowResource" {
+			return time.Minute
+		}
+
+		return 0
+	})
+
+	slow := &queue.Item{Resource: &testResource{name: "slow"}, State: queue.ItemStateWaiting, Type: "SlowResource",
+		Owner: "us-east-1"}
+	fast := &queue.Item{Resource: &testResource{name: "fast"}, State: queue.ItemStateWaiting, Type: "FastResource",
+		Owner: "us-east-1"}
+	n.Queue = &queue.Queue{Items: []*queue.Item{slow, fast}}
+
+	ctx, cancel := n.itemContext(context.TODO(), slow)
+	deadline, ok := ctx.Deadline()
+	cancel()
+	assert.True(t, ok)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), deadline, time.Second)
+
+	// Note: the deadline is set on the first removal attempt and is kept across retries
+	ctx, cancel = n.itemContext(context.TODO(), slow)
+	again, _ := ctx.Deadline()
+	cancel()
+	assert.Equal(t, deadline, again)
+
+	ctx, cancel = n.itemContext(context.TODO(), fast)
+	_, ok = ctx.Deadline()
+	cancel()
+	assert.False(t, ok)
+
+	assert.False(t, n.checkTimedOut(slow))
+	assert.False(t, n.checkTimedOut(fast))
+
+	n.deadlines[slow] = time.Now().Add(-time.Second)
+	n.deadlines[fast] = time.Now().Add(-time.Second)
+	fast.State = queue.ItemStateFinished
+	assert.False(t, n.checkTimedOut(fast))
+
+	assert.True(t, n.checkTimedOut(slow))
+	assert.True(t, n.TimedOut(slow))
+	assert.Equal(t, queue.ItemStateFailed, slow.GetState())
+	assert.Equal(t, "timed out: removal did not finish within 1m0s", slow.GetReason())
+
+	report := n.Report(nil)
+	assert.Equal(t, ReportStateTimedOut, report.Items[0].State)
+	assert.Equal(t, map[string]int{ReportStateTimedOut: 1, "finished": 1}, report.Summary)
+}
+
+func TestNuke_RemoveTimeoutWaitOnDependencies(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true, WaitOnDependencies: true}, filter.Filters{}, nil)
+	n.RegisterRemoveTimeout(func(_ string) time.Duration {
+		return time.Minute
+	})
+
+	dependency := &queue.Item{Resource: &testResource{name: "dependency"}, State: queue.ItemStateWaiting,
+		Type: "TestResource", Owner: "us-east-1"}
+	res := &deadlineResource{testResource: testResource{name: "dependent"}}
+	dependent := &queue.Item{Resource: res, State: queue.ItemStateNewDependency, Type: dependentResourceType,
+		Owner: "us-east-1"}
+	n.Queue = &queue.Queue{Items: []*queue.Item{dependency, dependent}}
+
+	// Note: the deadline does not start while the item waits on its dependencies
+	n.handleWaitDependency(context.TODO(), dependent)
+	assert.Equal(t, queue.ItemStatePendingDependency, dependent.GetState())
+	assert.NotContains(t, n.deadlines, dependent)
+	assert.True(t, res.deadline.IsZero())
+
+	dependency.State = queue.ItemStateFinished
+
+	n.handleWaitDependency(context.TODO(), dependent)
+	assert.Equal(t, queue.ItemStatePending, dependent.GetState())
+	assert.Contains(t, n.deadlines, dependent)
+	assert.WithinDuration(t, time.Now().Add(time.Minute), res.deadline, time.Second)
+}
+
+func TestNuke_RunTimeout(t *testing.T) {
+	n := New(&libnuke.Parameters{NoDryRun: true}, filter.Filters{}, nil)
+	n.RegisterRunTimeout(time.Millisecond)
+
+	n.Queue = &queue.Queue{
+		Items: []*queue.Item{
+			{Resource: &testResource{name: "in-progress"}, State: queue.ItemStateWaiting, Type: "TestResource",
+				Owner: "us-east-1"},
+			{Resource: &testResource{name: "not-started"}, State: queue.ItemStateNew, Type: "TestResource",
+				Owner: "us-east-1"},
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), time.Millisecond)
+	defer cancel()
+
+	<-ctx.Done()
+
+	err := n.run(ctx)
+	assert.ErrorIs(t, err, ErrRemovalFailed)
+	assert.EqualError(t, err, "failed: run timeout of 1ms exceeded")
+
+	items := n.Queue.GetItems()
+	assert.True(t, n.TimedOut(items[0]))
+	assert.Equal(t, "timed out: run timeout of 1ms exceeded", items[0].GetReason())
+	assert.False(t, n.TimedOut(items[1]))
+	assert.Equal(t, queue.ItemStateNew, items[1].GetState())
+	assert.Equal(t, "not removed: run timeout of 1ms exceeded", items[1].GetReason())
+
+	assert.Equal(t, ReportStatusStopped, n.Report(err).Status)
+}
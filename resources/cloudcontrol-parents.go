@@ -0,0 +1,95 @@
+package resources
+
+import (
+	"github.com/ekristen/libnuke/pkg/types"
+)
+
+// CloudControlParent is the parent of a Cloud Control resource type that can only be listed with a resource model,
+// e.g. the stages of an API Gateway can only be listed for a single REST API.
+type CloudControlParent struct {
+	// TypeName is the Cloud Control resource type of the parent
+	TypeName string
+
+	// Properties maps the properties of the resource model of the child to the properties of the parent they are read
+	// from, see cloudControlParseProperties for the names of the properties
+	Properties map[string]string
+}
+
+// ResourceModel returns the resource model to list the children of the parent with the properties, it returns false
+// if any property of the model is missing
+func (p *CloudControlParent) ResourceModel(properties types.Properties) (map[string]string, bool) {
+	model := make(map[string]string, len(p.Properties))
+	for childProperty, parentProperty := range p.Properties {
+		value := properties.Get(parentProperty)
+		if value == "" {
+			return nil, false
+		}
+
+		model[childProperty] = value
+	}
+
+	return model, true
+}
+
+// CloudControlParents are the parents of the Cloud Control resource types that require a resource model to be listed.
+// These types are listed by listing the parent type first and then listing the type once for every parent, parents
+// may have a parent of their own.
+//
+// The required properties of the resource model of a type are found in the `handlers.list.handlerSchema` of the
+// schema of the type:
+//
+//	aws cloudformation describe-type --type RESOURCE --type-name AWS::ApiGateway::Stage
+var CloudControlParents = map[string]*CloudControlParent{
+	"AWS::ApiGateway::Authorizer": {
+		TypeName:   "AWS::ApiGateway::RestApi",
+		Properties: map[string]string{"RestApiId": "RestApiId"},
+	},
+	"AWS::ApiGateway::Deployment": {
+		TypeName:   "AWS::ApiGateway::RestApi",
+		Properties: map[string]string{"RestApiId": "RestApiId"},
+	},
+	"AWS::ApiGateway::Stage": {
+		TypeName:   "AWS::ApiGateway::RestApi",
+		Properties: map[string]string{"RestApiId": "RestApiId"},
+	},
+	"AWS::ApiGatewayV2::Integration": {
+		TypeName:   "AWS::ApiGatewayV2::Api",
+		Properties: map[string]string{"ApiId": "ApiId"},
+	},
+	"AWS::ApiGatewayV2::Route": {
+		TypeName:   "AWS::ApiGatewayV2::Api",
+		Properties: map[string]string{"ApiId": "ApiId"},
+	},
+	"AWS::ApiGatewayV2::Stage": {
+		TypeName:   "AWS::ApiGatewayV2::Api",
+		Properties: map[string]string{"ApiId": "ApiId"},
+	},
+	"AWS::Cognito::UserPoolClient": {
+		TypeName:   "AWS::Cognito::UserPool",
+		Properties: map[string]string{"UserPoolId": "UserPoolId"},
+	},
+	"AWS::Cognito::UserPoolGroup": {
+		TypeName:   "AWS::Cognito::UserPool",
+		Properties: map[string]string{"UserPoolId": "UserPoolId"},
+	},
+	"AWS::EC2::SubnetRouteTableAssociation": {
+		TypeName:   "AWS::EC2::Subnet",
+		Properties: map[string]string{"SubnetId": "SubnetId"},
+	},
+	"AWS::ECS::Service": {
+		TypeName:   "AWS::ECS::Cluster",
+		Properties: map[string]string{"Cluster": "Arn"},
+	},
+	"AWS::EKS::Addon": {
+		TypeName:   "AWS::EKS::Cluster",
+		Properties: map[string]string{"ClusterName": "Name"},
+	},
+	"AWS::EKS::Nodegroup": {
+		TypeName:   "AWS::EKS::Cluster",
+		Properties: map[string]string{"ClusterName": "Name"},
+	},
+	"AWS::Timestream::Table": {
+		TypeName:   "AWS::Timestream::Database",
+		Properties: map[string]string{"DatabaseName": "DatabaseName"},
+	},
+}
@@ -2,24 +2,26 @@ package resources
 
 import (
 	"context"
+	_ "embed"
 	"encoding/json"
 	"fmt"
-	"time"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/gotidy/ptr"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"go.uber.org/ratelimit"
 
-	"github.com/aws/aws-sdk-go/aws/awserr"              //nolint:staticcheck
-	"github.com/aws/aws-sdk-go/service/cloudcontrolapi" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/aws/awserr"                                   //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/cloudcontrolapi"                      //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/cloudcontrolapi/cloudcontrolapiiface" //nolint:staticcheck
 
 	liberrors "github.com/ekristen/libnuke/pkg/errors"
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
 
+	"github.com/ekristen/aws-nuke/v3/pkg/config"
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
@@ -55,10 +57,10 @@ func init() {
 	RegisterCloudControl("AWS::NetworkFirewall::RuleGroup")
 }
 
-// describeRateLimit is a rate limiter to avoid throttling when describing resources via the cloud control api.
-// AWS does not publish the rate limits for the cloud control api, the rate seems to be 60 reqs/minute, setting to
-// 55 and setting no slack to avoid throttling.
-var describeRateLimit = ratelimit.New(55, ratelimit.Per(time.Minute), ratelimit.WithoutSlack)
+// defaultCloudControlLimiter limits the requests to the Cloud Control API when no limiter is given in the lister
+// options, e.g. when a lister is used outside a run.
+var defaultCloudControlLimiter = nuke.NewRateLimiter(
+	config.DefaultCloudControlRequestsPerMinute, config.DefaultCloudControlMaxBackoff)
 
 // RegisterCloudControl registers a resource type for the Cloud Control API. This is a unique function that is used
 // in two different places. The first place is in the init() function of this file, where it is used to register
@@ -71,12 +73,53 @@ func RegisterCloudControl(typeName string) {
 		Resource: &CloudControlResource{},
 		Lister: &CloudControlResourceLister{
 			TypeName: typeName,
+			Parent:   CloudControlParents[typeName],
 		},
 	})
 }
 
+// cloudControlCatalog is the list of Cloud Control resource types that can be listed and deleted, it is generated by
+// running this command in the repo root:
+//
+//	go run ./tools/list-cloudcontrol resources/cloudcontrol-catalog.json
+//
+//go:embed cloudcontrol-catalog.json
+var cloudControlCatalog []byte
+
+// RegisterCloudControlCatalog registers every resource type of the Cloud Control catalog that is not registered yet
+// and returns the names of the registered types. Types that are an alternative of a resource implemented by aws-nuke
+// are skipped, they are used instead of the resource when they are added as an alternative.
+func RegisterCloudControlCatalog() ([]string, error) {
+	catalog := struct {
+		Types []string `json:"types"`
+	}{}
+
+	if err := json.Unmarshal(cloudControlCatalog, &catalog); err != nil {
+		return nil, fmt.Errorf("unable to parse the cloud control catalog: %w", err)
+	}
+
+	alternatives := registry.GetAlternativeResourceTypeMapping()
+
+	registered := make([]string, 0, len(catalog.Types))
+	for _, typeName := range catalog.Types {
+		if registry.GetRegistration(typeName) != nil {
+			continue
+		}
+
+		if _, ok := alternatives[typeName]; ok {
+			continue
+		}
+
+		RegisterCloudControl(typeName)
+		registered = append(registered, typeName)
+	}
+
+	return registered, nil
+}
+
 type CloudControlResourceLister struct {
 	TypeName string
+	Parent   *CloudControlParent
 
 	logger *logrus.Entry
 }
@@ -85,17 +128,106 @@ func (l *CloudControlResourceLister) List(_ context.Context, o interface{}) ([]r
 	opts := o.(*nuke.ListerOpts)
 	l.logger = opts.Logger.WithField("type-name", l.TypeName)
 
-	svc := cloudcontrolapi.New(opts.Session)
-	resources := make([]resource.Resource, 0)
+	limiter := opts.CloudControlLimiter
+	if limiter == nil {
+		limiter = defaultCloudControlLimiter
+	}
+
+	client := &cloudControlClient{
+		svc:     cloudcontrolapi.New(opts.Session),
+		limiter: limiter,
+		region:  ptr.ToString(opts.Session.Config.Region),
+	}
+
+	found, err := l.listAll(client)
+	if err != nil {
+		// If a Type is not available in a region we shouldn't throw an error for it.
+		var awsError awserr.Error
+		if errors.As(err, &awsError) {
+			if awsError.Code() == "TypeNotFoundException" {
+				return nil, liberrors.ErrSkipRequest(
+					"cloudformation type not available in region: " + *opts.Session.Config.Region)
+			}
+		}
+
+		return nil, err
+	}
+
+	resources := make([]resource.Resource, 0, len(found))
+	for _, r := range found {
+		resources = append(resources, r)
+	}
+
+	return resources, nil
+}
+
+// listAll lists all resources of the type, types that have a parent are listed once for every resource of the parent
+// type, with the identifiers of the parent as resource model
+func (l *CloudControlResourceLister) listAll(client *cloudControlClient) ([]*CloudControlResource, error) {
+	if l.Parent == nil {
+		return l.list(client, nil)
+	}
+
+	parentLister := &CloudControlResourceLister{
+		TypeName: l.Parent.TypeName,
+		Parent:   CloudControlParents[l.Parent.TypeName],
+		logger:   l.logger.WithField("parent-type-name", l.Parent.TypeName),
+	}
+
+	parents, err := parentLister.listAll(client)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := make([]*CloudControlResource, 0)
+	for _, parent := range parents {
+		model, ok := l.Parent.ResourceModel(parent.properties)
+		if !ok {
+			l.logger.
+				WithField("parent", parent.identifier).
+				Debug("parent is missing the properties of the resource model, skipping")
+			continue
+		}
+
+		children, err := l.list(client, model)
+		if err != nil {
+			return nil, err
+		}
+
+		resources = append(resources, children...)
+	}
+
+	return resources, nil
+}
+
+// list lists the resources of the type, the resource model is required by types that can only be listed for a parent,
+// its properties are added to the properties of the resources if they are missing
+func (l *CloudControlResourceLister) list(
+	client *cloudControlClient, model map[string]string) ([]*CloudControlResource, error) {
+	resources := make([]*CloudControlResource, 0)
 
 	params := &cloudcontrolapi.ListResourcesInput{
 		TypeName:   ptr.String(l.TypeName),
 		MaxResults: ptr.Int64(100),
 	}
 
-	if err := svc.ListResourcesPages(params, func(page *cloudcontrolapi.ListResourcesOutput, lastPage bool) bool {
-		dt := describeRateLimit.Take()
-		l.logger.Debugf("rate limit time: %s", dt)
+	if model != nil {
+		payload, err := json.Marshal(model)
+		if err != nil {
+			return nil, err
+		}
+
+		params.ResourceModel = ptr.String(string(payload))
+	}
+
+	for {
+		var page *cloudcontrolapi.ListResourcesOutput
+		if err := client.do(func() (err error) {
+			page, err = client.svc.ListResources(params)
+			return err
+		}); err != nil {
+			return nil, err
+		}
 
 		for _, desc := range page.ResourceDescriptions {
 			identifier := ptr.ToString(desc.Identifier)
@@ -108,8 +240,13 @@ func (l *CloudControlResourceLister) List(_ context.Context, o interface{}) ([]r
 				continue
 			}
 			properties = properties.Set("Identifier", identifier)
+			for name, value := range model {
+				if properties.Get(name) == "" {
+					properties.Set(name, value)
+				}
+			}
 			resources = append(resources, &CloudControlResource{
-				svc:         svc,
+				client:      client,
 				clientToken: uuid.New().String(),
 				typeName:    l.TypeName,
 				identifier:  identifier,
@@ -117,103 +254,216 @@ func (l *CloudControlResourceLister) List(_ context.Context, o interface{}) ([]r
 			})
 		}
 
-		return true
-	}); err != nil {
-		// If a Type is not available in a region we shouldn't throw an error for it.
-		var awsError awserr.Error
-		if errors.As(err, &awsError) {
-			if awsError.Code() == "TypeNotFoundException" {
-				return nil, liberrors.ErrSkipRequest(
-					"cloudformation type not available in region: " + *opts.Session.Config.Region)
-			}
+		if page.NextToken == nil {
+			break
 		}
 
-		return nil, err
+		params.NextToken = page.NextToken
 	}
 
 	return resources, nil
 }
 
-func (l *CloudControlResourceLister) cloudControlParseProperties(payload string) (types.Properties, error) {
-	// Warning: The implementation of this function is not very straightforward,
-	// because the aws-nuke filter functions expect a very rigid structure and
-	// the properties from the Cloud Control API are very dynamic.
+// cloudControlClient is the Cloud Control API of a region, every request is limited by the rate limiter of the region
+type cloudControlClient struct {
+	svc     cloudcontrolapiiface.CloudControlApiAPI
+	limiter *nuke.RateLimiter
+	region  string
+}
+
+// do runs the request once the rate limit of the region allows it, throttled requests are retried
+func (c *cloudControlClient) do(request func() error) error {
+	if c.limiter == nil {
+		return request()
+	}
+
+	return c.limiter.Do(c.region, request)
+}
 
+// cloudControlParseProperties flattens the JSON properties of a Cloud Control resource into properties that can be
+// used in filters:
+//
+//   - scalars are set with their path, numbers and booleans as their JSON literal, e.g. `Port: "443"`
+//   - nested objects are joined with a dot, e.g. `VpcConfig.SecurityGroupIds`
+//   - arrays are indexed, e.g. `Rules.[0].Priority`, arrays of strings additionally set the value as key for
+//     backwards compatibility, e.g. `CidrBlockAssociations.["vpc-cidr-assoc-1234"]: "true"`
+//   - arrays of key value pairs, e.g. tags, are set by their key regardless of the casing of the fields,
+//     e.g. `Tags.["Name"]: "Kubernetes VPC"`
+//   - null values, empty objects and empty arrays are skipped
+func (l *CloudControlResourceLister) cloudControlParseProperties(payload string) (types.Properties, error) {
 	properties := types.NewProperties()
 	propMap := map[string]interface{}{}
 
-	err := json.Unmarshal([]byte(payload), &propMap)
-	if err != nil {
+	// Numbers are decoded as json.Number, so they keep the literal they had in the payload
+	decoder := json.NewDecoder(strings.NewReader(payload))
+	decoder.UseNumber()
+
+	if err := decoder.Decode(&propMap); err != nil {
 		return properties, err
 	}
 
 	for name, value := range propMap {
-		switch v := value.(type) {
-		case string:
-			properties = properties.Set(name, v)
-		case []interface{}:
-			for _, value2 := range v {
-				switch v2 := value2.(type) {
-				case string:
-					properties.Set(
-						fmt.Sprintf("%s.[%q]", name, v2),
-						true,
-					)
-				case map[string]interface{}:
-					if len(v2) == 2 && v2["Key"] != nil && v2["Value"] != nil {
-						properties.Set(
-							fmt.Sprintf("%s.[%q]", name, v2["Key"]),
-							v2["Value"],
-						)
-					} else {
-						l.logger.
-							WithField("value", fmt.Sprintf("%q", v)).
-							Debugf("nested cloud control property type []%T is not supported", value)
-					}
-				default:
-					l.logger.
-						WithField("value", fmt.Sprintf("%q", v)).
-						Debugf("nested cloud control property type []%T is not supported", value)
-				}
+		cloudControlFlattenProperty(properties, name, value)
+	}
+
+	return properties, nil
+}
+
+// cloudControlFlattenProperty sets the value under the path, nested values are set recursively, see
+// cloudControlParseProperties for the scheme
+func cloudControlFlattenProperty(properties types.Properties, path string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		properties.Set(path, v)
+	case json.Number:
+		properties.Set(path, v.String())
+	case bool:
+		properties.Set(path, v)
+	case map[string]interface{}:
+		for name, nested := range v {
+			cloudControlFlattenProperty(properties, path+"."+name, nested)
+		}
+	case []interface{}:
+		for i, element := range v {
+			if key, keyValue, ok := cloudControlKeyValue(element); ok {
+				cloudControlFlattenProperty(properties, fmt.Sprintf("%s.[%q]", path, key), keyValue)
+				continue
 			}
 
-		default:
-			// We cannot rely on the default handling of
-			// properties.Set, because it would fall back to
-			// fmt.Sprintf. Since the cloud control properties are
-			// nested it would create properties that are not
-			// suitable for filtering. Therefore, we have to
-			// implemented more sophisticated parsing.
-			l.logger.
-				WithField("value", fmt.Sprintf("%q", v)).
-				Debugf("cloud control property type %T is not supported", v)
+			if str, ok := element.(string); ok {
+				properties.Set(fmt.Sprintf("%s.[%q]", path, str), true)
+			}
+
+			cloudControlFlattenProperty(properties, fmt.Sprintf("%s.[%d]", path, i), element)
 		}
 	}
+}
 
-	return properties, nil
+// cloudControlKeyValue returns the key and the value of an object that is a key value pair, e.g. a tag. The fields
+// are matched regardless of their casing, e.g. `Key` and `key`, and may be prefixed, e.g. `TagKey` and `TagValue`.
+func cloudControlKeyValue(element interface{}) (key string, value interface{}, ok bool) {
+	object, isObject := element.(map[string]interface{})
+	if !isObject || len(object) != 2 {
+		return "", nil, false
+	}
+
+	var hasKey, hasValue bool
+	for name, field := range object {
+		switch strings.ToLower(name) {
+		case "key", "tagkey":
+			key, hasKey = field.(string)
+		case "value", "tagvalue":
+			value, hasValue = field, field != nil
+		}
+	}
+
+	return key, value, hasKey && hasValue
 }
 
 type CloudControlResource struct {
-	svc         *cloudcontrolapi.CloudControlApi
-	clientToken string
-	typeName    string
-	identifier  string
-	properties  types.Properties
+	client       *cloudControlClient
+	clientToken  string
+	requestToken *string
+	typeName     string
+	identifier   string
+	properties   types.Properties
 }
 
 func (r *CloudControlResource) String() string {
 	return r.identifier
 }
 
-func (r *CloudControlResource) Remove(_ context.Context) error {
-	_, err := r.svc.DeleteResource(&cloudcontrolapi.DeleteResourceInput{
-		ClientToken: &r.clientToken,
-		Identifier:  &r.identifier,
-		TypeName:    &r.typeName,
+func (r *CloudControlResource) Remove(ctx context.Context) error {
+	var res *cloudcontrolapi.DeleteResourceOutput
+	err := r.client.do(func() (err error) {
+		res, err = r.client.svc.DeleteResourceWithContext(ctx, &cloudcontrolapi.DeleteResourceInput{
+			ClientToken: &r.clientToken,
+			Identifier:  &r.identifier,
+			TypeName:    &r.typeName,
+		})
+		return err
 	})
+	if err != nil {
+		return err
+	}
+
+	if res.ProgressEvent == nil {
+		return nil
+	}
+
+	r.requestToken = res.ProgressEvent.RequestToken
+
+	// The delete request is asynchronous, only a request that already failed is a failed removal, requests that are
+	// still running are checked by HandleWait
+	err = r.checkProgressEvent(res.ProgressEvent)
+
+	var waitErr liberrors.ErrWaitResource
+	if errors.As(err, &waitErr) {
+		return nil
+	}
+
 	return err
 }
 
+// HandleWait polls the status of the delete request until it either succeeded or failed, a failed request is reported
+// as a failed removal until the removal is retried with a new request.
+func (r *CloudControlResource) HandleWait(ctx context.Context) error {
+	if r.requestToken == nil {
+		return nil
+	}
+
+	var res *cloudcontrolapi.GetResourceRequestStatusOutput
+	err := r.client.do(func() (err error) {
+		res, err = r.client.svc.GetResourceRequestStatusWithContext(ctx, &cloudcontrolapi.GetResourceRequestStatusInput{
+			RequestToken: r.requestToken,
+		})
+		return err
+	})
+	if err != nil {
+		var awsError awserr.Error
+		if errors.As(err, &awsError) && awsError.Code() == cloudcontrolapi.ErrCodeRequestTokenNotFoundException {
+			r.requestToken = nil
+			return nil
+		}
+
+		return err
+	}
+
+	return r.checkProgressEvent(res.ProgressEvent)
+}
+
+// checkProgressEvent maps the status of a delete request to the result of the removal, requests that are still
+// running are waited on, failed requests are returned as an error along with the error code of the handler
+func (r *CloudControlResource) checkProgressEvent(event *cloudcontrolapi.ProgressEvent) error {
+	if event == nil {
+		return nil
+	}
+
+	switch ptr.ToString(event.OperationStatus) {
+	case cloudcontrolapi.OperationStatusPending, cloudcontrolapi.OperationStatusInProgress,
+		cloudcontrolapi.OperationStatusCancelInProgress:
+		return liberrors.ErrWaitResource(fmt.Sprintf("waiting for delete request %s to complete",
+			ptr.ToString(event.RequestToken)))
+	case cloudcontrolapi.OperationStatusFailed:
+		// A new client token is required, otherwise retrying the removal would return the same failed request
+		r.clientToken = uuid.New().String()
+
+		// The resource was already removed, e.g. by the removal of its parent
+		if ptr.ToString(event.ErrorCode) == cloudcontrolapi.HandlerErrorCodeNotFound {
+			return nil
+		}
+
+		return fmt.Errorf("delete request failed with %s: %s",
+			ptr.ToString(event.ErrorCode), ptr.ToString(event.StatusMessage))
+	case cloudcontrolapi.OperationStatusCancelComplete:
+		r.clientToken = uuid.New().String()
+
+		return fmt.Errorf("delete request was cancelled: %s", ptr.ToString(event.StatusMessage))
+	}
+
+	return nil
+}
+
 func (r *CloudControlResource) Properties() types.Properties {
 	return r.properties
 }
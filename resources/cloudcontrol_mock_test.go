@@ -0,0 +1,372 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/gotidy/ptr"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"              //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/cloudcontrolapi" //nolint:staticcheck
+
+	liberrors "github.com/ekristen/libnuke/pkg/errors"
+
+	"github.com/ekristen/aws-nuke/v3/mocks/mock_cloudcontrolapiiface"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+func Test_Mock_CloudControlResource_Remove(t *testing.T) {
+	cases := []struct {
+		name    string
+		status  string
+		code    string
+		wantErr string
+	}{
+		{
+			name:   "in progress",
+			status: cloudcontrolapi.OperationStatusInProgress,
+		},
+		{
+			name:   "success",
+			status: cloudcontrolapi.OperationStatusSuccess,
+		},
+		{
+			name:    "failed",
+			status:  cloudcontrolapi.OperationStatusFailed,
+			code:    cloudcontrolapi.HandlerErrorCodeResourceConflict,
+			wantErr: "delete request failed with ResourceConflict: environment has dependencies",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSvc := mock_cloudcontrolapiiface.NewMockCloudControlApiAPI(ctrl)
+
+			r := CloudControlResource{
+				client:      &cloudControlClient{svc: mockSvc},
+				clientToken: "client-token",
+				typeName:    "AWS::MWAA::Environment",
+				identifier:  "env",
+			}
+
+			mockSvc.EXPECT().DeleteResourceWithContext(gomock.Eq(context.TODO()), gomock.Eq(&cloudcontrolapi.DeleteResourceInput{
+				ClientToken: ptr.String("client-token"),
+				Identifier:  ptr.String("env"),
+				TypeName:    ptr.String("AWS::MWAA::Environment"),
+			})).Return(&cloudcontrolapi.DeleteResourceOutput{
+				ProgressEvent: &cloudcontrolapi.ProgressEvent{
+					OperationStatus: ptr.String(tc.status),
+					ErrorCode:       ptr.String(tc.code),
+					RequestToken:    ptr.String("request-token"),
+					StatusMessage:   ptr.String("environment has dependencies"),
+				},
+			}, nil)
+
+			err := r.Remove(context.TODO())
+			if tc.wantErr != "" {
+				a.EqualError(err, tc.wantErr)
+			} else {
+				a.NoError(err)
+			}
+
+			a.Equal("request-token", ptr.ToString(r.requestToken))
+		})
+	}
+}
+
+func Test_Mock_CloudControlResource_HandleWait(t *testing.T) {
+	cases := []struct {
+		name    string
+		status  string
+		code    string
+		err     error
+		wait    bool
+		wantErr string
+	}{
+		{
+			name:   "pending",
+			status: cloudcontrolapi.OperationStatusPending,
+			wait:   true,
+		},
+		{
+			name:   "in progress",
+			status: cloudcontrolapi.OperationStatusInProgress,
+			wait:   true,
+		},
+		{
+			name:   "cancel in progress",
+			status: cloudcontrolapi.OperationStatusCancelInProgress,
+			wait:   true,
+		},
+		{
+			name:   "success",
+			status: cloudcontrolapi.OperationStatusSuccess,
+		},
+		{
+			name:    "failed",
+			status:  cloudcontrolapi.OperationStatusFailed,
+			code:    cloudcontrolapi.HandlerErrorCodeResourceConflict,
+			wantErr: "delete request failed with ResourceConflict: delete failed",
+		},
+		{
+			name:   "failed not found",
+			status: cloudcontrolapi.OperationStatusFailed,
+			code:   cloudcontrolapi.HandlerErrorCodeNotFound,
+		},
+		{
+			name:    "cancel complete",
+			status:  cloudcontrolapi.OperationStatusCancelComplete,
+			wantErr: "delete request was cancelled: delete failed",
+		},
+		{
+			name: "request token not found",
+			err:  awserr.New(cloudcontrolapi.ErrCodeRequestTokenNotFoundException, "", nil),
+		},
+		{
+			name:    "api error",
+			err:     awserr.New(cloudcontrolapi.ErrCodeThrottlingException, "rate exceeded", nil),
+			wantErr: "ThrottlingException: rate exceeded",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockSvc := mock_cloudcontrolapiiface.NewMockCloudControlApiAPI(ctrl)
+
+			r := CloudControlResource{
+				client:       &cloudControlClient{svc: mockSvc},
+				clientToken:  "client-token",
+				requestToken: ptr.String("request-token"),
+				typeName:     "AWS::MWAA::Environment",
+				identifier:   "env",
+			}
+
+			output := &cloudcontrolapi.GetResourceRequestStatusOutput{
+				ProgressEvent: &cloudcontrolapi.ProgressEvent{
+					OperationStatus: ptr.String(tc.status),
+					ErrorCode:       ptr.String(tc.code),
+					RequestToken:    ptr.String("request-token"),
+					StatusMessage:   ptr.String("delete failed"),
+				},
+			}
+			if tc.err != nil {
+				output = nil
+			}
+
+			mockSvc.EXPECT().GetResourceRequestStatusWithContext(gomock.Eq(context.TODO()), gomock.Eq(&cloudcontrolapi.GetResourceRequestStatusInput{
+				RequestToken: ptr.String("request-token"),
+			})).Return(output, tc.err)
+
+			err := r.HandleWait(context.TODO())
+
+			var waitErr liberrors.ErrWaitResource
+			switch {
+			case tc.wait:
+				a.ErrorAs(err, &waitErr)
+			case tc.wantErr != "":
+				a.EqualError(err, tc.wantErr)
+			default:
+				a.NoError(err)
+			}
+		})
+	}
+}
+
+func Test_Mock_CloudControlResource_HandleWait_NoRequest(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	r := CloudControlResource{
+		client: &cloudControlClient{svc: mock_cloudcontrolapiiface.NewMockCloudControlApiAPI(ctrl)},
+	}
+
+	a.NoError(r.HandleWait(context.TODO()))
+}
+
+func Test_Mock_CloudControlResource_Retry(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_cloudcontrolapiiface.NewMockCloudControlApiAPI(ctrl)
+
+	r := CloudControlResource{
+		client:       &cloudControlClient{svc: mockSvc},
+		clientToken:  "client-token",
+		requestToken: ptr.String("request-token"),
+		typeName:     "AWS::MWAA::Environment",
+		identifier:   "env",
+	}
+
+	mockSvc.EXPECT().GetResourceRequestStatusWithContext(gomock.Any(), gomock.Any()).Return(&cloudcontrolapi.GetResourceRequestStatusOutput{
+		ProgressEvent: &cloudcontrolapi.ProgressEvent{
+			OperationStatus: ptr.String(cloudcontrolapi.OperationStatusFailed),
+			ErrorCode:       ptr.String(cloudcontrolapi.HandlerErrorCodeResourceConflict),
+		},
+	}, nil)
+
+	a.Error(r.HandleWait(context.TODO()))
+
+	// Note: the failed request must not be reused when the removal is retried
+	a.NotEqual("client-token", r.clientToken)
+}
+
+func Test_Mock_CloudControlResourceLister_Parent(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_cloudcontrolapiiface.NewMockCloudControlApiAPI(ctrl)
+
+	mockSvc.EXPECT().ListResources(gomock.Eq(&cloudcontrolapi.ListResourcesInput{
+		TypeName:   ptr.String("AWS::ApiGateway::RestApi"),
+		MaxResults: ptr.Int64(100),
+	})).Return(&cloudcontrolapi.ListResourcesOutput{
+		ResourceDescriptions: []*cloudcontrolapi.ResourceDescription{
+			{Identifier: ptr.String("api-1"), Properties: ptr.String(`{"RestApiId":"api-1"}`)},
+			{Identifier: ptr.String("api-2"), Properties: ptr.String(`{"RestApiId":"api-2"}`)},
+			{Identifier: ptr.String("api-3"), Properties: ptr.String(`{}`)},
+		},
+	}, nil)
+
+	mockSvc.EXPECT().ListResources(gomock.Eq(&cloudcontrolapi.ListResourcesInput{
+		TypeName:      ptr.String("AWS::ApiGateway::Stage"),
+		MaxResults:    ptr.Int64(100),
+		ResourceModel: ptr.String(`{"RestApiId":"api-1"}`),
+	})).Return(&cloudcontrolapi.ListResourcesOutput{
+		ResourceDescriptions: []*cloudcontrolapi.ResourceDescription{
+			{Identifier: ptr.String("api-1|prod"), Properties: ptr.String(`{"StageName":"prod"}`)},
+		},
+	}, nil)
+
+	mockSvc.EXPECT().ListResources(gomock.Eq(&cloudcontrolapi.ListResourcesInput{
+		TypeName:      ptr.String("AWS::ApiGateway::Stage"),
+		MaxResults:    ptr.Int64(100),
+		ResourceModel: ptr.String(`{"RestApiId":"api-2"}`),
+	})).Return(&cloudcontrolapi.ListResourcesOutput{
+		ResourceDescriptions: []*cloudcontrolapi.ResourceDescription{
+			{Identifier: ptr.String("api-2|dev"), Properties: ptr.String(`{"StageName":"dev","RestApiId":"api-2"}`)},
+		},
+	}, nil)
+
+	lister := &CloudControlResourceLister{
+		TypeName: "AWS::ApiGateway::Stage",
+		Parent:   CloudControlParents["AWS::ApiGateway::Stage"],
+		logger:   logrus.WithField("test", true),
+	}
+
+	resources, err := lister.listAll(&cloudControlClient{
+		svc:     mockSvc,
+		limiter: nuke.NewRateLimiter(6000, time.Second),
+		region:  "us-east-1",
+	})
+	a.NoError(err)
+	a.Len(resources, 2)
+
+	a.Equal("api-1|prod", resources[0].String())
+	a.Equal("prod", resources[0].Properties().Get("StageName"))
+	a.Equal("api-1", resources[0].Properties().Get("RestApiId"))
+	a.Equal("api-2|dev", resources[1].String())
+	a.Equal("api-2", resources[1].Properties().Get("RestApiId"))
+}
+
+func Test_Mock_CloudControlResourceLister_Pagination(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_cloudcontrolapiiface.NewMockCloudControlApiAPI(ctrl)
+
+	gomock.InOrder(
+		mockSvc.EXPECT().ListResources(gomock.Eq(&cloudcontrolapi.ListResourcesInput{
+			TypeName:   ptr.String("AWS::MWAA::Environment"),
+			MaxResults: ptr.Int64(100),
+		})).Return(&cloudcontrolapi.ListResourcesOutput{
+			ResourceDescriptions: []*cloudcontrolapi.ResourceDescription{
+				{Identifier: ptr.String("env-1"), Properties: ptr.String(`{"Name":"env-1"}`)},
+			},
+			NextToken: ptr.String("next"),
+		}, nil),
+		mockSvc.EXPECT().ListResources(gomock.Eq(&cloudcontrolapi.ListResourcesInput{
+			TypeName:   ptr.String("AWS::MWAA::Environment"),
+			MaxResults: ptr.Int64(100),
+			NextToken:  ptr.String("next"),
+		})).Return(nil, awserr.New(cloudcontrolapi.ErrCodeThrottlingException, "rate exceeded", nil)),
+		mockSvc.EXPECT().ListResources(gomock.Eq(&cloudcontrolapi.ListResourcesInput{
+			TypeName:   ptr.String("AWS::MWAA::Environment"),
+			MaxResults: ptr.Int64(100),
+			NextToken:  ptr.String("next"),
+		})).Return(&cloudcontrolapi.ListResourcesOutput{
+			ResourceDescriptions: []*cloudcontrolapi.ResourceDescription{
+				{Identifier: ptr.String("env-2"), Properties: ptr.String(`{"Name":"env-2"}`)},
+			},
+		}, nil),
+	)
+
+	lister := &CloudControlResourceLister{
+		TypeName: "AWS::MWAA::Environment",
+		logger:   logrus.WithField("test", true),
+	}
+
+	resources, err := lister.listAll(&cloudControlClient{
+		svc:     mockSvc,
+		limiter: nuke.NewRateLimiter(6000, 10*time.Millisecond),
+		region:  "us-east-1",
+	})
+	a.NoError(err)
+	a.Len(resources, 2)
+	a.Equal("env-1", resources[0].String())
+	a.Equal("env-2", resources[1].String())
+}
+
+func Test_Mock_CloudControlResource_Properties(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_cloudcontrolapiiface.NewMockCloudControlApiAPI(ctrl)
+
+	mockSvc.EXPECT().ListResources(gomock.Eq(&cloudcontrolapi.ListResourcesInput{
+		TypeName:   ptr.String("AWS::MWAA::Environment"),
+		MaxResults: ptr.Int64(100),
+	})).Return(&cloudcontrolapi.ListResourcesOutput{
+		ResourceDescriptions: []*cloudcontrolapi.ResourceDescription{
+			{
+				Identifier: ptr.String("env-1"),
+				Properties: ptr.String(`{"Name":"env-1","Tags":[{"Key":"Owner","Value":"team"}]}`),
+			},
+		},
+	}, nil)
+
+	lister := &CloudControlResourceLister{
+		TypeName: "AWS::MWAA::Environment",
+		logger:   logrus.WithField("test", true),
+	}
+
+	resources, err := lister.listAll(&cloudControlClient{
+		svc:     mockSvc,
+		limiter: nuke.NewRateLimiter(6000, 10*time.Millisecond),
+		region:  "us-east-1",
+	})
+	a.NoError(err)
+	a.Len(resources, 1)
+
+	// Note: the properties are used by the filters, a resource without properties cannot be filtered
+	properties := resources[0].Properties()
+	a.Equal("env-1", properties.Get("Identifier"))
+	a.Equal("env-1", properties.Get("Name"))
+	a.Equal("team", properties.Get(`Tags.["Owner"]`))
+}
@@ -5,6 +5,9 @@ import (
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/types"
 )
 
 func TestCloudControlParseProperties(t *testing.T) {
@@ -13,18 +16,68 @@ func TestCloudControlParseProperties(t *testing.T) {
 	cases := []struct {
 		name    string
 		payload string
-		want    []string
+		want    map[string]string
 	}{
 		{
 			name:    "AWS::EC2::VPC",
 			payload: `{"VpcId":"vpc-456","InstanceTenancy":"default","CidrBlockAssociations":["vpc-cidr-assoc-1234", "vpc-cidr-assoc-5678"],"CidrBlock":"10.10.0.0/16","Tags":[{"Value":"Kubernetes VPC","Key":"Name"}]}`, //nolint:lll
-			want: []string{
-				`CidrBlock: "10.10.0.0/16"`,
-				`Tags.["Name"]: "Kubernetes VPC"`,
-				`VpcId: "vpc-456"`,
-				`InstanceTenancy: "default"`,
-				`CidrBlockAssociations.["vpc-cidr-assoc-1234"]: "true"`,
-				`CidrBlockAssociations.["vpc-cidr-assoc-5678"]: "true"`,
+			want: map[string]string{
+				`CidrBlock`:       "10.10.0.0/16",
+				`Tags.["Name"]`:   "Kubernetes VPC",
+				`VpcId`:           "vpc-456",
+				`InstanceTenancy`: "default",
+				`CidrBlockAssociations.["vpc-cidr-assoc-1234"]`: "true",
+				`CidrBlockAssociations.["vpc-cidr-assoc-5678"]`: "true",
+				`CidrBlockAssociations.[0]`:                     "vpc-cidr-assoc-1234",
+				`CidrBlockAssociations.[1]`:                     "vpc-cidr-assoc-5678",
+			},
+		},
+		{
+			name:    "AWS::Lambda::Function",
+			payload: `{"FunctionName":"fn","MemorySize":128,"Timeout":2.5,"VpcConfig":{"SubnetIds":["subnet-1","subnet-2"],"Ipv6AllowedForDualStack":false},"Tags":[{"key":"Owner","value":"team"}]}`, //nolint:lll
+			want: map[string]string{
+				`FunctionName`:                      "fn",
+				`MemorySize`:                        "128",
+				`Timeout`:                           "2.5",
+				`VpcConfig.SubnetIds.["subnet-1"]`:  "true",
+				`VpcConfig.SubnetIds.["subnet-2"]`:  "true",
+				`VpcConfig.SubnetIds.[0]`:           "subnet-1",
+				`VpcConfig.SubnetIds.[1]`:           "subnet-2",
+				`VpcConfig.Ipv6AllowedForDualStack`: "false",
+				`Tags.["Owner"]`:                    "team",
+			},
+		},
+		{
+			name:    "AWS::NetworkFirewall::RuleGroup",
+			payload: `{"RuleGroupName":"rules","Rules":[{"Priority":1,"Action":"pass","Ports":[80,443]},{"Priority":2,"Action":"drop","Ports":[]}],"Tags":[{"TagKey":"Env","TagValue":"dev"}]}`, //nolint:lll
+			want: map[string]string{
+				`RuleGroupName`:       "rules",
+				`Rules.[0].Priority`:  "1",
+				`Rules.[0].Action`:    "pass",
+				`Rules.[0].Ports.[0]`: "80",
+				`Rules.[0].Ports.[1]`: "443",
+				`Rules.[1].Priority`:  "2",
+				`Rules.[1].Action`:    "drop",
+				`Tags.["Env"]`:        "dev",
+			},
+		},
+		{
+			name:    "skipped values",
+			payload: `{"Name":"name","Description":null,"Config":{},"Aliases":[],"Tags":[{"Key":"Empty","Value":null}]}`,
+			want: map[string]string{
+				`Name`:         "name",
+				`Tags.[0].Key`: "Empty",
+			},
+		},
+		{
+			name:    "nested arrays",
+			payload: `{"Matrix":[["a","b"],[true]]}`,
+			want: map[string]string{
+				`Matrix.[0].["a"]`: "true",
+				`Matrix.[0].["b"]`: "true",
+				`Matrix.[0].[0]`:   "a",
+				`Matrix.[0].[1]`:   "b",
+				`Matrix.[1].[0]`:   "true",
 			},
 		},
 	}
@@ -37,9 +90,58 @@ func TestCloudControlParseProperties(t *testing.T) {
 
 			result, err := lister.cloudControlParseProperties(tc.payload)
 			assert.NoError(t, err)
-			for _, w := range tc.want {
-				assert.Contains(t, result.String(), w)
+
+			for key, value := range tc.want {
+				assert.Equal(t, value, result.Get(key), key)
 			}
+
+			// Note: the properties also contain the tag prefix, which is not part of the payload
+			assert.Len(t, result, len(tc.want)+1)
 		})
 	}
 }
+
+func TestCloudControlParsePropertiesInvalid(t *testing.T) {
+	lister := CloudControlResourceLister{}
+
+	_, err := lister.cloudControlParseProperties(`{"Name":`)
+	assert.Error(t, err)
+}
+
+func TestRegisterCloudControlCatalog(t *testing.T) {
+	registered, err := RegisterCloudControlCatalog()
+	assert.NoError(t, err)
+
+	assert.Contains(t, registered, "AWS::Lambda::Function")
+	assert.NotNil(t, registry.GetRegistration("AWS::Lambda::Function"))
+
+	// Note: already registered types and alternatives of resources implemented by aws-nuke are skipped
+	assert.NotContains(t, registered, "AWS::MWAA::Environment")
+	assert.NotContains(t, registered, "AWS::EC2::VPC")
+	assert.Nil(t, registry.GetRegistration("AWS::EC2::VPC"))
+
+	registered, err = RegisterCloudControlCatalog()
+	assert.NoError(t, err)
+	assert.Empty(t, registered)
+}
+
+func TestCloudControlParentResourceModel(t *testing.T) {
+	parent := &CloudControlParent{
+		TypeName:   "AWS::ECS::Cluster",
+		Properties: map[string]string{"Cluster": "Arn"},
+	}
+
+	model, ok := parent.ResourceModel(types.NewProperties().Set("Arn", "arn:aws:ecs:us-east-1:000000000000:cluster/c"))
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{"Cluster": "arn:aws:ecs:us-east-1:000000000000:cluster/c"}, model)
+
+	_, ok = parent.ResourceModel(types.NewProperties().Set("ClusterName", "c"))
+	assert.False(t, ok)
+}
+
+func TestCloudControlParents(t *testing.T) {
+	for typeName, parent := range CloudControlParents {
+		assert.NotEqual(t, typeName, parent.TypeName, typeName)
+		assert.NotEmpty(t, parent.Properties, typeName)
+	}
+}
@@ -52,7 +52,8 @@ type CodeBuildReport struct {
 }
 
 func (r *CodeBuildReport) Name() string {
-	return strings.Split(*r.arn, "report/")[1]
+	_, name, _ := strings.Cut(*r.arn, "report/")
+	return name
 }
 
 func (r *CodeBuildReport) Remove(_ context.Context) error {
@@ -57,7 +57,8 @@ type CodebuildReportGroup struct {
 }
 
 func (r *CodebuildReportGroup) Name() string {
-	return strings.Split(*r.arn, "report-group/")[1]
+	_, name, _ := strings.Cut(*r.arn, "report-group/")
+	return name
 }
 
 func (r *CodebuildReportGroup) Remove(_ context.Context) error {
@@ -0,0 +1,524 @@
+package resources
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/docs"
+)
+
+// conformanceRegistrations are the registrations of the resources, they are copied before any test runs, so the
+// resources that are registered by tests, e.g. the Cloud Control catalog, are not checked
+var conformanceRegistrations = make(registry.Registrations)
+
+func TestMain(m *testing.M) {
+	for name, reg := range registry.GetRegistrations() {
+		conformanceRegistrations[name] = reg
+	}
+
+	os.Exit(m.Run())
+}
+
+// conformanceSource is the index of the source files of the resources
+type conformanceSource struct {
+	// files maps the name of every type to the file it is declared in
+	files map[string]string
+	// settings are the names of the settings that are read in every file
+	settings map[string]map[string]bool
+}
+
+func loadConformanceSource(t *testing.T) *conformanceSource {
+	t.Helper()
+
+	filenames, err := filepath.Glob("*.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fset := token.NewFileSet()
+	files := make(map[string]*ast.File)
+	constants := make(map[string]string)
+
+	for _, filename := range filenames {
+		if strings.HasSuffix(filename, "_test.go") {
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, filename, nil, parser.SkipObjectResolution)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[filename] = file
+
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.CONST {
+				continue
+			}
+
+			for _, spec := range gen.Specs {
+				value := spec.(*ast.ValueSpec)
+				for i, name := range value.Names {
+					if i < len(value.Values) {
+						if lit, ok := value.Values[i].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+							constants[name.Name], _ = strconv.Unquote(lit.Value)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	source := &conformanceSource{
+		files:    make(map[string]string),
+		settings: make(map[string]map[string]bool),
+	}
+
+	for filename, file := range files {
+		source.settings[filename] = make(map[string]bool)
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.TypeSpec:
+				source.files[n.Name.Name] = filename
+			case *ast.CallExpr:
+				// Note: settings are read with e.g. r.settings.GetBool("DisableDeletionProtection")
+				sel, ok := n.Fun.(*ast.SelectorExpr)
+				if !ok || !strings.HasPrefix(sel.Sel.Name, "Get") || len(n.Args) != 1 || !isSettingsExpr(sel.X) {
+					return true
+				}
+
+				switch arg := n.Args[0].(type) {
+				case *ast.BasicLit:
+					name, _ := strconv.Unquote(arg.Value)
+					source.settings[filename][name] = true
+				case *ast.Ident:
+					source.settings[filename][constants[arg.Name]] = true
+				}
+			}
+
+			return true
+		})
+	}
+
+	return source
+}
+
+func isSettingsExpr(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return strings.EqualFold(e.Name, "settings")
+	case *ast.SelectorExpr:
+		return strings.EqualFold(e.Sel.Name, "settings")
+	}
+
+	return false
+}
+
+// file returns the file the resource of the registration is declared in
+func (s *conformanceSource) file(reg *registry.Registration) string {
+	for _, value := range []interface{}{reg.Resource, reg.Lister} {
+		if value == nil {
+			continue
+		}
+
+		typ := reflect.TypeOf(value)
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+
+		if filename, ok := s.files[typ.Name()]; ok {
+			return filepath.Join("resources", filename)
+		}
+	}
+
+	return "resources/<unknown file>"
+}
+
+// sortedRegistrations returns the registrations sorted by their name, so the failures are reported in a stable order
+func sortedRegistrations() []*registry.Registration {
+	regs := make([]*registry.Registration, 0, len(conformanceRegistrations))
+	for _, reg := range conformanceRegistrations {
+		regs = append(regs, reg)
+	}
+
+	sort.Slice(regs, func(i, j int) bool {
+		return regs[i].Name < regs[j].Name
+	})
+
+	return regs
+}
+
+func TestConformance_DependsOn(t *testing.T) {
+	source := loadConformanceSource(t)
+
+	for _, reg := range sortedRegistrations() {
+		for _, dependency := range reg.DependsOn {
+			if _, ok := conformanceRegistrations[dependency]; !ok {
+				t.Errorf("%s: %s depends on %s, which is not registered", source.file(reg), reg.Name, dependency)
+			}
+		}
+	}
+}
+
+func TestConformance_DeprecatedAliases(t *testing.T) {
+	source := loadConformanceSource(t)
+
+	aliases := make(map[string]string)
+	for _, reg := range sortedRegistrations() {
+		for _, alias := range reg.DeprecatedAliases {
+			if _, ok := conformanceRegistrations[alias]; ok {
+				t.Errorf("%s: the deprecated alias %s of %s is the name of a resource", source.file(reg), alias, reg.Name)
+			}
+
+			if other, ok := aliases[alias]; ok {
+				t.Errorf("%s: the deprecated alias %s of %s is also an alias of %s",
+					source.file(reg), alias, reg.Name, other)
+			}
+
+			aliases[alias] = reg.Name
+		}
+	}
+}
+
+func TestConformance_Settings(t *testing.T) {
+	source := loadConformanceSource(t)
+
+	for _, reg := range sortedRegistrations() {
+		if len(reg.Settings) == 0 {
+			continue
+		}
+
+		file := source.file(reg)
+
+		if _, ok := reg.Resource.(resource.SettingsGetter); !ok {
+			t.Errorf("%s: %s has settings, but the resource does not implement Settings()", file, reg.Name)
+			continue
+		}
+
+		for _, setting := range reg.Settings {
+			if !source.settings[filepath.Base(file)][setting] {
+				t.Errorf("%s: the setting %s of %s is never read", file, setting, reg.Name)
+			}
+		}
+	}
+}
+
+// conformanceEmptyProperties are the resources that have no properties, e.g. because they are a singleton per region
+var conformanceEmptyProperties = map[string]bool{
+	BedrockModelInvocationLoggingConfigurationResource: true,
+}
+
+func TestConformance_Properties(t *testing.T) {
+	source := loadConformanceSource(t)
+
+	for _, reg := range sortedRegistrations() {
+		if reg.Resource == nil {
+			continue
+		}
+
+		typ := reflect.TypeOf(reg.Resource)
+		if typ.Kind() != reflect.Ptr || typ.Elem().Kind() != reflect.Struct {
+			continue
+		}
+
+		value := reflect.New(typ.Elem())
+		fillConformanceValue(value.Elem(), nil)
+
+		getter, ok := value.Interface().(resource.PropertyGetter)
+		if !ok {
+			continue
+		}
+
+		properties, err := conformanceProperties(getter)
+
+		empty := true
+		for key := range properties {
+			// Note: internal properties, e.g. the tag prefix, start with an underscore
+			if !strings.HasPrefix(key, "_") {
+				empty = false
+			}
+		}
+
+		// Note: the resources that keep what was listed in unexported fields can only be built by their listers, their
+		// properties are covered by the mock tests of the listers instead
+		if (err != nil || empty) && hasUnexportedData(typ.Elem()) {
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: Properties() of %s panics: %v", source.file(reg), reg.Name, err)
+			continue
+		}
+
+		if empty && !conformanceEmptyProperties[reg.Name] {
+			t.Errorf("%s: Properties() of %s is empty", source.file(reg), reg.Name)
+		}
+	}
+}
+
+func conformanceProperties(getter resource.PropertyGetter) (properties map[string]string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return getter.Properties(), nil
+}
+
+// hasUnexportedData returns true if the struct has unexported fields that are not clients of the AWS SDKs
+func hasUnexportedData(typ reflect.Type) bool {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.IsExported() || field.Type.Kind() == reflect.Interface {
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Ptr && isConformanceClient(field.Type.Elem()) {
+			continue
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// isConformanceClient returns true if the type is the client of a service, i.e. the Client of SDK v2 or the service of
+// SDK v1 which embeds its client
+func isConformanceClient(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct || !strings.HasPrefix(typ.PkgPath(), "github.com/aws/") {
+		return false
+	}
+
+	if typ.Name() == "Client" {
+		return true
+	}
+
+	_, ok := typ.FieldByName("Client")
+	return ok
+}
+
+var conformanceTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// fillConformanceValue sets every exported field below the value to a value that is not zero, the types that are
+// being filled are tracked to stop at recursive types
+func fillConformanceValue(v reflect.Value, filling []reflect.Type) {
+	for _, typ := range filling {
+		if typ == v.Type() {
+			return
+		}
+	}
+	filling = append(filling, v.Type())
+
+	if !v.CanSet() {
+		return
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString("test")
+	case reflect.Bool:
+		v.SetBool(true)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(1)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(1)
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(1)
+	case reflect.Ptr:
+		p := reflect.New(v.Type().Elem())
+		fillConformanceValue(p.Elem(), filling)
+		v.Set(p)
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), 1, 1)
+		fillConformanceValue(s.Index(0), filling)
+		v.Set(s)
+	case reflect.Map:
+		key := reflect.New(v.Type().Key()).Elem()
+		fillConformanceValue(key, filling)
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillConformanceValue(elem, filling)
+
+		m := reflect.MakeMapWithSize(v.Type(), 1)
+		m.SetMapIndex(key, elem)
+		v.Set(m)
+	case reflect.Struct:
+		if v.Type() == reflect.TypeOf(conformanceTime) {
+			v.Set(reflect.ValueOf(conformanceTime))
+			return
+		}
+
+		// Note: the types of the standard library, e.g. sync.Mutex, are left as they are
+		if !strings.Contains(strings.Split(v.Type().PkgPath(), "/")[0], ".") && v.Type().PkgPath() != "" &&
+			!strings.HasPrefix(v.Type().PkgPath(), "github.com") {
+			return
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			fillConformanceValue(v.Field(i), filling)
+		}
+	default:
+	}
+}
+
+func TestConformance_PropertyTags(t *testing.T) {
+	source := loadConformanceSource(t)
+
+	for _, reg := range sortedRegistrations() {
+		if reg.Resource == nil {
+			continue
+		}
+
+		typ := reflect.TypeOf(reg.Resource)
+		if typ.Kind() == reflect.Ptr {
+			typ = typ.Elem()
+		}
+		if typ.Kind() != reflect.Struct {
+			continue
+		}
+
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+
+			if err := checkConformanceTag(field); err != nil {
+				t.Errorf("%s: the tag of the field %s of %s is invalid: %v", source.file(reg), field.Name, reg.Name, err)
+			}
+		}
+	}
+}
+
+// propertyTagOptions are the options of the property tag that are supported by types.NewPropertiesFromStruct
+var propertyTagOptions = map[string]bool{
+	"name":       true,
+	"prefix":     true,
+	"tagPrefix":  true,
+	"keyField":   true,
+	"valueField": true,
+}
+
+func checkConformanceTag(field reflect.StructField) error {
+	tags, err := parseConformanceTag(string(field.Tag))
+	if err != nil {
+		return err
+	}
+
+	if property, ok := tags["property"]; ok {
+		if !field.IsExported() {
+			return errors.New("the property tag is ignored on unexported fields")
+		}
+
+		options := strings.Split(property, ",")
+		for i, option := range options {
+			switch {
+			case option == "-" && len(options) == 1, option == "inline" && i == 1, option == "" && i == 0:
+				continue
+			}
+
+			key, value, ok := strings.Cut(option, "=")
+			if !ok || !propertyTagOptions[key] || value == "" {
+				return fmt.Errorf("unknown option %q of the property tag", option)
+			}
+		}
+	}
+
+	if unique, ok := tags["libnuke"]; ok && unique != "uniqueKey" {
+		return fmt.Errorf("unknown value %q of the libnuke tag", unique)
+	}
+
+	return nil
+}
+
+// parseConformanceTag parses the struct tag the same way as reflect.StructTag, but returns an error if it is malformed
+// instead of ignoring the rest of the tag
+func parseConformanceTag(tag string) (map[string]string, error) {
+	tags := make(map[string]string)
+
+	for {
+		tag = strings.TrimLeft(tag, " ")
+		if tag == "" {
+			return tags, nil
+		}
+
+		i := 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return nil, fmt.Errorf("bad syntax for struct tag %q", tag)
+		}
+
+		name := tag[:i]
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("bad syntax for struct tag value of %s", name)
+		}
+
+		value, err := strconv.Unquote(tag[:i+1])
+		if err != nil {
+			return nil, fmt.Errorf("bad syntax for struct tag value of %s", name)
+		}
+
+		if _, ok := tags[name]; ok {
+			return nil, fmt.Errorf("duplicate struct tag %s", name)
+		}
+
+		tags[name] = value
+		tag = tag[i+1:]
+	}
+}
+
+func TestConformance_Docs(t *testing.T) {
+	source := loadConformanceSource(t)
+
+	for _, reg := range sortedRegistrations() {
+		if reg.Resource == nil {
+			continue
+		}
+
+		generated, err := docs.RenderResource(reg)
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		filename := filepath.Join("docs", "resources", docs.ResourceFilename(reg.Name)+".md")
+
+		got, err := os.ReadFile(filepath.Join("..", filename))
+		if err != nil {
+			t.Errorf("%s: %s of %s is missing, run `go run ./tools/generate-docs --write`",
+				source.file(reg), filename, reg.Name)
+			continue
+		}
+
+		// Note: only the generated sections are compared, the sections that are written by hand are kept
+		if want := docs.MergeResource(generated, got); string(got) != string(want) {
+			t.Errorf("%s: %s of %s is out of date, run `go run ./tools/generate-docs --write`",
+				source.file(reg), filename, reg.Name)
+		}
+	}
+}
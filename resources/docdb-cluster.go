@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/docdb"
@@ -67,6 +68,7 @@ func (l *DocDBClusterLister) List(ctx context.Context, o interface{}) ([]resourc
 			}
 			resources = append(resources, &DocDBCluster{
 				svc:                svc,
+				backup:             opts.Backup,
 				ID:                 page.DBClusters[i].DBClusterIdentifier,
 				DeletionProtection: page.DBClusters[i].DeletionProtection,
 				Tags:               tagList,
@@ -77,8 +79,10 @@ func (l *DocDBClusterLister) List(ctx context.Context, o interface{}) ([]resourc
 }
 
 type DocDBCluster struct {
-	svc      *docdb.Client
-	settings *libsettings.Setting
+	svc           *docdb.Client
+	settings      *libsettings.Setting
+	backup        *nuke.BackupOptions
+	finalSnapshot *string
 
 	ID                 *string
 	DeletionProtection *bool
@@ -100,11 +104,33 @@ func (r *DocDBCluster) Remove(ctx context.Context) error {
 		}
 	}
 
-	_, err := r.svc.DeleteDBCluster(ctx, &docdb.DeleteDBClusterInput{
+	params := &docdb.DeleteDBClusterInput{
 		DBClusterIdentifier: r.ID,
 		SkipFinalSnapshot:   aws.Bool(true),
-	})
-	return err
+	}
+
+	var finalSnapshot *string
+	if r.backup.Enabled(DocDBClusterResource) {
+		finalSnapshot = aws.String(nuke.BackupName(*r.ID, time.Now()))
+		params.SkipFinalSnapshot = aws.Bool(false)
+		params.FinalDBSnapshotIdentifier = finalSnapshot
+	}
+
+	if _, err := r.svc.DeleteDBCluster(ctx, params); err != nil {
+		return err
+	}
+
+	r.finalSnapshot = finalSnapshot
+
+	return nil
+}
+
+func (r *DocDBCluster) BackupArtifacts() []nuke.BackupArtifact {
+	if r.finalSnapshot == nil {
+		return nil
+	}
+
+	return []nuke.BackupArtifact{{Kind: "docdb-cluster-snapshot", ID: *r.finalSnapshot}}
 }
 
 func (r *DocDBCluster) Properties() types.Properties {
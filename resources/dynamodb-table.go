@@ -2,6 +2,8 @@ package resources
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
@@ -73,6 +75,7 @@ func (l *DynamoDBTableLister) List(_ context.Context, o interface{}) ([]resource
 
 		resources = append(resources, &DynamoDBTable{
 			svc:        svc,
+			backup:     opts.Backup,
 			id:         tableName,
 			protection: table.Table.DeletionProtectionEnabled,
 			Name:       tableName,
@@ -86,7 +89,9 @@ func (l *DynamoDBTableLister) List(_ context.Context, o interface{}) ([]resource
 type DynamoDBTable struct {
 	svc        dynamodbiface.DynamoDBAPI
 	settings   *settings.Setting
-	id         *string `property:"Identifier"` // TODO(v4): remove this
+	backup     *nuke.BackupOptions
+	backupArn  *string
+	id         *string // TODO(v4): remove this
 	protection *bool
 	Name       *string
 	Tags       []*dynamodb.Tag
@@ -97,6 +102,12 @@ func (r *DynamoDBTable) Remove(_ context.Context) error {
 		return err
 	}
 
+	if r.backup.Enabled(DynamoDBTableResource) && r.backupArn == nil {
+		if err := r.createBackup(); err != nil {
+			return fmt.Errorf("unable to create backup before removal: %w", err)
+		}
+	}
+
 	params := &dynamodb.DeleteTableInput{
 		TableName: r.Name,
 	}
@@ -109,6 +120,29 @@ func (r *DynamoDBTable) Remove(_ context.Context) error {
 	return nil
 }
 
+// createBackup creates an on-demand backup of the table, the table can be deleted while the backup is being created
+func (r *DynamoDBTable) createBackup() error {
+	resp, err := r.svc.CreateBackup(&dynamodb.CreateBackupInput{
+		TableName:  r.Name,
+		BackupName: ptr.String(nuke.BackupName(ptr.ToString(r.Name), time.Now())),
+	})
+	if err != nil {
+		return err
+	}
+
+	r.backupArn = resp.BackupDetails.BackupArn
+
+	return nil
+}
+
+func (r *DynamoDBTable) BackupArtifacts() []nuke.BackupArtifact {
+	if r.backupArn == nil {
+		return nil
+	}
+
+	return []nuke.BackupArtifact{{Kind: "dynamodb-backup", ID: *r.backupArn}}
+}
+
 func (r *DynamoDBTable) DisableDeletionProtection() error {
 	if !r.settings.GetBool("DisableDeletionProtection") {
 		return nil
@@ -14,6 +14,7 @@ import (
 	libsettings "github.com/ekristen/libnuke/pkg/settings"
 
 	"github.com/ekristen/aws-nuke/v3/mocks/mock_dynamodbiface"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
 func Test_Mock_DynamoDBTable_List(t *testing.T) {
@@ -163,3 +164,49 @@ func Test_Mock_DynamoDBTable_Remove_DeletionProtection_Disable(t *testing.T) {
 	err := resource.Remove(context.TODO())
 	a.Error(err)
 }
+
+func Test_Mock_DynamoDBTable_Remove_Backup(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_dynamodbiface.NewMockDynamoDBAPI(ctrl)
+
+	mockSvc.EXPECT().CreateBackup(gomock.Any()).DoAndReturn(
+		func(input *dynamodb.CreateBackupInput) (*dynamodb.CreateBackupOutput, error) {
+			a.Equal("ExampleTable", ptr.ToString(input.TableName))
+			a.Contains(ptr.ToString(input.BackupName), "aws-nuke-backup-ExampleTable-")
+
+			return &dynamodb.CreateBackupOutput{
+				BackupDetails: &dynamodb.BackupDetails{
+					BackupArn: ptr.String("arn:aws:dynamodb:us-east-2:012345678901:table/ExampleTable/backup/01"),
+				},
+			}, nil
+		})
+
+	mockSvc.EXPECT().DeleteTable(&dynamodb.DeleteTableInput{
+		TableName: ptr.String("ExampleTable"),
+	}).Return(nil, awserr.New("ResourceInUseException", "table is being backed up", nil))
+
+	mockSvc.EXPECT().DeleteTable(&dynamodb.DeleteTableInput{
+		TableName: ptr.String("ExampleTable"),
+	}).Return(&dynamodb.DeleteTableOutput{}, nil)
+
+	resource := &DynamoDBTable{
+		svc:        mockSvc,
+		settings:   &libsettings.Setting{},
+		backup:     &nuke.BackupOptions{ResourceTypes: []string{DynamoDBTableResource}},
+		id:         ptr.String("ExampleTable"),
+		protection: ptr.Bool(false),
+		Name:       ptr.String("ExampleTable"),
+	}
+
+	// Note: the backup is only created once, even if the removal is retried
+	a.Error(resource.Remove(context.TODO()))
+	a.NoError(resource.Remove(context.TODO()))
+
+	a.Equal([]nuke.BackupArtifact{{
+		Kind: "dynamodb-backup",
+		ID:   "arn:aws:dynamodb:us-east-2:012345678901:table/ExampleTable/backup/01",
+	}}, resource.BackupArtifacts())
+}
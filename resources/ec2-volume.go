@@ -2,8 +2,10 @@ package resources
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
 	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
@@ -45,6 +47,7 @@ func (l *EC2VolumeLister) List(ctx context.Context, o interface{}) ([]resource.R
 			volume := &resp.Volumes[i]
 			resources = append(resources, &EC2Volume{
 				svc:                svc,
+				backup:             opts.Backup,
 				VolumeID:           volume.VolumeId,
 				VolumeType:         &volume.VolumeType,
 				State:              &volume.State,
@@ -71,6 +74,8 @@ func (l *EC2VolumeLister) List(ctx context.Context, o interface{}) ([]resource.R
 
 type EC2Volume struct {
 	svc                *ec2.Client
+	backup             *nuke.BackupOptions
+	snapshotID         *string
 	VolumeID           *string               `description:"The ID of the EBS volume"`
 	VolumeType         *ec2types.VolumeType  `description:"The volume type (gp2, gp3, io1, io2, st1, sc1, standard)"`
 	State              *ec2types.VolumeState `description:"The state of the volume (creating, available, in-use, deleting, deleted, error)"`
@@ -86,6 +91,12 @@ type EC2Volume struct {
 }
 
 func (r *EC2Volume) Remove(ctx context.Context) error {
+	if r.backup.Enabled(EC2VolumeResource) && r.snapshotID == nil {
+		if err := r.createSnapshot(ctx); err != nil {
+			return fmt.Errorf("unable to create snapshot before removal: %w", err)
+		}
+	}
+
 	params := &ec2.DeleteVolumeInput{
 		VolumeId: r.VolumeID,
 	}
@@ -94,6 +105,37 @@ func (r *EC2Volume) Remove(ctx context.Context) error {
 	return err
 }
 
+// createSnapshot creates a snapshot of the volume, the volume can be deleted while the snapshot is still pending
+func (r *EC2Volume) createSnapshot(ctx context.Context) error {
+	resp, err := r.svc.CreateSnapshot(ctx, &ec2.CreateSnapshotInput{
+		VolumeId:    r.VolumeID,
+		Description: aws.String(nuke.BackupName(*r.VolumeID, time.Now())),
+		TagSpecifications: []ec2types.TagSpecification{
+			{
+				ResourceType: ec2types.ResourceTypeSnapshot,
+				Tags: []ec2types.Tag{
+					{Key: aws.String(nuke.BackupTagKey), Value: r.VolumeID},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	r.snapshotID = resp.SnapshotId
+
+	return nil
+}
+
+func (r *EC2Volume) BackupArtifacts() []nuke.BackupArtifact {
+	if r.snapshotID == nil {
+		return nil
+	}
+
+	return []nuke.BackupArtifact{{Kind: "ebs-snapshot", ID: *r.snapshotID}}
+}
+
 func (r *EC2Volume) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
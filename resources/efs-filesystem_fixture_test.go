@@ -0,0 +1,65 @@
+package resources
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/aws-sdk-go-v2/service/efs"
+	efsTypes "github.com/aws/aws-sdk-go-v2/service/efs/types"
+
+	"github.com/ekristen/aws-nuke/v3/pkg/httpfixture"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+func Test_Fixture_EFSFileSystem(t *testing.T) {
+	ctx := context.TODO()
+	fixture := httpfixture.New(t, "testdata/fixtures/efs-filesystem.json")
+
+	opts := &nuke.ListerOpts{
+		Region:    &nuke.Region{Name: "us-west-2"},
+		Config:    fixture.Config("us-west-2"),
+		AccountID: ptr.String(fixture.AccountID()),
+	}
+
+	svc := efs.NewFromConfig(*opts.Config)
+
+	created, err := svc.CreateFileSystem(ctx, &efs.CreateFileSystemInput{
+		CreationToken: ptr.String("aws-nuke-fixture-efs"),
+		Tags:          []efsTypes.Tag{{Key: ptr.String("aws-nuke"), Value: ptr.String("fixture")}},
+	})
+	require.NoError(t, err)
+
+	// Note: the file system can only be removed once it is available
+	for state := created.LifeCycleState; state != efsTypes.LifeCycleStateAvailable; {
+		if fixture.Recording() {
+			time.Sleep(time.Second)
+		}
+
+		resp, err := svc.DescribeFileSystems(ctx, &efs.DescribeFileSystemsInput{FileSystemId: created.FileSystemId})
+		require.NoError(t, err)
+		require.Len(t, resp.FileSystems, 1)
+
+		state = resp.FileSystems[0].LifeCycleState
+	}
+
+	resources, err := (&EFSFileSystemLister{}).List(ctx, opts)
+	require.NoError(t, err)
+
+	var fs *EFSFileSystem
+	for _, r := range resources {
+		if r.(*EFSFileSystem).id == *created.FileSystemId {
+			fs = r.(*EFSFileSystem)
+		}
+	}
+
+	require.NotNil(t, fs)
+	assert.Equal(t, "aws-nuke-fixture-efs", fs.String())
+	assert.Equal(t, "fixture", fs.Properties().Get("tag:aws-nuke"))
+
+	require.NoError(t, fs.Remove(ctx))
+}
@@ -2,7 +2,9 @@ package resources
 
 import (
 	"context"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"           //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/elbv2" //nolint:staticcheck
 
 	"github.com/ekristen/libnuke/pkg/registry"
@@ -105,6 +107,10 @@ func (e *ELBv2TargetGroup) Properties() types.Properties {
 
 	properties.Set("IsLoadBalanced", len(e.tg.LoadBalancerArns) > 0)
 
+	if len(e.tg.LoadBalancerArns) > 0 {
+		properties.Set("LoadBalancerARNs", strings.Join(aws.StringValueSlice(e.tg.LoadBalancerArns), ","))
+	}
+
 	return properties
 }
 
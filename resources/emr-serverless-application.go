@@ -82,16 +82,16 @@ func (l *EMRServerlessApplicationLister) List(ctx context.Context, o interface{}
 
 type EMRServerlessApplication struct {
 	svc          *emrserverless.Client
-	ID           *string
-	Name         *string
-	Type         *string
-	State        types.ApplicationState
-	ARN          *string
-	CreatedAt    *time.Time
-	UpdatedAt    *time.Time
-	Tags         map[string]string
-	ReleaseLabel *string
-	Architecture types.Architecture
+	ID           *string                `description:"The unique identifier of the application"`
+	Name         *string                `description:"The name of the application"`
+	Type         *string                `description:"The type of application (Spark or Hive)"`
+	State        types.ApplicationState `description:"The current state of the application (CREATING, CREATED, STARTING, STARTED, STOPPING, STOPPED, TERMINATED)"` //nolint:lll
+	ARN          *string                `description:"The Amazon Resource Name (ARN) of the application"`
+	CreatedAt    *time.Time             `description:"The date and time when the application was created"`
+	UpdatedAt    *time.Time             `description:"The date and time when the application was last updated"`
+	Tags         map[string]string      `description:"Tags associated with the application"`
+	ReleaseLabel *string                `description:"The EMR release version used by the application"`
+	Architecture types.Architecture     `description:"The CPU architecture of the application (ARM64 or X86_64)"`
 }
 
 func (r *EMRServerlessApplication) Remove(ctx context.Context) error {
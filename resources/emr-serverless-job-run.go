@@ -106,15 +106,15 @@ func isJobRunCancellable(state types.JobRunState) bool {
 
 type EMRServerlessJobRun struct {
 	svc             *emrserverless.Client
-	ApplicationID   *string
-	ApplicationName *string
-	JobRunID        *string
-	Name            *string
-	ARN             *string
-	State           types.JobRunState
-	CreatedAt       *time.Time
-	UpdatedAt       *time.Time
-	Tags            map[string]string
+	ApplicationID   *string           `description:"The ID of the EMR Serverless application running this job"`
+	ApplicationName *string           `description:"The name of the EMR Serverless application running this job"`
+	JobRunID        *string           `description:"The unique identifier of the job run"`
+	Name            *string           `description:"The name of the job run"`
+	ARN             *string           `description:"The Amazon Resource Name (ARN) of the job run"`
+	State           types.JobRunState `description:"The current state of the job run (SUBMITTED, PENDING, SCHEDULED, RUNNING, SUCCESS, FAILED, CANCELLING, CANCELLED)"` //nolint:lll
+	CreatedAt       *time.Time        `description:"The date and time when the job run was created"`
+	UpdatedAt       *time.Time        `description:"The date and time when the job run was last updated"`
+	Tags            map[string]string `description:"Tags associated with the job run"`
 }
 
 func (r *EMRServerlessJobRun) Remove(ctx context.Context) error {
@@ -49,6 +49,22 @@ func (e *IAMGroup) Remove(_ context.Context) error {
 	return nil
 }
 
+// ExportPolicy returns the inline and attached policies of the group
+func (e *IAMGroup) ExportPolicy(_ context.Context) (interface{}, error) {
+	export := &IAMPrincipalPolicyExport{
+		Name:             e.name,
+		Path:             e.path,
+		InlinePolicies:   []IAMInlinePolicyExport{},
+		AttachedPolicies: []IAMAttachedPolicyExport{},
+	}
+
+	if err := exportIAMGroupPolicies(e.svc, &e.name, export); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
 func (e *IAMGroup) String() string {
 	return e.name
 }
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -121,6 +122,58 @@ func (r *IAMPolicy) Remove(_ context.Context) error {
 	return nil
 }
 
+// IAMManagedPolicyExport is the exported definition of a customer managed policy including all versions
+type IAMManagedPolicyExport struct {
+	Name     string                   `json:"name"`
+	ARN      string                   `json:"arn"`
+	Path     string                   `json:"path,omitempty"`
+	Versions []IAMPolicyVersionExport `json:"versions"`
+	Tags     map[string]string        `json:"tags,omitempty"`
+}
+
+// IAMPolicyVersionExport is a single version of a managed policy
+type IAMPolicyVersionExport struct {
+	VersionID string          `json:"version_id"`
+	IsDefault bool            `json:"is_default"`
+	Document  json.RawMessage `json:"document"`
+}
+
+// ExportPolicy returns every version of the policy document
+func (r *IAMPolicy) ExportPolicy(_ context.Context) (interface{}, error) {
+	export := &IAMManagedPolicyExport{
+		Name:     aws.StringValue(r.Name),
+		ARN:      aws.StringValue(r.ARN),
+		Path:     aws.StringValue(r.Path),
+		Versions: []IAMPolicyVersionExport{},
+		Tags:     iamTags(r.Tags),
+	}
+
+	resp, err := r.svc.ListPolicyVersions(&iam.ListPolicyVersionsInput{
+		PolicyArn: r.ARN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, version := range resp.Versions {
+		versionResp, err := r.svc.GetPolicyVersion(&iam.GetPolicyVersionInput{
+			PolicyArn: r.ARN,
+			VersionId: version.VersionId,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		export.Versions = append(export.Versions, IAMPolicyVersionExport{
+			VersionID: aws.StringValue(version.VersionId),
+			IsDefault: aws.BoolValue(version.IsDefaultVersion),
+			Document:  policyDocument(versionResp.PolicyVersion.Document),
+		})
+	}
+
+	return export, nil
+}
+
 func (r *IAMPolicy) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -123,6 +123,34 @@ func (r *IAMRole) Remove(_ context.Context) error {
 	}
 }
 
+// ExportPolicy returns the trust policy, inline and attached policies and the permissions boundary of the role
+func (r *IAMRole) ExportPolicy(_ context.Context) (interface{}, error) {
+	role, err := GetIAMRole(r.svc, r.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &IAMPrincipalPolicyExport{
+		Name:             ptr.ToString(role.RoleName),
+		ARN:              ptr.ToString(role.Arn),
+		Path:             ptr.ToString(role.Path),
+		AssumeRolePolicy: policyDocument(role.AssumeRolePolicyDocument),
+		InlinePolicies:   []IAMInlinePolicyExport{},
+		AttachedPolicies: []IAMAttachedPolicyExport{},
+		Tags:             iamTags(role.Tags),
+	}
+
+	if role.PermissionsBoundary != nil {
+		export.PermissionsBoundary = ptr.ToString(role.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+
+	if err := exportIAMRolePolicies(r.svc, r.Name, export); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
 func (r *IAMRole) Properties() types.Properties {
 	return types.NewPropertiesFromStruct(r)
 }
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
@@ -335,3 +336,78 @@ func Test_Mock_IAMRole_Properties(t *testing.T) {
 	a.Equal("/testing", iamRole.Properties().Get("Path"))
 	a.Equal("test", iamRole.Properties().Get("tag:test-key"))
 }
+
+func Test_Mock_IAMRole_ExportPolicy(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockIAM := mock_iamiface.NewMockIAMAPI(ctrl)
+
+	mockIAM.EXPECT().GetRole(&iam.GetRoleInput{
+		RoleName: ptr.String("test"),
+	}).Return(&iam.GetRoleOutput{
+		Role: &iam.Role{
+			Arn:      ptr.String("arn:aws:iam::123456789012:role/test"),
+			RoleName: ptr.String("test"),
+			Path:     ptr.String("/"),
+			AssumeRolePolicyDocument: ptr.String(
+				"%7B%22Version%22%3A%222012-10-17%22%2C%22Statement%22%3A%5B%5D%7D"),
+			PermissionsBoundary: &iam.AttachedPermissionsBoundary{
+				PermissionsBoundaryArn: ptr.String("arn:aws:iam::123456789012:policy/boundary"),
+			},
+			Tags: []*iam.Tag{{Key: ptr.String("team"), Value: ptr.String("platform")}},
+		},
+	}, nil)
+
+	mockIAM.EXPECT().ListRolePoliciesPages(&iam.ListRolePoliciesInput{RoleName: ptr.String("test")}, gomock.Any()).
+		DoAndReturn(func(_ *iam.ListRolePoliciesInput, fn func(*iam.ListRolePoliciesOutput, bool) bool) error {
+			fn(&iam.ListRolePoliciesOutput{PolicyNames: []*string{ptr.String("inline")}}, true)
+			return nil
+		})
+
+	mockIAM.EXPECT().GetRolePolicy(&iam.GetRolePolicyInput{
+		RoleName:   ptr.String("test"),
+		PolicyName: ptr.String("inline"),
+	}).Return(&iam.GetRolePolicyOutput{
+		PolicyDocument: ptr.String("%7B%22Statement%22%3A%5B%5D%7D"),
+	}, nil)
+
+	mockIAM.EXPECT().ListAttachedRolePoliciesPages(&iam.ListAttachedRolePoliciesInput{RoleName: ptr.String("test")},
+		gomock.Any()).
+		DoAndReturn(func(_ *iam.ListAttachedRolePoliciesInput,
+			fn func(*iam.ListAttachedRolePoliciesOutput, bool) bool) error {
+			fn(&iam.ListAttachedRolePoliciesOutput{
+				AttachedPolicies: []*iam.AttachedPolicy{
+					{
+						PolicyName: ptr.String("ReadOnlyAccess"),
+						PolicyArn:  ptr.String("arn:aws:iam::aws:policy/ReadOnlyAccess"),
+					},
+				},
+			}, true)
+			return nil
+		})
+
+	role := IAMRole{
+		svc:  mockIAM,
+		Name: ptr.String("test"),
+		Path: ptr.String("/"),
+	}
+
+	export, err := role.ExportPolicy(context.TODO())
+	a.Nil(err)
+	a.Equal(&IAMPrincipalPolicyExport{
+		Name:                "test",
+		ARN:                 "arn:aws:iam::123456789012:role/test",
+		Path:                "/",
+		AssumeRolePolicy:    json.RawMessage(`{"Version":"2012-10-17","Statement":[]}`),
+		PermissionsBoundary: "arn:aws:iam::123456789012:policy/boundary",
+		InlinePolicies: []IAMInlinePolicyExport{
+			{Name: "inline", Document: json.RawMessage(`{"Statement":[]}`)},
+		},
+		AttachedPolicies: []IAMAttachedPolicyExport{
+			{Name: "ReadOnlyAccess", ARN: "arn:aws:iam::aws:policy/ReadOnlyAccess"},
+		},
+		Tags: map[string]string{"team": "platform"},
+	}, export)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/gotidy/ptr"
 	"github.com/sirupsen/logrus"
 
 	"github.com/aws/aws-sdk-go/service/iam" //nolint:staticcheck
@@ -77,6 +78,33 @@ func (r *IAMUser) Remove(_ context.Context) error {
 	return nil
 }
 
+// ExportPolicy returns the inline and attached policies, groups and the permissions boundary of the user
+func (r *IAMUser) ExportPolicy(_ context.Context) (interface{}, error) {
+	user, err := GetIAMUser(r.svc, r.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &IAMPrincipalPolicyExport{
+		Name:             ptr.ToString(user.UserName),
+		ARN:              ptr.ToString(user.Arn),
+		Path:             ptr.ToString(user.Path),
+		InlinePolicies:   []IAMInlinePolicyExport{},
+		AttachedPolicies: []IAMAttachedPolicyExport{},
+		Tags:             iamTags(user.Tags),
+	}
+
+	if user.PermissionsBoundary != nil {
+		export.PermissionsBoundary = ptr.ToString(user.PermissionsBoundary.PermissionsBoundaryArn)
+	}
+
+	if err := exportIAMUserPolicies(r.svc, r.Name, export); err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
 func (r *IAMUser) String() string {
 	return *r.Name
 }
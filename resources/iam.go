@@ -0,0 +1,185 @@
+package resources
+
+import (
+	"encoding/json"
+	"net/url"
+
+	"github.com/gotidy/ptr"
+
+	"github.com/aws/aws-sdk-go/service/iam" //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+)
+
+// IAMPrincipalPolicyExport is the exported definition of an IAM role, user or group
+type IAMPrincipalPolicyExport struct {
+	Name                string                    `json:"name"`
+	ARN                 string                    `json:"arn,omitempty"`
+	Path                string                    `json:"path,omitempty"`
+	AssumeRolePolicy    json.RawMessage           `json:"assume_role_policy,omitempty"`
+	PermissionsBoundary string                    `json:"permissions_boundary,omitempty"`
+	InlinePolicies      []IAMInlinePolicyExport   `json:"inline_policies"`
+	AttachedPolicies    []IAMAttachedPolicyExport `json:"attached_policies"`
+	Groups              []string                  `json:"groups,omitempty"`
+	Tags                map[string]string         `json:"tags,omitempty"`
+}
+
+// IAMInlinePolicyExport is an inline policy of an IAM role, user or group
+type IAMInlinePolicyExport struct {
+	Name     string          `json:"name"`
+	Document json.RawMessage `json:"document"`
+}
+
+// IAMAttachedPolicyExport is a managed policy attached to an IAM role, user or group
+type IAMAttachedPolicyExport struct {
+	Name string `json:"name"`
+	ARN  string `json:"arn"`
+}
+
+// ResourcePolicyExport is the exported resource policy of a resource, e.g. a bucket policy
+type ResourcePolicyExport struct {
+	ID     string          `json:"id"`
+	ARN    string          `json:"arn,omitempty"`
+	Policy json.RawMessage `json:"policy"`
+}
+
+// policyDocument returns the policy document as raw JSON, IAM returns the documents URL encoded. A document that is
+// not valid JSON is returned as a JSON string, so it is never lost. An empty document is returned as null.
+func policyDocument(document *string) json.RawMessage {
+	value := ptr.ToString(document)
+	if value == "" {
+		return json.RawMessage("null")
+	}
+
+	if json.Valid([]byte(value)) {
+		return json.RawMessage(value)
+	}
+
+	if decoded, err := url.PathUnescape(value); err == nil && json.Valid([]byte(decoded)) {
+		return json.RawMessage(decoded)
+	}
+
+	quoted, _ := json.Marshal(value)
+
+	return quoted
+}
+
+// iamTags converts the tags to a map for the export
+func iamTags(tags []*iam.Tag) map[string]string {
+	out := map[string]string{}
+	for _, tag := range tags {
+		out[ptr.ToString(tag.Key)] = ptr.ToString(tag.Value)
+	}
+
+	return out
+}
+
+// exportIAMRolePolicies adds the inline and attached policies of the role to the export
+func exportIAMRolePolicies(svc iamiface.IAMAPI, name *string, export *IAMPrincipalPolicyExport) error {
+	var names []*string
+	if err := svc.ListRolePoliciesPages(&iam.ListRolePoliciesInput{RoleName: name},
+		func(page *iam.ListRolePoliciesOutput, _ bool) bool {
+			names = append(names, page.PolicyNames...)
+			return true
+		}); err != nil {
+		return err
+	}
+
+	for _, policyName := range names {
+		resp, err := svc.GetRolePolicy(&iam.GetRolePolicyInput{RoleName: name, PolicyName: policyName})
+		if err != nil {
+			return err
+		}
+
+		export.InlinePolicies = append(export.InlinePolicies, IAMInlinePolicyExport{
+			Name:     ptr.ToString(policyName),
+			Document: policyDocument(resp.PolicyDocument),
+		})
+	}
+
+	return svc.ListAttachedRolePoliciesPages(&iam.ListAttachedRolePoliciesInput{RoleName: name},
+		func(page *iam.ListAttachedRolePoliciesOutput, _ bool) bool {
+			export.AttachedPolicies = appendAttachedPolicies(export.AttachedPolicies, page.AttachedPolicies)
+			return true
+		})
+}
+
+// exportIAMUserPolicies adds the inline and attached policies, and the groups of the user to the export
+func exportIAMUserPolicies(svc iamiface.IAMAPI, name *string, export *IAMPrincipalPolicyExport) error {
+	var names []*string
+	if err := svc.ListUserPoliciesPages(&iam.ListUserPoliciesInput{UserName: name},
+		func(page *iam.ListUserPoliciesOutput, _ bool) bool {
+			names = append(names, page.PolicyNames...)
+			return true
+		}); err != nil {
+		return err
+	}
+
+	for _, policyName := range names {
+		resp, err := svc.GetUserPolicy(&iam.GetUserPolicyInput{UserName: name, PolicyName: policyName})
+		if err != nil {
+			return err
+		}
+
+		export.InlinePolicies = append(export.InlinePolicies, IAMInlinePolicyExport{
+			Name:     ptr.ToString(policyName),
+			Document: policyDocument(resp.PolicyDocument),
+		})
+	}
+
+	if err := svc.ListAttachedUserPoliciesPages(&iam.ListAttachedUserPoliciesInput{UserName: name},
+		func(page *iam.ListAttachedUserPoliciesOutput, _ bool) bool {
+			export.AttachedPolicies = appendAttachedPolicies(export.AttachedPolicies, page.AttachedPolicies)
+			return true
+		}); err != nil {
+		return err
+	}
+
+	return svc.ListGroupsForUserPages(&iam.ListGroupsForUserInput{UserName: name},
+		func(page *iam.ListGroupsForUserOutput, _ bool) bool {
+			for _, group := range page.Groups {
+				export.Groups = append(export.Groups, ptr.ToString(group.GroupName))
+			}
+			return true
+		})
+}
+
+// exportIAMGroupPolicies adds the inline and attached policies of the group to the export
+func exportIAMGroupPolicies(svc iamiface.IAMAPI, name *string, export *IAMPrincipalPolicyExport) error {
+	var names []*string
+	if err := svc.ListGroupPoliciesPages(&iam.ListGroupPoliciesInput{GroupName: name},
+		func(page *iam.ListGroupPoliciesOutput, _ bool) bool {
+			names = append(names, page.PolicyNames...)
+			return true
+		}); err != nil {
+		return err
+	}
+
+	for _, policyName := range names {
+		resp, err := svc.GetGroupPolicy(&iam.GetGroupPolicyInput{GroupName: name, PolicyName: policyName})
+		if err != nil {
+			return err
+		}
+
+		export.InlinePolicies = append(export.InlinePolicies, IAMInlinePolicyExport{
+			Name:     ptr.ToString(policyName),
+			Document: policyDocument(resp.PolicyDocument),
+		})
+	}
+
+	return svc.ListAttachedGroupPoliciesPages(&iam.ListAttachedGroupPoliciesInput{GroupName: name},
+		func(page *iam.ListAttachedGroupPoliciesOutput, _ bool) bool {
+			export.AttachedPolicies = appendAttachedPolicies(export.AttachedPolicies, page.AttachedPolicies)
+			return true
+		})
+}
+
+func appendAttachedPolicies(out []IAMAttachedPolicyExport, policies []*iam.AttachedPolicy) []IAMAttachedPolicyExport {
+	for _, policy := range policies {
+		out = append(out, IAMAttachedPolicyExport{
+			Name: ptr.ToString(policy.PolicyName),
+			ARN:  ptr.ToString(policy.PolicyArn),
+		})
+	}
+
+	return out
+}
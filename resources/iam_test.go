@@ -0,0 +1,30 @@
+package resources
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_policyDocument(t *testing.T) {
+	cases := []struct {
+		name     string
+		document *string
+		want     string
+	}{
+		{name: "nil", document: nil, want: "null"},
+		{name: "empty", document: ptr.String(""), want: "null"},
+		{name: "json", document: ptr.String(`{"Statement":[]}`), want: `{"Statement":[]}`},
+		{name: "url encoded", document: ptr.String("%7B%22Statement%22%3A%5B%5D%7D"), want: `{"Statement":[]}`},
+		{name: "plus in json", document: ptr.String(`{"Sid":"a+b"}`), want: `{"Sid":"a+b"}`},
+		{name: "invalid", document: ptr.String("not json"), want: `"not json"`},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, json.RawMessage(tc.want), policyDocument(tc.document))
+		})
+	}
+}
@@ -154,6 +154,22 @@ func (r *KMSKey) Remove(_ context.Context) error {
 	return err
 }
 
+// ExportPolicy returns the default key policy
+func (r *KMSKey) ExportPolicy(_ context.Context) (interface{}, error) {
+	resp, err := r.svc.GetKeyPolicy(&kms.GetKeyPolicyInput{
+		KeyId:      r.ID,
+		PolicyName: aws.String("default"),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourcePolicyExport{
+		ID:     ptr.ToString(r.ID),
+		Policy: policyDocument(resp.Policy),
+	}, nil
+}
+
 func (r *KMSKey) String() string {
 	return *r.ID
 }
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -237,3 +238,30 @@ func Test_Mock_KMSKey_Remove(t *testing.T) {
 	err := kmsKey.Remove(context.TODO())
 	a.NoError(err)
 }
+
+func Test_Mock_KMSKey_ExportPolicy(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockKMS := mock_kmsiface.NewMockKMSAPI(ctrl)
+
+	mockKMS.EXPECT().GetKeyPolicy(&kms.GetKeyPolicyInput{
+		KeyId:      aws.String("test-key-id"),
+		PolicyName: aws.String("default"),
+	}).Return(&kms.GetKeyPolicyOutput{
+		Policy: aws.String(`{"Version":"2012-10-17","Statement":[]}`),
+	}, nil)
+
+	kmsKey := KMSKey{
+		svc: mockKMS,
+		ID:  aws.String("test-key-id"),
+	}
+
+	export, err := kmsKey.ExportPolicy(context.TODO())
+	a.Nil(err)
+	a.Equal(&ResourcePolicyExport{
+		ID:     "test-key-id",
+		Policy: json.RawMessage(`{"Version":"2012-10-17","Statement":[]}`),
+	}, export)
+}
@@ -2,8 +2,12 @@ package resources
 
 import (
 	"context"
+	"errors"
+
+	"github.com/gotidy/ptr"
 
 	"github.com/aws/aws-sdk-go-v2/service/lambda"
+	lambdatypes "github.com/aws/aws-sdk-go-v2/service/lambda/types"
 
 	"github.com/ekristen/libnuke/pkg/registry"
 	"github.com/ekristen/libnuke/pkg/resource"
@@ -81,6 +85,31 @@ func (r *LambdaFunction) Remove(ctx context.Context) error {
 	return err
 }
 
+// ExportPolicy returns the resource-based policy of the function, which holds the permissions granted with
+// AddPermission. A function without permissions is exported with a null policy.
+func (r *LambdaFunction) ExportPolicy(ctx context.Context) (interface{}, error) {
+	export := &ResourcePolicyExport{
+		ID:     ptr.ToString(r.Name),
+		Policy: policyDocument(nil),
+	}
+
+	resp, err := r.svc.GetPolicy(ctx, &lambda.GetPolicyInput{
+		FunctionName: r.Name,
+	})
+	if err != nil {
+		var notFound *lambdatypes.ResourceNotFoundException
+		if errors.As(err, &notFound) {
+			return export, nil
+		}
+
+		return nil, err
+	}
+
+	export.Policy = policyDocument(resp.Policy)
+
+	return export, nil
+}
+
 func (r *LambdaFunction) String() string {
 	return *r.Name
 }
@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/gotidy/ptr"
 
@@ -71,6 +72,7 @@ func (l *NeptuneClusterLister) List(_ context.Context, o interface{}) ([]resourc
 
 			resources = append(resources, &NeptuneCluster{
 				svc:    svc,
+				backup: opts.Backup,
 				ID:     dbCluster.DBClusterIdentifier,
 				Status: dbCluster.Status,
 				Tags:   dbTags,
@@ -88,8 +90,10 @@ func (l *NeptuneClusterLister) List(_ context.Context, o interface{}) ([]resourc
 }
 
 type NeptuneCluster struct {
-	svc      *neptune.Neptune
-	settings *libsettings.Setting
+	svc           *neptune.Neptune
+	settings      *libsettings.Setting
+	backup        *nuke.BackupOptions
+	finalSnapshot *string
 
 	ID     *string
 	Status *string
@@ -118,12 +122,33 @@ func (r *NeptuneCluster) Remove(_ context.Context) error {
 		}
 	}
 
-	_, err := r.svc.DeleteDBCluster(&neptune.DeleteDBClusterInput{
+	params := &neptune.DeleteDBClusterInput{
 		DBClusterIdentifier: r.ID,
 		SkipFinalSnapshot:   ptr.Bool(true),
-	})
+	}
+
+	var finalSnapshot *string
+	if r.backup.Enabled(NeptuneClusterResource) {
+		finalSnapshot = ptr.String(nuke.BackupName(*r.ID, time.Now()))
+		params.SkipFinalSnapshot = ptr.Bool(false)
+		params.FinalDBSnapshotIdentifier = finalSnapshot
+	}
+
+	if _, err := r.svc.DeleteDBCluster(params); err != nil {
+		return err
+	}
+
+	r.finalSnapshot = finalSnapshot
+
+	return nil
+}
+
+func (r *NeptuneCluster) BackupArtifacts() []nuke.BackupArtifact {
+	if r.finalSnapshot == nil {
+		return nil
+	}
 
-	return err
+	return []nuke.BackupArtifact{{Kind: "neptune-cluster-snapshot", ID: *r.finalSnapshot}}
 }
 
 func (r *NeptuneCluster) Properties() types.Properties {
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/rds" //nolint:staticcheck
@@ -54,6 +55,7 @@ func (l *RDSDBClusterLister) List(_ context.Context, o interface{}) ([]resource.
 			id:                 *instance.DBClusterIdentifier,
 			deletionProtection: *instance.DeletionProtection,
 			tags:               tags.TagList,
+			backup:             opts.Backup,
 		})
 	}
 
@@ -65,6 +67,8 @@ type RDSDBCluster struct {
 	id                 string
 	deletionProtection bool
 	tags               []*rds.Tag
+	backup             *nuke.BackupOptions
+	finalSnapshot      *string
 }
 
 func (i *RDSDBCluster) Remove(_ context.Context) error {
@@ -84,14 +88,31 @@ func (i *RDSDBCluster) Remove(_ context.Context) error {
 		SkipFinalSnapshot:   aws.Bool(true),
 	}
 
+	var finalSnapshot *string
+	if i.backup.Enabled(RDSDBClusterResource) {
+		finalSnapshot = aws.String(nuke.BackupName(i.id, time.Now()))
+		params.SkipFinalSnapshot = aws.Bool(false)
+		params.FinalDBSnapshotIdentifier = finalSnapshot
+	}
+
 	_, err := i.svc.DeleteDBCluster(params)
 	if err != nil {
 		return err
 	}
 
+	i.finalSnapshot = finalSnapshot
+
 	return nil
 }
 
+func (i *RDSDBCluster) BackupArtifacts() []nuke.BackupArtifact {
+	if i.finalSnapshot == nil {
+		return nil
+	}
+
+	return []nuke.BackupArtifact{{Kind: "rds-cluster-snapshot", ID: *i.finalSnapshot}}
+}
+
 func (i *RDSDBCluster) String() string {
 	return i.id
 }
@@ -42,6 +42,9 @@ type RDSInstance struct {
 	tags     []*rds.Tag
 
 	settings *libsettings.Setting
+
+	backup        *nuke.BackupOptions
+	finalSnapshot *string
 }
 
 type RDSInstanceLister struct{}
@@ -82,6 +85,7 @@ func (l *RDSInstanceLister) List(_ context.Context, o interface{}) ([]resource.R
 			svc:      svc,
 			instance: instance,
 			tags:     tags.TagList,
+			backup:   opts.Backup,
 		})
 	}
 
@@ -138,13 +142,31 @@ func (i *RDSInstance) deleteDBInstance() error {
 		SkipFinalSnapshot:    aws.Bool(true),
 	}
 
+	// Note: instances that are part of a cluster can not have a final snapshot, the cluster snapshot covers them
+	var finalSnapshot *string
+	if i.backup.Enabled(RDSInstanceResource) && i.instance.DBClusterIdentifier == nil {
+		finalSnapshot = aws.String(nuke.BackupName(ptr.ToString(i.instance.DBInstanceIdentifier), time.Now()))
+		params.SkipFinalSnapshot = aws.Bool(false)
+		params.FinalDBSnapshotIdentifier = finalSnapshot
+	}
+
 	if _, err := i.svc.DeleteDBInstance(params); err != nil {
 		return err
 	}
 
+	i.finalSnapshot = finalSnapshot
+
 	return nil
 }
 
+func (i *RDSInstance) BackupArtifacts() []nuke.BackupArtifact {
+	if i.finalSnapshot == nil {
+		return nil
+	}
+
+	return []nuke.BackupArtifact{{Kind: "rds-snapshot", ID: *i.finalSnapshot}}
+}
+
 func (i *RDSInstance) getDBInstanceStatus() (string, error) {
 	resp, err := i.svc.DescribeDBInstances(&rds.DescribeDBInstancesInput{
 		DBInstanceIdentifier: i.instance.DBInstanceIdentifier,
@@ -253,6 +253,30 @@ func (r *S3Bucket) RemoveAllObjects(ctx context.Context) error {
 	return awsmod.NewBatchDeleteWithClient(r.svc, batchSize).Delete(ctx, iterator, opts...)
 }
 
+// ExportPolicy returns the bucket policy, a bucket without a policy is exported with a null policy
+func (r *S3Bucket) ExportPolicy(ctx context.Context) (interface{}, error) {
+	export := &ResourcePolicyExport{
+		ID:     ptr.ToString(r.Name),
+		Policy: policyDocument(nil),
+	}
+
+	resp, err := r.svc.GetBucketPolicy(ctx, &s3.GetBucketPolicyInput{
+		Bucket: r.Name,
+	})
+	if err != nil {
+		var aerr smithy.APIError
+		if errors.As(err, &aerr) && aerr.ErrorCode() == "NoSuchBucketPolicy" {
+			return export, nil
+		}
+
+		return nil, err
+	}
+
+	export.Policy = policyDocument(resp.Policy)
+
+	return export, nil
+}
+
 func (r *S3Bucket) Settings(settings *libsettings.Setting) {
 	r.settings = settings
 }
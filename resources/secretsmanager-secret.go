@@ -3,6 +3,7 @@ package resources
 import (
 	"context"
 	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 
@@ -78,6 +79,7 @@ func (l *SecretsManagerSecretLister) List(_ context.Context, o interface{}) ([]r
 			resources = append(resources, &SecretsManagerSecret{
 				svc:           svc,
 				primarySvc:    primarySvc,
+				backup:        opts.Backup,
 				region:        ptr.String(opts.Region.Name),
 				ARN:           secret.ARN,
 				Name:          secret.Name,
@@ -100,6 +102,8 @@ func (l *SecretsManagerSecretLister) List(_ context.Context, o interface{}) ([]r
 type SecretsManagerSecret struct {
 	svc            secretsmanageriface.SecretsManagerAPI
 	primarySvc     secretsmanageriface.SecretsManagerAPI
+	backup         *nuke.BackupOptions
+	export         *string
 	region         *string
 	ARN            *string
 	Name           *string
@@ -126,6 +130,13 @@ func (r *SecretsManagerSecret) Remove(_ context.Context) error {
 		return err
 	}
 
+	// Note: replicas are skipped above, removing a replica does not remove the value from the primary region
+	if r.backup.Enabled(SecretsManagerSecretResource) && r.export == nil {
+		if err := r.exportValue(); err != nil {
+			return fmt.Errorf("unable to export secret before removal: %w", err)
+		}
+	}
+
 	_, err := r.svc.DeleteSecret(&secretsmanager.DeleteSecretInput{
 		SecretId:                   r.ARN,
 		ForceDeleteWithoutRecovery: aws.Bool(true),
@@ -134,6 +145,56 @@ func (r *SecretsManagerSecret) Remove(_ context.Context) error {
 	return err
 }
 
+// SecretsManagerSecretExport is the content of the encrypted export of a secret
+type SecretsManagerSecretExport struct {
+	ARN          string            `json:"arn"`
+	Name         string            `json:"name"`
+	VersionID    string            `json:"version_id"`
+	SecretString string            `json:"secret_string,omitempty"`
+	SecretBinary []byte            `json:"secret_binary,omitempty"`
+	Tags         map[string]string `json:"tags,omitempty"`
+}
+
+// exportValue writes the current value of the secret to an encrypted local file
+func (r *SecretsManagerSecret) exportValue() error {
+	resp, err := r.svc.GetSecretValue(&secretsmanager.GetSecretValueInput{
+		SecretId: r.ARN,
+	})
+	if err != nil {
+		return err
+	}
+
+	export := &SecretsManagerSecretExport{
+		ARN:          ptr.ToString(resp.ARN),
+		Name:         ptr.ToString(resp.Name),
+		VersionID:    ptr.ToString(resp.VersionId),
+		SecretString: ptr.ToString(resp.SecretString),
+		SecretBinary: resp.SecretBinary,
+		Tags:         map[string]string{},
+	}
+
+	for _, tag := range r.tags {
+		export.Tags[ptr.ToString(tag.Key)] = ptr.ToString(tag.Value)
+	}
+
+	path, err := r.backup.WriteExport(SecretsManagerSecretResource, ptr.ToString(r.Name), export)
+	if err != nil {
+		return err
+	}
+
+	r.export = &path
+
+	return nil
+}
+
+func (r *SecretsManagerSecret) BackupArtifacts() []nuke.BackupArtifact {
+	if r.export == nil {
+		return nil
+	}
+
+	return []nuke.BackupArtifact{{Kind: "encrypted-export", ID: *r.export}}
+}
+
 func (r *SecretsManagerSecret) Filter() error {
 	if managedRegex.MatchString(*r.Name) {
 		return errAWSManaged
@@ -2,6 +2,8 @@ package resources
 
 import (
 	"context"
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/golang/mock/gomock"
@@ -11,6 +13,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/secretsmanager" //nolint:staticcheck
 
 	"github.com/ekristen/aws-nuke/v3/mocks/mock_secretsmanageriface"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
 )
 
 func Test_Mock_SecretsManager_List(t *testing.T) {
@@ -106,3 +109,60 @@ func Test_Mock_SecretsManager_Secret_RemoveReplica(t *testing.T) {
 	err := resource.Remove(context.TODO())
 	a.Nil(err)
 }
+
+func Test_Mock_SecretsManager_Secret_RemoveBackup(t *testing.T) {
+	a := assert.New(t)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSvc := mock_secretsmanageriface.NewMockSecretsManagerAPI(ctrl)
+
+	backup := &nuke.BackupOptions{
+		ResourceTypes:   []string{SecretsManagerSecretResource},
+		ExportDirectory: t.TempDir(),
+		Passphrase:      "passphrase",
+	}
+
+	resource := SecretsManagerSecret{
+		svc:    mockSvc,
+		backup: backup,
+		ARN:    ptr.String("arn:foo"),
+		Name:   ptr.String("foo"),
+		tags: []*secretsmanager.Tag{
+			{Key: ptr.String("team"), Value: ptr.String("platform")},
+		},
+	}
+
+	mockSvc.EXPECT().GetSecretValue(gomock.Eq(&secretsmanager.GetSecretValueInput{
+		SecretId: ptr.String("arn:foo"),
+	})).Return(&secretsmanager.GetSecretValueOutput{
+		ARN:          ptr.String("arn:foo"),
+		Name:         ptr.String("foo"),
+		VersionId:    ptr.String("v1"),
+		SecretString: ptr.String("hunter2"),
+	}, nil)
+
+	mockSvc.EXPECT().DeleteSecret(gomock.Eq(&secretsmanager.DeleteSecretInput{
+		SecretId:                   ptr.String("arn:foo"),
+		ForceDeleteWithoutRecovery: ptr.Bool(true),
+	})).Return(&secretsmanager.DeleteSecretOutput{}, nil)
+
+	err := resource.Remove(context.TODO())
+	a.Nil(err)
+
+	artifacts := resource.BackupArtifacts()
+	a.Len(artifacts, 1)
+	a.Equal("encrypted-export", artifacts[0].Kind)
+
+	sealed, err := os.ReadFile(artifacts[0].ID)
+	a.NoError(err)
+
+	data, err := nuke.DecryptExport(sealed, "passphrase")
+	a.NoError(err)
+
+	var export SecretsManagerSecretExport
+	a.NoError(json.Unmarshal(data, &export))
+	a.Equal("hunter2", export.SecretString)
+	a.Equal("v1", export.VersionID)
+	a.Equal(map[string]string{"team": "platform"}, export.Tags)
+}
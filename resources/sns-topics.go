@@ -74,6 +74,22 @@ func (topic *SNSTopic) Remove(_ context.Context) error {
 	return err
 }
 
+// ExportPolicy returns the access policy of the topic
+func (topic *SNSTopic) ExportPolicy(_ context.Context) (interface{}, error) {
+	resp, err := topic.svc.GetTopicAttributes(&sns.GetTopicAttributesInput{
+		TopicArn: topic.id,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourcePolicyExport{
+		ID:     *topic.id,
+		ARN:    *topic.id,
+		Policy: policyDocument(resp.Attributes["Policy"]),
+	}, nil
+}
+
 func (topic *SNSTopic) Properties() types.Properties {
 	properties := types.NewProperties()
 
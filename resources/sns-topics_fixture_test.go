@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"context"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
+	"github.com/aws/aws-sdk-go/service/sns" //nolint:staticcheck
+
+	"github.com/ekristen/aws-nuke/v3/pkg/httpfixture"
+	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
+)
+
+func Test_Fixture_SNSTopic(t *testing.T) {
+	ctx := context.TODO()
+	fixture := httpfixture.New(t, "testdata/fixtures/sns-topics.json")
+
+	opts := &nuke.ListerOpts{
+		Region:    &nuke.Region{Name: "us-east-1"},
+		Session:   fixture.Session("us-east-1"),
+		AccountID: ptr.String(fixture.AccountID()),
+	}
+
+	created, err := sns.New(opts.Session).CreateTopic(&sns.CreateTopicInput{
+		Name: aws.String("aws-nuke-fixture-topic"),
+		Tags: []*sns.Tag{{Key: aws.String("aws-nuke"), Value: aws.String("fixture")}},
+	})
+	require.NoError(t, err)
+
+	find := func() *SNSTopic {
+		resources, err := (&SNSTopicLister{}).List(ctx, opts)
+		require.NoError(t, err)
+
+		for _, r := range resources {
+			if topic := r.(*SNSTopic); *topic.id == *created.TopicArn {
+				return topic
+			}
+		}
+
+		return nil
+	}
+
+	topic := find()
+	require.NotNil(t, topic)
+	assert.Equal(t, "fixture", topic.Properties().Get("tag:aws-nuke"))
+
+	require.NoError(t, topic.Remove(ctx))
+	assert.Nil(t, find())
+}
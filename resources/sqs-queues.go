@@ -84,6 +84,23 @@ func (f *SQSQueue) Remove(_ context.Context) error {
 	return err
 }
 
+// ExportPolicy returns the access policy of the queue
+func (f *SQSQueue) ExportPolicy(_ context.Context) (interface{}, error) {
+	resp, err := f.svc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       f.queueURL,
+		AttributeNames: []*string{ptr.String(sqs.QueueAttributeNamePolicy), ptr.String(sqs.QueueAttributeNameQueueArn)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResourcePolicyExport{
+		ID:     ptr.ToString(f.queueURL),
+		ARN:    ptr.ToString(resp.Attributes[sqs.QueueAttributeNameQueueArn]),
+		Policy: policyDocument(resp.Attributes[sqs.QueueAttributeNamePolicy]),
+	}, nil
+}
+
 func (f *SQSQueue) String() string {
 	return ptr.ToString(f.queueURL)
 }
@@ -2,6 +2,7 @@ package resources
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go/aws"         //nolint:staticcheck
 	"github.com/aws/aws-sdk-go/service/ssm" //nolint:staticcheck
@@ -54,9 +55,10 @@ func (l *SSMParameterLister) List(_ context.Context, o interface{}) ([]resource.
 			}
 
 			resources = append(resources, &SSMParameter{
-				svc:  svc,
-				name: parameter.Name,
-				tags: tagResp.TagList,
+				svc:    svc,
+				backup: opts.Backup,
+				name:   parameter.Name,
+				tags:   tagResp.TagList,
 			})
 		}
 
@@ -71,12 +73,29 @@ func (l *SSMParameterLister) List(_ context.Context, o interface{}) ([]resource.
 }
 
 type SSMParameter struct {
-	svc  *ssm.SSM
-	name *string
-	tags []*ssm.Tag
+	svc    *ssm.SSM
+	backup *nuke.BackupOptions
+	export *string
+	name   *string
+	tags   []*ssm.Tag
+}
+
+// SSMParameterExport is the content of the encrypted export of a parameter
+type SSMParameterExport struct {
+	Name    string            `json:"name"`
+	Type    string            `json:"type"`
+	Value   string            `json:"value"`
+	Version int64             `json:"version"`
+	Tags    map[string]string `json:"tags,omitempty"`
 }
 
 func (f *SSMParameter) Remove(_ context.Context) error {
+	if f.backup.Enabled(SSMParameterResource) && f.export == nil {
+		if err := f.exportValue(); err != nil {
+			return fmt.Errorf("unable to export parameter before removal: %w", err)
+		}
+	}
+
 	_, err := f.svc.DeleteParameter(&ssm.DeleteParameterInput{
 		Name: f.name,
 	})
@@ -84,6 +103,46 @@ func (f *SSMParameter) Remove(_ context.Context) error {
 	return err
 }
 
+// exportValue writes the decrypted value of the parameter to an encrypted local file
+func (f *SSMParameter) exportValue() error {
+	resp, err := f.svc.GetParameter(&ssm.GetParameterInput{
+		Name:           f.name,
+		WithDecryption: aws.Bool(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	export := &SSMParameterExport{
+		Name:    aws.StringValue(resp.Parameter.Name),
+		Type:    aws.StringValue(resp.Parameter.Type),
+		Value:   aws.StringValue(resp.Parameter.Value),
+		Version: aws.Int64Value(resp.Parameter.Version),
+		Tags:    map[string]string{},
+	}
+
+	for _, tag := range f.tags {
+		export.Tags[aws.StringValue(tag.Key)] = aws.StringValue(tag.Value)
+	}
+
+	path, err := f.backup.WriteExport(SSMParameterResource, *f.name, export)
+	if err != nil {
+		return err
+	}
+
+	f.export = &path
+
+	return nil
+}
+
+func (f *SSMParameter) BackupArtifacts() []nuke.BackupArtifact {
+	if f.export == nil {
+		return nil
+	}
+
+	return []nuke.BackupArtifact{{Kind: "encrypted-export", ID: *f.export}}
+}
+
 func (f *SSMParameter) String() string {
 	return *f.name
 }
@@ -2,8 +2,13 @@ package main
 
 import (
 	"bytes"
+	"errors"
+	"flag"
 	"fmt"
+	"go/format"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"text/template"
 
@@ -13,15 +18,38 @@ import (
 	"github.com/iancoleman/strcase"
 )
 
+const interfaceTemplate = `package resources
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/service/{{.Service}}"
+)
+
+// {{.Interface}} defines the interface for {{.ServiceTitle}} API operations.
+// Defined for dependency injection and test mocking.
+type {{.Interface}} interface {
+	List{{.ResourceTypeTitle}}s(ctx context.Context, params *{{.Service}}.List{{.ResourceTypeTitle}}sInput,
+		optFns ...func(*{{.Service}}.Options)) (*{{.Service}}.List{{.ResourceTypeTitle}}sOutput, error)
+	Delete{{.ResourceTypeTitle}}(ctx context.Context, params *{{.Service}}.Delete{{.ResourceTypeTitle}}Input,
+		optFns ...func(*{{.Service}}.Options)) (*{{.Service}}.Delete{{.ResourceTypeTitle}}Output, error)
+{{- if .Tags}}
+	ListTagsForResource(ctx context.Context, params *{{.Service}}.ListTagsForResourceInput,
+		optFns ...func(*{{.Service}}.Options)) (*{{.Service}}.ListTagsForResourceOutput, error)
+{{- end}}
+}
+`
+
 const resourceTemplate = `package resources
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/aws/aws-sdk-go-v2/service/{{.Service}}"
 
-	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/registry"
+	"github.com/ekristen/libnuke/pkg/resource"
 	"github.com/ekristen/libnuke/pkg/types"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/nuke"
@@ -38,40 +66,77 @@ func init() {
 	})
 }
 
-type {{.Combined}}Lister struct{}
+type {{.Combined}}Lister struct {
+	svc {{.Interface}}
+}
 
 func (l *{{.Combined}}Lister) List(ctx context.Context, o interface{}) ([]resource.Resource, error) {
-	opts := o.(*nuke.ListerOpts)
-	svc := {{.Service}}.NewFromConfig(*opts.Config)
 	var resources []resource.Resource
 
-	// NOTE: you might have to modify the code below to actually work, this currently does not 
+	if l.svc == nil {
+		opts := o.(*nuke.ListerOpts)
+		l.svc = {{.Service}}.NewFromConfig(*opts.Config)
+	}
+
+	// NOTE: you might have to modify the code below to actually work, this currently does not
 	// inspect the aws sdk instead is a jumping off point
-	res, err := svc.List{{.ResourceTypeTitle}}s(ctx, &{{.Service}}.List{{.ResourceTypeTitle}}sInput{})
-	if err != nil {
-		return nil, err
+{{- if .Paginator}}
+	paginator := {{.Service}}.NewList{{.ResourceTypeTitle}}sPaginator(l.svc, &{{.Service}}.List{{.ResourceTypeTitle}}sInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		for i := range page.{{.ResourceTypeTitle}}s {
+			item := &page.{{.ResourceTypeTitle}}s[i]
+			{{- template "item" .}}
+		}
 	}
+{{- else}}
+	params := &{{.Service}}.List{{.ResourceTypeTitle}}sInput{}
+	for {
+		resp, err := l.svc.List{{.ResourceTypeTitle}}s(ctx, params)
+		if err != nil {
+			return nil, err
+		}
 
-	for _, p := range res.{{.ResourceTypeTitle}}sList {
-		resources = append(resources, &{{.Combined}}{
-			svc:  svc,
-			ID:   p.{{.ResourceTypeTitle}}Id,
-			Tags: p.Tags,
-		})
+		for i := range resp.{{.ResourceTypeTitle}}s {
+			item := &resp.{{.ResourceTypeTitle}}s[i]
+			{{- template "item" .}}
+		}
+
+		if resp.NextToken == nil {
+			break
+		}
+		params.NextToken = resp.NextToken
 	}
+{{- end}}
 
 	return resources, nil
 }
 
 type {{.Combined}} struct {
-	svc  *{{.Service}}.Client
-	ID   *string
-	Tags []*{{.Service}}.Tag
+	svc {{.Interface}}
+{{- range .Properties}}
+	{{.Name}} {{.Type}}
+{{- end}}
+{{- if .Tags}}
+	Tags   map[string]string
+{{- end}}
+}
+
+func (r *{{.Combined}}) Filter() error {
+	// NOTE: the statuses of a resource that is already being removed differ per resource type
+	if r.Status == "DELETING" {
+		return fmt.Errorf("already deleting")
+	}
+	return nil
 }
 
 func (r *{{.Combined}}) Remove(ctx context.Context) error {
 	_, err := r.svc.Delete{{.ResourceTypeTitle}}(ctx, &{{.Service}}.Delete{{.ResourceTypeTitle}}Input{
-		{{.ResourceTypeTitle}}Id: r.ID, 
+		{{.ResourceTypeTitle}}Id: r.ID,
 	})
 	return err
 }
@@ -81,15 +146,395 @@ func (r *{{.Combined}}) Properties() types.Properties {
 }
 
 func (r *{{.Combined}}) String() string {
-	return *r.ID
+	return *r.Name
+}
+
+{{- define "item"}}
+{{- if .Tags}}
+			var tags map[string]string
+			tagsResp, err := l.svc.ListTagsForResource(ctx, &{{.Service}}.ListTagsForResourceInput{
+				ResourceArn: item.{{.ResourceTypeTitle}}Arn,
+			})
+			if err == nil {
+				tags = tagsResp.Tags
+			}
+{{end}}
+			resources = append(resources, &{{.Combined}}{
+				svc: l.svc,
+{{- range .Properties}}
+				{{.Name}}: {{.Value}},
+{{- end}}
+{{- if .Tags}}
+				Tags: tags,
+{{- end}}
+			})
+{{- end}}
+`
+
+const mockTestTemplate = `package resources
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/gotidy/ptr"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go-v2/service/{{.Service}}"
+	{{.Service}}types "github.com/aws/aws-sdk-go-v2/service/{{.Service}}/types"
+)
+
+// mock{{.Combined}}Client is a hand-rolled mock of the {{.Interface}}, calling a method without setting its
+// function panics
+type mock{{.Combined}}Client struct {
+	{{.Interface}}
+
+	list{{.ResourceTypeTitle}}s func(*{{.Service}}.List{{.ResourceTypeTitle}}sInput) (*{{.Service}}.List{{.ResourceTypeTitle}}sOutput, error)
+	delete{{.ResourceTypeTitle}} func(*{{.Service}}.Delete{{.ResourceTypeTitle}}Input) (*{{.Service}}.Delete{{.ResourceTypeTitle}}Output, error)
+{{- if .Tags}}
+	listTagsForResource func(*{{.Service}}.ListTagsForResourceInput) (*{{.Service}}.ListTagsForResourceOutput, error)
+{{- end}}
+}
+
+func (m *mock{{.Combined}}Client) List{{.ResourceTypeTitle}}s(_ context.Context,
+	params *{{.Service}}.List{{.ResourceTypeTitle}}sInput, _ ...func(*{{.Service}}.Options)) (*{{.Service}}.List{{.ResourceTypeTitle}}sOutput, error) {
+	return m.list{{.ResourceTypeTitle}}s(params)
+}
+
+func (m *mock{{.Combined}}Client) Delete{{.ResourceTypeTitle}}(_ context.Context,
+	params *{{.Service}}.Delete{{.ResourceTypeTitle}}Input, _ ...func(*{{.Service}}.Options)) (*{{.Service}}.Delete{{.ResourceTypeTitle}}Output, error) {
+	return m.delete{{.ResourceTypeTitle}}(params)
+}
+{{- if .Tags}}
+
+func (m *mock{{.Combined}}Client) ListTagsForResource(_ context.Context,
+	params *{{.Service}}.ListTagsForResourceInput, _ ...func(*{{.Service}}.Options)) (*{{.Service}}.ListTagsForResourceOutput, error) {
+	return m.listTagsForResource(params)
+}
+{{- end}}
+
+func Test_Mock_{{.Combined}}_List(t *testing.T) {
+	cases := []struct {
+		name  string
+		pages [][]{{.Service}}types.{{.ResourceTypeTitle}}Summary
+		err   error
+		want  []string
+	}{
+		{
+			name: "single page",
+			pages: [][]{{.Service}}types.{{.ResourceTypeTitle}}Summary{
+				{
+					{
+						{{.ResourceTypeTitle}}Id:   ptr.String("id-1"),
+						{{.ResourceTypeTitle}}Name: ptr.String("name-1"),
+						{{.ResourceTypeTitle}}Arn:  ptr.String("arn:aws:{{.Service}}:us-east-1:123456789012:{{.ResourceType}}/id-1"),
+					},
+				},
+			},
+			want: []string{"id-1"},
+		},
+		{
+			name: "multiple pages",
+			pages: [][]{{.Service}}types.{{.ResourceTypeTitle}}Summary{
+				{
+					{
+						{{.ResourceTypeTitle}}Id:   ptr.String("id-1"),
+						{{.ResourceTypeTitle}}Name: ptr.String("name-1"),
+						{{.ResourceTypeTitle}}Arn:  ptr.String("arn:aws:{{.Service}}:us-east-1:123456789012:{{.ResourceType}}/id-1"),
+					},
+				},
+				{
+					{
+						{{.ResourceTypeTitle}}Id:   ptr.String("id-2"),
+						{{.ResourceTypeTitle}}Name: ptr.String("name-2"),
+						{{.ResourceTypeTitle}}Arn:  ptr.String("arn:aws:{{.Service}}:us-east-1:123456789012:{{.ResourceType}}/id-2"),
+					},
+				},
+			},
+			want: []string{"id-1", "id-2"},
+		},
+		{
+			name: "error",
+			err:  errors.New("access denied"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			mockSvc := &mock{{.Combined}}Client{
+				list{{.ResourceTypeTitle}}s: func(params *{{.Service}}.List{{.ResourceTypeTitle}}sInput) (*{{.Service}}.List{{.ResourceTypeTitle}}sOutput, error) {
+					if tc.err != nil {
+						return nil, tc.err
+					}
+
+					// Note: the next token is the index of the next page
+					page, _ := strconv.Atoi(ptr.ToString(params.NextToken))
+
+					output := &{{.Service}}.List{{.ResourceTypeTitle}}sOutput{
+						{{.ResourceTypeTitle}}s: tc.pages[page],
+					}
+					if page+1 < len(tc.pages) {
+						output.NextToken = ptr.String(strconv.Itoa(page + 1))
+					}
+
+					return output, nil
+				},
+{{- if .Tags}}
+				listTagsForResource: func(params *{{.Service}}.ListTagsForResourceInput) (*{{.Service}}.ListTagsForResourceOutput, error) {
+					return &{{.Service}}.ListTagsForResourceOutput{
+						Tags: map[string]string{"ARN": ptr.ToString(params.ResourceArn)},
+					}, nil
+				},
+{{- end}}
+			}
+
+			lister := &{{.Combined}}Lister{
+				svc: mockSvc,
+			}
+
+			resources, err := lister.List(context.TODO(), testListerOpts)
+			if tc.err != nil {
+				a.ErrorIs(err, tc.err)
+				return
+			}
+			a.NoError(err)
+
+			var ids []string
+			for _, r := range resources {
+				res := r.(*{{.Combined}})
+				ids = append(ids, *res.ID)
+{{- if .Tags}}
+				a.Equal(*res.ARN, res.Tags["ARN"])
+{{- end}}
+			}
+			a.Equal(tc.want, ids)
+		})
+	}
+}
+
+func Test_Mock_{{.Combined}}_Filter(t *testing.T) {
+	cases := []struct {
+		name     string
+		status   string
+		filtered bool
+	}{
+		{
+			name:     "available",
+			status:   "AVAILABLE",
+			filtered: false,
+		},
+		{
+			name:     "deleting",
+			status:   "DELETING",
+			filtered: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			resource := &{{.Combined}}{
+				ID:     ptr.String("id-1"),
+				Name:   ptr.String("name-1"),
+				Status: tc.status,
+			}
+
+			err := resource.Filter()
+			if tc.filtered {
+				a.Error(err)
+			} else {
+				a.NoError(err)
+			}
+		})
+	}
+}
+
+func Test_Mock_{{.Combined}}_Remove(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "success",
+		},
+		{
+			name: "error",
+			err:  errors.New("conflict"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			mockSvc := &mock{{.Combined}}Client{
+				delete{{.ResourceTypeTitle}}: func(params *{{.Service}}.Delete{{.ResourceTypeTitle}}Input) (*{{.Service}}.Delete{{.ResourceTypeTitle}}Output, error) {
+					a.Equal("id-1", ptr.ToString(params.{{.ResourceTypeTitle}}Id))
+					if tc.err != nil {
+						return nil, tc.err
+					}
+
+					return &{{.Service}}.Delete{{.ResourceTypeTitle}}Output{}, nil
+				},
+			}
+
+			resource := &{{.Combined}}{
+				svc:  mockSvc,
+				ID:   ptr.String("id-1"),
+				Name: ptr.String("name-1"),
+			}
+
+			err := resource.Remove(context.TODO())
+			a.Equal(tc.err, err)
+		})
+	}
+}
+
+func Test_Mock_{{.Combined}}_Properties(t *testing.T) {
+	cases := []struct {
+		name     string
+		resource *{{.Combined}}
+		want     map[string]string
+	}{
+		{
+			name: "all",
+			resource: &{{.Combined}}{
+				ID:     ptr.String("id-1"),
+				Name:   ptr.String("name-1"),
+				ARN:    ptr.String("arn:aws:{{.Service}}:us-east-1:123456789012:{{.ResourceType}}/id-1"),
+				Status: "AVAILABLE",
+{{- if .Tags}}
+				Tags: map[string]string{
+					"Environment": "test",
+				},
+{{- end}}
+			},
+			want: map[string]string{
+				"ID":     "id-1",
+				"Name":   "name-1",
+				"ARN":    "arn:aws:{{.Service}}:us-east-1:123456789012:{{.ResourceType}}/id-1",
+				"Status": "AVAILABLE",
+{{- if .Tags}}
+				"tag:Environment": "test",
+{{- end}}
+			},
+		},
+		{
+			name: "minimal",
+			resource: &{{.Combined}}{
+				ID:   ptr.String("id-1"),
+				Name: ptr.String("name-1"),
+			},
+			want: map[string]string{
+				"ID":   "id-1",
+				"Name": "name-1",
+				"ARN":  "",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := assert.New(t)
+
+			props := tc.resource.Properties()
+			for key, value := range tc.want {
+				a.Equal(value, props.Get(key), key)
+			}
+			a.Equal("name-1", tc.resource.String())
+		})
+	}
 }
 `
 
+const docsTemplate = "---\n" + `generated: true
+---
+
+# {{.Combined}}
+
+
+## Resource
+
+` + "```text\n{{.Combined}}\n```" + `
+
+## Properties
+
+{{range .SortedProperties}}
+- ` + "`{{.Name}}`" + `: No Description
+{{- end}}
+{{- if .Tags}}
+- ` + "`tag:<key>:`" + `: This resource has tags with property ` + "`Tags`" + `. These are key/value pairs that are
+	added as their own property with the prefix of ` + "`tag:`" + ` (e.g. [tag:example: "value"])
+{{- end}}
+
+!!! note - Using Properties
+    Properties are what [Filters](../config-filtering.md) are written against in your configuration. You use the property
+    names to write filters for what you want to **keep** and omit from the nuke process.
+
+### String Property
+
+The string representation of a resource is generally the value of the Name, ID or ARN field of the resource. Not all
+resources support properties. To write a filter against the string representation, simply omit the ` + "`property`" + ` field in
+the filter.
+
+The string value is always what is used in the output of the log format when a resource is identified.
+`
+
+// property is a field of the resource struct that is set from the listed item
+type property struct {
+	Name  string
+	Type  string
+	Value string
+}
+
+type templateData struct {
+	Service           string
+	ServiceTitle      string
+	ResourceType      string
+	ResourceTypeTitle string
+	Combined          string
+	Interface         string
+	Paginator         bool
+	Tags              bool
+	Properties        []property
+}
+
+// SortedProperties returns the properties sorted by name, which is the order of the properties in the docs
+func (d templateData) SortedProperties() []property {
+	sorted := slices.Clone(d.Properties)
+	slices.SortFunc(sorted, func(a, b property) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return sorted
+}
+
+// scaffoldFile is a file that is generated for the resource
+type scaffoldFile struct {
+	path     string
+	template string
+}
+
 func main() {
-	args := os.Args[1:]
+	write := flag.Bool("write", false,
+		"write the interface, resource, mock test and docs files instead of printing the resource to stdout")
+	paginator := flag.Bool("paginator", true, "list the resources with the paginator of the SDK")
+	tags := flag.Bool("tags", true, "add the tags of the resources with ListTagsForResource")
+	flag.Usage = func() {
+		fmt.Println("usage: create-resource [-write] [-paginator=false] [-tags=false] <service> <resource>")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	args := flag.Args()
 
 	if len(args) != 2 {
-		fmt.Println("usage: create-resource <service> <resource>")
+		flag.Usage()
 		os.Exit(1)
 	}
 
@@ -98,29 +543,92 @@ func main() {
 
 	caser := cases.Title(language.English)
 
-	data := struct {
-		Service           string
-		ServiceTitle      string
-		ResourceType      string
-		ResourceTypeTitle string
-		Combined          string
-	}{
+	data := templateData{
 		Service:           strings.ToLower(service),
 		ServiceTitle:      caser.String(service),
 		ResourceType:      resourceType,
 		ResourceTypeTitle: strcase.ToCamel(resourceType),
 		Combined:          fmt.Sprintf("%s%s", caser.String(service), strcase.ToCamel(resourceType)),
+		Interface:         fmt.Sprintf("%sAPI", caser.String(service)),
+		Paginator:         *paginator,
+		Tags:              *tags,
 	}
 
-	tmpl, err := template.New("resource").Parse(resourceTemplate)
-	if err != nil {
+	// Note: the struct, the listed item and the docs are all generated from the same properties
+	data.Properties = []property{
+		{Name: "ID", Type: "*string", Value: fmt.Sprintf("item.%sId", data.ResourceTypeTitle)},
+		{Name: "Name", Type: "*string", Value: fmt.Sprintf("item.%sName", data.ResourceTypeTitle)},
+		{Name: "ARN", Type: "*string", Value: fmt.Sprintf("item.%sArn", data.ResourceTypeTitle)},
+		{Name: "Status", Type: "string", Value: "string(item.Status)"},
+	}
+
+	if !*write {
+		out, err := render(resourceTemplate, data, true)
+		if err != nil {
+			panic(err)
+		}
+
+		fmt.Println(string(out))
+		return
+	}
+
+	name := fmt.Sprintf("%s-%s", data.Service, strcase.ToKebab(resourceType))
+	resourcePath := filepath.Join("resources", name+".go")
+
+	// Note: the other files are generated against the scaffolded resource, they would not compile against a resource
+	// that already exists
+	if _, err := os.Stat(resourcePath); err == nil {
+		fmt.Printf("%s already exists, nothing was written\n", resourcePath)
+		os.Exit(1)
+	} else if !errors.Is(err, os.ErrNotExist) {
 		panic(err)
 	}
 
+	files := []scaffoldFile{
+		{path: filepath.Join("resources", data.Service+"-api-interface.go"), template: interfaceTemplate},
+		{path: resourcePath, template: resourceTemplate},
+		{path: filepath.Join("resources", name+"_mock_test.go"), template: mockTestTemplate},
+		{path: filepath.Join("docs", "resources", strcase.ToKebab(data.Combined)+".md"), template: docsTemplate},
+	}
+
+	for _, file := range files {
+		// Note: the interface is shared by all resources of the service, the methods of the resource have to be
+		// added to an existing interface by hand
+		if _, err := os.Stat(file.path); err == nil {
+			fmt.Printf("Skipped %s, it already exists\n", file.path)
+			continue
+		} else if !errors.Is(err, os.ErrNotExist) {
+			panic(err)
+		}
+
+		out, err := render(file.template, data, filepath.Ext(file.path) == ".go")
+		if err != nil {
+			panic(err)
+		}
+
+		if err := os.WriteFile(file.path, out, 0644); err != nil { //nolint:gosec
+			panic(err)
+		}
+
+		fmt.Printf("Wrote %s\n", file.path)
+	}
+}
+
+// render executes the template with the data, go files are formatted
+func render(text string, data templateData, gofmt bool) ([]byte, error) {
+	tmpl, err := template.New("resource").Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
 	var tpl bytes.Buffer
 	if err := tmpl.Execute(&tpl, data); err != nil {
-		panic(err)
+		return nil, err
+	}
+
+	if !gofmt {
+		return tpl.Bytes(), nil
 	}
 
-	fmt.Println(tpl.String())
+	return format.Source(tpl.Bytes())
 }
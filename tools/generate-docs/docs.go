@@ -1,16 +1,13 @@
 package main
 
 import (
-	"bytes"
 	"context"
-	"embed"
 	"fmt"
 	"os"
 	"path/filepath"
 	"regexp"
 	"slices"
 	"strings"
-	"text/template"
 
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
@@ -18,33 +15,15 @@ import (
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
-	"github.com/ekristen/libnuke/pkg/docs"
 	"github.com/ekristen/libnuke/pkg/registry"
 
 	"github.com/ekristen/aws-nuke/v3/pkg/common"
+	"github.com/ekristen/aws-nuke/v3/pkg/docs"
 
 	_ "github.com/ekristen/aws-nuke/v3/resources"
 )
 
-//go:embed files/*
-var ResourceTemplates embed.FS
-
-var SkipCamelCase = []string{
-	"ELBv2",
-	"IoT",
-}
-
-type TemplateData struct {
-	Name                string
-	Description         string
-	Properties          map[string]string
-	Settings            []string
-	DependsOn           []string
-	DeprecatedAliases   []string
-	AlternativeResource string
-}
-
-func execute(_ context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
+func execute(_ context.Context, c *cli.Command) error { //nolint:funlen
 	var regs registry.Registrations
 
 	if c.String("resource") == "all" {
@@ -60,69 +39,33 @@ func execute(_ context.Context, c *cli.Command) error { //nolint:funlen,gocyclo
 			continue
 		}
 
-		name := reg.Name
-		description := ""
-		if strings.HasPrefix(name, "AWS::") {
-			description = `This is a resource that is access and controlled via the Cloud Control API, as such it's name
-and properties do not match the standard format for aws-nuke resources. Furthermore, the resource properties are
-dynamically populated and therefore cannot be documented here.`
-		}
-
-		data := TemplateData{
-			Name:                name,
-			Description:         description,
-			Properties:          docs.GeneratePropertiesMap(reg.Resource),
-			Settings:            reg.Settings,
-			DependsOn:           reg.DependsOn,
-			DeprecatedAliases:   reg.DeprecatedAliases,
-			AlternativeResource: reg.AlternativeResource,
-		}
-
-		rawTmpl, err := ResourceTemplates.ReadFile("files/resource.gomd")
+		buf, err := docs.RenderResource(reg)
 		if err != nil {
 			return err
 		}
 
-		funcMap := template.FuncMap{
-			"KebabCase":      KebabCase,
-			"SplitCamelCase": SplitCamelCase,
-			"ToLower":        toLower,
-		}
-
-		tmpl, err := template.New("example").Funcs(funcMap).Parse(string(rawTmpl))
-		if err != nil {
-			return err
-		}
-
-		var buf bytes.Buffer
+		if c.Bool("write-to-disk") {
+			filename := fmt.Sprintf("docs/resources/%s.md", docs.ResourceFilename(reg.Name))
 
-		err = tmpl.Execute(&buf, data)
-		if err != nil {
-			return err
-		}
+			// Note: the sections that are written by hand are kept, e.g. the descriptions of the settings
+			existing, err := os.ReadFile(filename)
+			if err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			if existing != nil {
+				buf = docs.MergeResource(buf, existing)
+			}
 
-		if c.Bool("write-to-disk") {
-			filename := KebabCase(strings.ToLower(SplitCamelCase(name)))
-			filename = strings.Replace(filename, "io-t", "iot-", 1)
-			filename = strings.Replace(filename, "iamsaml-", "iam-saml-", 1)
-			filename = strings.Replace(filename, "wa-fv2-", "wafv2-", 1)
-			filename = strings.Replace(filename, "f-sx", "fsx-", 1)
-			filename = strings.Replace(filename, "el-bv2-", "elbv2-", 1)
-			filename = strings.Replace(filename, "x-ray-", "xray-", 1)
-			filename = strings.Replace(filename, "x-ray-", "xray-", 1)
-
-			err := os.WriteFile(
-				fmt.Sprintf("docs/resources/%s.md", filename), buf.Bytes(), 0600)
-			if err != nil {
+			if err := os.WriteFile(filename, buf, 0600); err != nil {
 				return err
 			}
 
-			fmt.Printf("Wrote docs/resources/%s.md\n", filename)
+			fmt.Printf("Wrote %s\n", filename)
 
 			continue
 		}
 
-		fmt.Println(buf.String())
+		fmt.Println(string(buf))
 	}
 
 	mkdocs, err := os.ReadFile("mkdocs.yml")
@@ -224,52 +167,6 @@ func main() {
 	}
 }
 
-var (
-	spaces      = regexp.MustCompile(`\s+`)
-	nonAlphaNum = regexp.MustCompile(`[^\pL\pN]+`)
-)
-
-// KebabCase -
-func KebabCase(in string) string {
-	s := casePrepare(in)
-	return spaces.ReplaceAllString(s, "-")
-}
-
-func casePrepare(in string) string {
-	in = strings.TrimSpace(in)
-	s := strings.ToLower(in)
-	// make sure the first letter remains lower- or upper-cased
-	s = strings.Replace(s, string(s[0]), string(in[0]), 1)
-	s = nonAlphaNum.ReplaceAllString(s, " ")
-	return strings.TrimSpace(s)
-}
-
-func toLower(in string) string {
-	return strings.ToLower(in)
-}
-
-func SplitCamelCase(input string) string {
-	if slices.Contains(SkipCamelCase, input) {
-		return input
-	}
-
-	// Regular expression to find boundaries between lowercase and uppercase letters,
-	// and between sequences of uppercase letters followed by lowercase letters.
-	re := regexp.MustCompile(`([a-z])([A-Z0-9])|([A-Z]+)([A-Z][a-z])|(\d)([A-Z])`)
-	// Replace boundaries with a space followed by the uppercase letter.
-
-	boundaries := re.ReplaceAllString(input, "${1}${3}${5} ${2}${4}${6}")
-	boundaries = strings.Replace(boundaries, "io-t", "iot-", 1)
-	boundaries = strings.Replace(boundaries, "iamsaml-", "iam-saml-", 1)
-	boundaries = strings.Replace(boundaries, "wa-fv2-", "wafv2-", 1)
-	boundaries = strings.Replace(boundaries, "f-sx", "fsx-", 1)
-	boundaries = strings.Replace(boundaries, "el-bv2-", "elbv2-", 1)
-	boundaries = strings.Replace(boundaries, "x-ray-", "xray-", 1)
-	boundaries = strings.Replace(boundaries, "x-ray-", "xray-", 1)
-
-	return boundaries
-}
-
 // Function to update the 'Resources' section with new list values
 func updateResources(markdown string, newResources []string) string {
 	// Define the regex to match the 'Resources:' section and the following list
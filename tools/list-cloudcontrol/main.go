@@ -4,7 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-
+	"os"
+	"sort"
 	"strings"
 
 	"github.com/fatih/color"
@@ -16,15 +17,42 @@ import (
 	"github.com/aws/aws-sdk-go/service/cloudformation" //nolint:staticcheck
 
 	"github.com/ekristen/libnuke/pkg/registry"
+
+	"github.com/ekristen/aws-nuke/v3/resources"
 )
 
 type CFTypeSchema struct {
-	Handlers map[string]interface{} `json:"handlers"`
+	Handlers map[string]CFTypeHandler `json:"handlers"`
+}
+
+type CFTypeHandler struct {
+	HandlerSchema *struct {
+		Required []string `json:"required"`
+	} `json:"handlerSchema"`
 }
 
+// Catalog is the list of Cloud Control resource types that can be listed and deleted, either without a resource model
+// or by their parent, it is embedded into aws-nuke as resources/cloudcontrol-catalog.json for --cloud-control-all
+type Catalog struct {
+	Types []string `json:"types"`
+}
+
+// Usage: go run ./tools/list-cloudcontrol [catalog path]
+//
+// If the catalog path is given, the catalog of the resource types that can be listed and deleted is written to it,
+// e.g. resources/cloudcontrol-catalog.json
 func main() {
 	ctx := context.Background()
 
+	catalogPath := ""
+	if len(os.Args) > 1 {
+		catalogPath = os.Args[1]
+	}
+
+	catalog := &Catalog{
+		Types: make([]string, 0),
+	}
+
 	sess, err := session.NewSession(&aws.Config{
 		Region: aws.String(endpoints.UsEast1RegionID),
 	})
@@ -75,12 +103,29 @@ func main() {
 				continue
 			}
 
-			_, canList := schema.Handlers["list"]
+			listHandler, canList := schema.Handlers["list"]
 			if !canList {
 				color.New(color.FgHiBlack).Println("does not support list")
 				continue
 			}
 
+			_, canDelete := schema.Handlers["delete"]
+			if !canDelete {
+				color.New(color.FgHiBlack).Println("does not support delete")
+				continue
+			}
+
+			// Types that require a resource model to be listed are only supported if their parent is declared, see
+			// resources/cloudcontrol-parents.go
+			_, hasParent := resources.CloudControlParents[typeName]
+			if listHandler.HandlerSchema != nil && len(listHandler.HandlerSchema.Required) > 0 && !hasParent {
+				color.New(color.FgHiBlack).Printf("requires %s to list\n",
+					strings.Join(listHandler.HandlerSchema.Required, ", "))
+				continue
+			}
+
+			catalog.Types = append(catalog.Types, typeName)
+
 			resourceName, exists := mapping[typeName]
 			if exists && resourceName == typeName {
 				fmt.Print("is only covered by ")
@@ -100,4 +145,21 @@ func main() {
 	if err != nil {
 		logrus.Fatal(err)
 	}
+
+	if catalogPath == "" {
+		return
+	}
+
+	sort.Strings(catalog.Types)
+
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		logrus.Fatal(err)
+	}
+
+	if err := os.WriteFile(catalogPath, append(data, '\n'), 0644); err != nil { //nolint:gosec
+		logrus.Fatal(err)
+	}
+
+	logrus.Infof("wrote %d resource types to %s", len(catalog.Types), catalogPath)
 }
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -43,8 +44,13 @@ func main() {
 		panic("no arguments given")
 	}
 
+	if args[0] == "sdk-v2" {
+		os.Exit(sdkV2Command(args[1:]))
+	}
+
 	if len(args) != 2 {
 		fmt.Println("usage: migrate-resource <source-aws-nuke> <resource-type>")
+		fmt.Println("       migrate-resource sdk-v2 [-write] <file>...")
 		os.Exit(1)
 	}
 
@@ -124,3 +130,91 @@ func main() {
 		panic(err)
 	}
 }
+
+// sdkV2Command migrates the files from SDK v1 to SDK v2, the constructs that could not be converted are reported along
+// with the type errors of the migrated files
+func sdkV2Command(args []string) int {
+	flags := flag.NewFlagSet("sdk-v2", flag.ExitOnError)
+	write := flags.Bool("write", false, "write the migrated files instead of printing them to stdout")
+	modfile := flags.String("modfile", "", "the go.mod file to type-check the migrated files with, "+
+		"e.g. one that requires the services of SDK v2 that are not dependencies yet")
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		fmt.Println("usage: migrate-resource sdk-v2 [-write] [-modfile <file>] <file>...")
+		return 1
+	}
+
+	if *modfile != "" {
+		abs, err := filepath.Abs(*modfile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		*modfile = abs
+	}
+
+	checkers := make(map[string]*packageChecker)
+
+	total := 0
+	for _, filename := range flags.Args() {
+		src, err := os.ReadFile(filepath.Clean(filename))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		dir := filepath.Dir(filename)
+		if checkers[dir] == nil {
+			checkers[dir], err = newPackageChecker(dir, *modfile)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		}
+
+		out, findings, err := migrateFile(checkers[dir], filename, src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+
+		if *write {
+			if err := os.WriteFile(filename, out, 0644); err != nil { //nolint:gosec
+				fmt.Fprintln(os.Stderr, err)
+				return 1
+			}
+		} else {
+			fmt.Println(string(out))
+		}
+
+		for _, f := range findings {
+			fmt.Fprintln(os.Stderr, f)
+		}
+		total += len(findings)
+	}
+
+	fmt.Fprintf(os.Stderr, "%d constructs could not be converted\n", total)
+
+	return 0
+}
+
+// migrateFile migrates the file to SDK v2 and fixes the types that changed, the following files of the package are
+// checked against the migrated file
+func migrateFile(c *packageChecker, filename string, src []byte) ([]byte, []finding, error) {
+	out, findings, err := migrateSDKv2(filename, src)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out, typeFindings, err := checkTypes(c, filename, out)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := c.replace(filename, out); err != nil {
+		return nil, nil, err
+	}
+
+	return out, append(findings, typeFindings...), nil
+}
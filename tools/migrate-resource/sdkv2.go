@@ -0,0 +1,1235 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	sdkV1Prefix       = "github.com/aws/aws-sdk-go/"
+	sdkV1ServicePath  = "github.com/aws/aws-sdk-go/service/"
+	sdkV2ServicePath  = "github.com/aws/aws-sdk-go-v2/service/"
+	sdkV1AWSPath      = "github.com/aws/aws-sdk-go/aws"
+	sdkV2AWSPath      = "github.com/aws/aws-sdk-go-v2/aws"
+	sdkV1AWSErrPath   = "github.com/aws/aws-sdk-go/aws/awserr"
+	smithyPath        = "github.com/aws/smithy-go"
+	ptrPath           = "github.com/gotidy/ptr"
+	defaultContextArg = "ctx"
+)
+
+// ptrHelpers maps the pointer helpers of the aws package of SDK v1 to the helpers of the ptr package
+var ptrHelpers = map[string]string{
+	"Bool":         "Bool",
+	"BoolValue":    "ToBool",
+	"Float64":      "Float64",
+	"Float64Value": "ToFloat64",
+	"Int":          "Int",
+	"IntValue":     "ToInt",
+	"Int64":        "Int64",
+	"Int64Value":   "ToInt64",
+	"String":       "String",
+	"StringValue":  "ToString",
+	"Time":         "Time",
+	"TimeValue":    "ToTime",
+}
+
+// finding is a construct that could not be converted to SDK v2 and has to be migrated by hand
+type finding struct {
+	Pos     token.Position
+	Message string
+}
+
+func (f finding) String() string {
+	return fmt.Sprintf("%s: %s", f.Pos, f.Message)
+}
+
+// sdkV2Migration rewrites a single file from SDK v1 to SDK v2
+type sdkV2Migration struct {
+	fset *token.FileSet
+	file *ast.File
+
+	// services maps the local name of every imported service package to the name of the service
+	services map[string]string
+	// typesUsed are the services whose types package is used after the rewrite
+	typesUsed map[string]bool
+	// inputs maps the names of the operations whose input or output is referenced to their service, calls of these
+	// are API calls
+	inputs map[string]string
+	// operations are the operations that are called
+	operations map[string]bool
+	// interfaces maps the interfaces of the iface packages of SDK v1 that are used to their service, they are replaced
+	// by interfaces of the operations that are called
+	interfaces map[string]string
+	// ifaces maps the local name of every imported iface package to its service
+	ifaces map[string]string
+	// apiErrors are the names of the variables that hold an awserr.Error
+	apiErrors map[string]bool
+	// unconverted are the calls of *Pages methods that are already reported
+	unconverted map[*ast.CallExpr]bool
+
+	awsName    string
+	awserrName string
+	ptrName    string
+
+	imports  map[string]bool
+	findings []finding
+}
+
+// migrateSDKv2 rewrites the common patterns of SDK v1 in the source to SDK v2, it returns the new source along with
+// the constructs that could not be converted
+func migrateSDKv2(filename string, src []byte) ([]byte, []finding, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	m := &sdkV2Migration{
+		fset:        fset,
+		file:        file,
+		services:    make(map[string]string),
+		typesUsed:   make(map[string]bool),
+		inputs:      make(map[string]string),
+		operations:  make(map[string]bool),
+		interfaces:  make(map[string]string),
+		ifaces:      make(map[string]string),
+		apiErrors:   make(map[string]bool),
+		unconverted: make(map[*ast.CallExpr]bool),
+		ptrName:     "ptr",
+		imports:     make(map[string]bool),
+	}
+
+	m.collectImports()
+	if len(m.services) == 0 && len(m.ifaces) == 0 && m.awsName == "" && m.awserrName == "" {
+		return src, nil, nil
+	}
+
+	m.collectInputs()
+	m.collectAPIErrors()
+
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Body == nil {
+				continue
+			}
+
+			ctx := m.contextFor(d)
+			m.rewriteStmts(d.Body, ctx)
+			replaceExprs(d, func(e ast.Expr) ast.Expr {
+				return m.rewriteExpr(e, ctx)
+			})
+		case *ast.GenDecl:
+			if d.Tok == token.IMPORT {
+				continue
+			}
+
+			ctx := m.contextFor(nil)
+			replaceExprs(d, func(e ast.Expr) ast.Expr {
+				return m.rewriteExpr(e, ctx)
+			})
+		}
+	}
+
+	out, err := m.print()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sort.SliceStable(m.findings, func(i, j int) bool {
+		return m.findings[i].Pos.Offset < m.findings[j].Pos.Offset
+	})
+
+	return out, m.findings, nil
+}
+
+func (m *sdkV2Migration) report(node ast.Node, format string, args ...interface{}) {
+	m.findings = append(m.findings, finding{
+		Pos:     m.fset.Position(node.Pos()),
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+func (m *sdkV2Migration) collectImports() {
+	for _, spec := range m.file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		switch {
+		case path == ptrPath:
+			m.ptrName = name
+		case path == sdkV1AWSPath:
+			m.awsName = name
+		case path == sdkV1AWSErrPath:
+			m.awserrName = name
+		case strings.HasPrefix(path, sdkV1ServicePath):
+			service := strings.TrimPrefix(path, sdkV1ServicePath)
+			if parent, pkg, ok := strings.Cut(service, "/"); ok {
+				if pkg != parent+"iface" {
+					m.report(spec, "%s has no equivalent in SDK v2", path)
+					continue
+				}
+
+				// Note: the client of SDK v2 is not an interface, the mocks are generated for an interface of the
+				// operations instead, like RAMAPI
+				m.ifaces[name] = parent
+				m.report(spec, "%s is replaced by an interface of the operations that are called, "+
+					"the mocks have to be generated again", path)
+				continue
+			}
+
+			m.services[name] = service
+		case strings.HasPrefix(path, sdkV1Prefix):
+			m.report(spec, "%s has no equivalent in SDK v2", path)
+		}
+	}
+}
+
+// collectInputs collects the operations whose input or output is referenced, e.g. ListAnalyzers for ListAnalyzersInput
+func (m *sdkV2Migration) collectInputs() {
+	ast.Inspect(m.file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || !m.isService(sel.X) {
+			return true
+		}
+
+		// Note: the output is referenced by the callbacks of the *Pages methods, even if the input is nil
+		for _, suffix := range []string{"Input", "Output"} {
+			if operation, ok := strings.CutSuffix(sel.Sel.Name, suffix); ok {
+				m.inputs[operation] = sel.X.(*ast.Ident).Name
+			}
+		}
+
+		return true
+	})
+}
+
+// collectAPIErrors collects the names of the variables that hold an awserr.Error, so their methods can be renamed
+func (m *sdkV2Migration) collectAPIErrors() {
+	ast.Inspect(m.file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.ValueSpec:
+			if m.isAWSErrType(n.Type) {
+				for _, name := range n.Names {
+					m.apiErrors[name.Name] = true
+				}
+			}
+		case *ast.AssignStmt:
+			if len(n.Rhs) == 1 && len(n.Lhs) > 0 {
+				if assert, ok := n.Rhs[0].(*ast.TypeAssertExpr); ok && m.isAWSErrType(assert.Type) {
+					if name, ok := n.Lhs[0].(*ast.Ident); ok {
+						m.apiErrors[name.Name] = true
+					}
+				}
+			}
+		case *ast.TypeSwitchStmt:
+			assign, ok := n.Assign.(*ast.AssignStmt)
+			if !ok || len(assign.Lhs) != 1 {
+				return true
+			}
+
+			for _, stmt := range n.Body.List {
+				for _, typ := range stmt.(*ast.CaseClause).List {
+					if m.isAWSErrType(typ) {
+						m.apiErrors[assign.Lhs[0].(*ast.Ident).Name] = true
+					}
+				}
+			}
+		}
+
+		return true
+	})
+}
+
+// contextFor returns a function that returns the context for the API calls in the function, the context parameter of
+// the function is named if it is unnamed, without a context parameter context.TODO() is used
+func (m *sdkV2Migration) contextFor(fn *ast.FuncDecl) func() ast.Expr {
+	var ctx ast.Expr
+
+	return func() ast.Expr {
+		if ctx != nil {
+			return ctx
+		}
+
+		if fn != nil {
+			for _, field := range fn.Type.Params.List {
+				if !isSelector(field.Type, "context", "Context") {
+					continue
+				}
+
+				if len(field.Names) == 0 {
+					field.Names = []*ast.Ident{ast.NewIdent(defaultContextArg)}
+				} else if field.Names[0].Name == "_" {
+					field.Names[0].Name = defaultContextArg
+				}
+
+				ctx = ast.NewIdent(field.Names[0].Name)
+				return ctx
+			}
+
+			m.report(fn, "%s has no context parameter, context.TODO() is used for the API calls", fn.Name.Name)
+		}
+
+		m.imports["context"] = true
+		ctx = &ast.CallExpr{
+			Fun: &ast.SelectorExpr{X: ast.NewIdent("context"), Sel: ast.NewIdent("TODO")},
+		}
+
+		return ctx
+	}
+}
+
+// rewriteStmts rewrites the statements of all blocks below the node that have to be replaced by multiple statements
+func (m *sdkV2Migration) rewriteStmts(node ast.Node, ctx func() ast.Expr) {
+	paginators := 0
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BlockStmt:
+			n.List = m.rewriteStmtList(n.List, ctx, &paginators)
+		case *ast.CaseClause:
+			n.Body = m.rewriteStmtList(n.Body, ctx, &paginators)
+		case *ast.CommClause:
+			n.Body = m.rewriteStmtList(n.Body, ctx, &paginators)
+		}
+
+		return true
+	})
+}
+
+func (m *sdkV2Migration) rewriteStmtList(list []ast.Stmt, ctx func() ast.Expr, paginators *int) []ast.Stmt {
+	result := make([]ast.Stmt, 0, len(list))
+
+	for i := 0; i < len(list); i++ {
+		stmt := list[i]
+
+		// Note: the error of a paginated call is either checked in the if statement of the call or in the next one
+		var call *ast.CallExpr
+		var errName *ast.Ident
+		var errCheck *ast.IfStmt
+		var consumed int
+
+		switch s := stmt.(type) {
+		case *ast.IfStmt:
+			if assign, ok := s.Init.(*ast.AssignStmt); ok && s.Else == nil {
+				call, errName = m.pagesAssign(assign)
+				errCheck = s
+			}
+
+			if replacement := m.rewriteAssertIf(s); replacement != nil {
+				result = append(result, replacement...)
+				continue
+			}
+		case *ast.AssignStmt:
+			call, errName = m.pagesAssign(s)
+			if call != nil && i+1 < len(list) {
+				if next, ok := list[i+1].(*ast.IfStmt); ok && next.Init == nil && next.Else == nil {
+					errCheck = next
+					consumed = 1
+				}
+			}
+		case *ast.ExprStmt:
+			if c, ok := s.X.(*ast.CallExpr); ok && m.isPagesCall(c) {
+				m.unconverted[c] = true
+				m.report(s, "the error of %s is not checked, it has to be converted to a paginator by hand",
+					c.Fun.(*ast.SelectorExpr).Sel.Name)
+			}
+		}
+
+		if call == nil || errCheck == nil || !isErrCheck(errCheck.Cond, errName.Name) {
+			if call != nil {
+				m.unconverted[call] = true
+				m.report(call, "%s is not followed by an error check, it has to be converted to a paginator by hand",
+					call.Fun.(*ast.SelectorExpr).Sel.Name)
+			}
+
+			result = append(result, stmt)
+			continue
+		}
+
+		replacement := m.rewritePages(stmt.Pos(), call, errName, errCheck.Body, ctx, *paginators)
+		if replacement == nil {
+			m.unconverted[call] = true
+			result = append(result, stmt)
+			continue
+		}
+
+		*paginators++
+		m.operations[strings.TrimSuffix(strings.TrimSuffix(call.Fun.(*ast.SelectorExpr).Sel.Name, "WithContext"),
+			"Pages")] = true
+		result = append(result, replacement...)
+		i += consumed
+	}
+
+	return result
+}
+
+// pagesAssign returns the call of a *Pages method and the error it is assigned to
+func (m *sdkV2Migration) pagesAssign(assign *ast.AssignStmt) (*ast.CallExpr, *ast.Ident) {
+	if len(assign.Lhs) != 1 || len(assign.Rhs) != 1 {
+		return nil, nil
+	}
+
+	name, ok := assign.Lhs[0].(*ast.Ident)
+	if !ok {
+		return nil, nil
+	}
+
+	call, ok := assign.Rhs[0].(*ast.CallExpr)
+	if !ok || !m.isPagesCall(call) {
+		return nil, nil
+	}
+
+	return call, name
+}
+
+// isPagesCall returns true for the calls of the *Pages and *PagesWithContext methods of the operations
+func (m *sdkV2Migration) isPagesCall(call *ast.CallExpr) bool {
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+
+	operation := strings.TrimSuffix(sel.Sel.Name, "WithContext")
+	operation, ok = strings.CutSuffix(operation, "Pages")
+
+	return ok && m.inputs[operation] != ""
+}
+
+// isErrCheck returns true for the condition `err != nil`
+func isErrCheck(cond ast.Expr, name string) bool {
+	binary, ok := cond.(*ast.BinaryExpr)
+	if !ok || binary.Op != token.NEQ {
+		return false
+	}
+
+	return isIdent(binary.X, name) && isIdent(binary.Y, "nil")
+}
+
+// rewritePages replaces a call of a *Pages method with a loop over the paginator of the operation:
+//
+//	paginator := service.NewOperationPaginator(svc, params)
+//	for paginator.HasMorePages() {
+//		page, err := paginator.NextPage(ctx)
+//		if err != nil {
+//			...
+//		}
+//		...
+//	}
+func (m *sdkV2Migration) rewritePages(pos token.Pos, call *ast.CallExpr, errName *ast.Ident,
+	errBody *ast.BlockStmt, ctx func() ast.Expr, index int) []ast.Stmt {
+	sel := call.Fun.(*ast.SelectorExpr)
+	operation := strings.TrimSuffix(strings.TrimSuffix(sel.Sel.Name, "WithContext"), "Pages")
+
+	args := call.Args
+	callCtx := ctx
+	if strings.HasSuffix(sel.Sel.Name, "WithContext") {
+		if len(args) > 3 {
+			m.report(call, "the request options of %s are not converted", sel.Sel.Name)
+		}
+		if len(args) < 3 {
+			m.report(call, "the arguments of %s are not converted, it has to be converted by hand", sel.Sel.Name)
+			return nil
+		}
+
+		ctxArg := args[0]
+		callCtx = func() ast.Expr { return ctxArg }
+		args = args[1:3]
+	}
+
+	if len(args) != 2 {
+		m.report(call, "the arguments of %s are not converted, it has to be converted by hand", sel.Sel.Name)
+		return nil
+	}
+
+	fn, ok := args[1].(*ast.FuncLit)
+	if !ok || len(fn.Type.Params.List) != 2 {
+		m.report(call, "the callback of %s is not a function literal, it has to be converted by hand", sel.Sel.Name)
+		return nil
+	}
+
+	pageField, lastPageField := fn.Type.Params.List[0], fn.Type.Params.List[1]
+	if len(pageField.Names) != 1 || len(lastPageField.Names) != 1 {
+		m.report(call, "the arguments of %s are not converted, it has to be converted by hand", sel.Sel.Name)
+		return nil
+	}
+
+	service := ""
+	if star, ok := pageField.Type.(*ast.StarExpr); ok {
+		if output, ok := star.X.(*ast.SelectorExpr); ok && m.isService(output.X) {
+			service = output.X.(*ast.Ident).Name
+		}
+	}
+	if service == "" {
+		m.report(call, "the service of %s is unknown, it has to be converted by hand", sel.Sel.Name)
+		return nil
+	}
+
+	body := fn.Body.List
+	if n := len(body); n > 0 && isContinue(body[n-1], lastPageField.Names[0].Name) {
+		body = body[:n-1]
+	}
+
+	returns := false
+	ast.Inspect(&ast.BlockStmt{List: body}, func(n ast.Node) bool {
+		switch n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			returns = true
+		}
+		return true
+	})
+	if returns {
+		m.report(call, "the callback of %s stops the pagination, it has to be converted by hand", sel.Sel.Name)
+		return nil
+	}
+
+	paginator := "paginator"
+	if index > 0 {
+		paginator = strings.ToLower(operation[:1]) + operation[1:] + "Paginator"
+	}
+
+	// Note: the last page of the callback is the last page of the paginator
+	if lastPage := lastPageField.Names[0].Name; lastPage != "_" {
+		replaceExprs(&ast.BlockStmt{List: body}, func(e ast.Expr) ast.Expr {
+			if isIdent(e, lastPage) {
+				return &ast.UnaryExpr{Op: token.NOT, X: &ast.CallExpr{
+					Fun: &ast.SelectorExpr{X: ast.NewIdent(paginator), Sel: ast.NewIdent("HasMorePages")},
+				}}
+			}
+			return e
+		})
+	}
+
+	// Note: the error check may follow the callback, its positions are cleared, so the comments of the callback are
+	// not printed in it
+	if errBody.Pos() > fn.Pos() {
+		clearPositions(errBody)
+	}
+
+	// Note: the new statements are placed at the replaced statement, so its comments are printed before them
+	end := fn.Body.Rbrace
+	if len(body) > 0 {
+		end = body[len(body)-1].End()
+	}
+
+	loop := &ast.BlockStmt{
+		Lbrace: pos,
+		List: append([]ast.Stmt{
+			&ast.AssignStmt{
+				Lhs: []ast.Expr{ast.NewIdent(pageField.Names[0].Name), ast.NewIdent(errName.Name)},
+				Tok: token.DEFINE,
+				Rhs: []ast.Expr{&ast.CallExpr{
+					Fun:  &ast.SelectorExpr{X: ast.NewIdent(paginator), Sel: ast.NewIdent("NextPage")},
+					Args: []ast.Expr{callCtx()},
+				}},
+			},
+			&ast.IfStmt{
+				If:   pos,
+				Cond: &ast.BinaryExpr{X: ast.NewIdent(errName.Name), Op: token.NEQ, Y: ast.NewIdent("nil")},
+				Body: errBody,
+			},
+		}, body...),
+		Rbrace: end,
+	}
+
+	return []ast.Stmt{
+		&ast.AssignStmt{
+			Lhs:    []ast.Expr{&ast.Ident{NamePos: pos, Name: paginator}},
+			TokPos: pos,
+			Tok:    token.DEFINE,
+			Rhs: []ast.Expr{&ast.CallExpr{
+				Fun: &ast.SelectorExpr{
+					X:   ast.NewIdent(service),
+					Sel: ast.NewIdent("New" + operation + "Paginator"),
+				},
+				Args: []ast.Expr{sel.X, args[0]},
+			}},
+		},
+		&ast.ForStmt{
+			For: pos,
+			Cond: &ast.CallExpr{
+				Fun: &ast.SelectorExpr{X: ast.NewIdent(paginator), Sel: ast.NewIdent("HasMorePages")},
+			},
+			Body: loop,
+		},
+	}
+}
+
+// isContinue returns true for the statements that continue the pagination at the end of the callback, which are
+// `return true` and `return !lastPage`
+func isContinue(stmt ast.Stmt, lastPage string) bool {
+	ret, ok := stmt.(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return false
+	}
+
+	if isIdent(ret.Results[0], "true") {
+		return true
+	}
+
+	not, ok := ret.Results[0].(*ast.UnaryExpr)
+	return ok && not.Op == token.NOT && isIdent(not.X, lastPage)
+}
+
+// rewriteAssertIf replaces the type assertion of an awserr.Error in an if statement with errors.As:
+//
+//	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == "..." {
+//
+// becomes
+//
+//	var awsErr smithy.APIError
+//	if errors.As(err, &awsErr) && awsErr.ErrorCode() == "..." {
+func (m *sdkV2Migration) rewriteAssertIf(s *ast.IfStmt) []ast.Stmt {
+	assign, ok := s.Init.(*ast.AssignStmt)
+	if !ok || assign.Tok != token.DEFINE || len(assign.Lhs) != 2 || len(assign.Rhs) != 1 {
+		return nil
+	}
+
+	assert, ok := assign.Rhs[0].(*ast.TypeAssertExpr)
+	if !ok || !m.isAWSErrType(assert.Type) {
+		return nil
+	}
+
+	name, okName := assign.Lhs[0].(*ast.Ident)
+	if !okName || name.Name == "_" {
+		return nil
+	}
+
+	var rest ast.Expr
+	switch cond := s.Cond.(type) {
+	case *ast.Ident:
+		if cond.Name != assign.Lhs[1].(*ast.Ident).Name {
+			return nil
+		}
+	case *ast.BinaryExpr:
+		if cond.Op != token.LAND || !isIdent(cond.X, assign.Lhs[1].(*ast.Ident).Name) {
+			return nil
+		}
+		rest = cond.Y
+	default:
+		return nil
+	}
+
+	m.imports["errors"] = true
+	m.imports[smithyPath] = true
+
+	var cond ast.Expr = &ast.CallExpr{
+		Fun: &ast.SelectorExpr{X: ast.NewIdent("errors"), Sel: ast.NewIdent("As")},
+		Args: []ast.Expr{assert.X, &ast.UnaryExpr{
+			Op: token.AND,
+			X:  ast.NewIdent(name.Name),
+		}},
+	}
+	if rest != nil {
+		cond = &ast.BinaryExpr{X: cond, Op: token.LAND, Y: rest}
+	}
+
+	s.Init = nil
+	s.Cond = cond
+
+	return []ast.Stmt{
+		&ast.DeclStmt{Decl: &ast.GenDecl{
+			TokPos: s.If,
+			Tok:    token.VAR,
+			Specs: []ast.Spec{&ast.ValueSpec{
+				Names: []*ast.Ident{ast.NewIdent(name.Name)},
+				Type:  &ast.SelectorExpr{X: ast.NewIdent("smithy"), Sel: ast.NewIdent("APIError")},
+			}},
+		}},
+		s,
+	}
+}
+
+// rewriteExpr rewrites a single expression, the expressions below it are already rewritten
+func (m *sdkV2Migration) rewriteExpr(e ast.Expr, ctx func() ast.Expr) ast.Expr {
+	switch e := e.(type) {
+	case *ast.CallExpr:
+		return m.rewriteCall(e, ctx)
+	case *ast.SelectorExpr:
+		return m.rewriteSelector(e)
+	case *ast.TypeAssertExpr:
+		if isSelector(e.Type, "smithy", "APIError") {
+			m.report(e, "type assertion of an awserr.Error, use errors.As to match wrapped errors")
+		}
+	case *ast.ArrayType:
+		e.Elt = m.dereferenceType(e.Elt)
+	case *ast.MapType:
+		e.Value = m.dereferenceType(e.Value)
+	}
+
+	return e
+}
+
+// dereferenceType returns the type of the shapes of SDK v2, which are used as values in slices and maps instead of
+// pointers, e.g. []*ec2.Tag becomes []ec2types.Tag
+func (m *sdkV2Migration) dereferenceType(e ast.Expr) ast.Expr {
+	star, ok := e.(*ast.StarExpr)
+	if !ok {
+		return e
+	}
+
+	if sel, ok := star.X.(*ast.SelectorExpr); ok && m.isTypes(sel.X) {
+		return sel
+	}
+
+	return e
+}
+
+func (m *sdkV2Migration) rewriteSelector(sel *ast.SelectorExpr) ast.Expr {
+	name := sel.Sel.Name
+
+	switch {
+	case m.isService(sel.X):
+		service := sel.X.(*ast.Ident).Name
+
+		switch {
+		case name == "New", strings.HasPrefix(name, "New"),
+			strings.HasSuffix(name, "Input"), strings.HasSuffix(name, "Output"):
+			return sel
+		case strings.HasPrefix(name, "ErrCode"):
+			// Note: the error codes of SDK v1 are named after the code, e.g. ErrCodeNotFoundException = "NotFoundException"
+			return &ast.BasicLit{
+				ValuePos: sel.Pos(),
+				Kind:     token.STRING,
+				Value:    strconv.Quote(strings.TrimPrefix(name, "ErrCode")),
+			}
+		case strings.EqualFold(name, m.services[service]):
+			sel.Sel.Name = "Client"
+			return sel
+		case name == "ServiceName", name == "EndpointsID", name == "ServiceID":
+			m.report(sel, "%s.%s has no equivalent in SDK v2", service, name)
+			return sel
+		case strings.HasSuffix(name, "_Values"):
+			m.report(sel, "%s.%s is a method of the enum type in SDK v2, e.g. %stypes.%s(\"\").Values()",
+				service, name, service, strings.TrimSuffix(name, "_Values"))
+			return sel
+		}
+
+		m.typesUsed[service] = true
+		return &ast.SelectorExpr{X: ast.NewIdent(service + "types"), Sel: sel.Sel}
+	case m.isIface(sel.X):
+		m.interfaces[sel.Sel.Name] = m.ifaces[sel.X.(*ast.Ident).Name]
+		return &ast.Ident{NamePos: sel.Pos(), Name: sel.Sel.Name}
+	case m.awserrName != "" && isIdent(sel.X, m.awserrName):
+		if name == "Error" {
+			m.imports[smithyPath] = true
+			return &ast.SelectorExpr{X: ast.NewIdent("smithy"), Sel: ast.NewIdent("APIError")}
+		}
+
+		m.report(sel, "awserr.%s has no equivalent in SDK v2, use the error types of the service instead", name)
+	}
+
+	return sel
+}
+
+func (m *sdkV2Migration) rewriteCall(call *ast.CallExpr, ctx func() ast.Expr) ast.Expr { //nolint:gocyclo
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return call
+	}
+
+	name := sel.Sel.Name
+
+	switch {
+	case m.isService(sel.X):
+		if name == "NewFromConfig" {
+			return call
+		}
+		if name != "New" {
+			return call
+		}
+
+		sel.Sel.Name = "NewFromConfig"
+		if len(call.Args) > 1 {
+			m.report(call, "the client configuration is not converted, use the options of NewFromConfig instead")
+			call.Args = call.Args[:1]
+		}
+
+		if len(call.Args) == 1 {
+			if session, ok := call.Args[0].(*ast.SelectorExpr); ok && session.Sel.Name == "Session" {
+				call.Args[0] = &ast.StarExpr{X: &ast.SelectorExpr{X: session.X, Sel: ast.NewIdent("Config")}}
+				return call
+			}
+		}
+
+		m.report(call, "the client is not created from the session of the lister options, pass *opts.Config instead")
+		return call
+	case m.awsName != "" && isIdent(sel.X, m.awsName):
+		if name == "String" && len(call.Args) == 1 {
+			// Note: the enums of SDK v2 are types of their own instead of strings
+			if arg, ok := call.Args[0].(*ast.SelectorExpr); ok && m.isTypes(arg.X) {
+				return arg
+			}
+		}
+
+		helper, ok := ptrHelpers[name]
+		if !ok {
+			m.report(call, "aws.%s is not converted", name)
+			return call
+		}
+
+		m.imports[ptrPath] = true
+		return &ast.CallExpr{
+			Fun:      &ast.SelectorExpr{X: ast.NewIdent(m.ptrName), Sel: ast.NewIdent(helper)},
+			Lparen:   call.Lparen,
+			Args:     call.Args,
+			Ellipsis: call.Ellipsis,
+			Rparen:   call.Rparen,
+		}
+	}
+
+	if ident, ok := sel.X.(*ast.Ident); ok && m.apiErrors[ident.Name] {
+		switch name {
+		case "Code":
+			sel.Sel.Name = "ErrorCode"
+		case "Message":
+			sel.Sel.Name = "ErrorMessage"
+		case "OrigErr":
+			m.report(call, "OrigErr has no equivalent in SDK v2, use errors.Unwrap instead")
+		}
+
+		return call
+	}
+
+	switch {
+	case m.isPagesCall(call) && !m.unconverted[call]:
+		m.report(call, "%s is not converted to a paginator", name)
+	case strings.HasPrefix(name, "WaitUntil"):
+		m.report(call, "the waiters of SDK v2 are types of their own, e.g. New%sWaiter",
+			strings.TrimPrefix(name, "WaitUntil"))
+	case strings.HasSuffix(name, "WithContext") && m.inputs[strings.TrimSuffix(name, "WithContext")] != "":
+		sel.Sel.Name = strings.TrimSuffix(name, "WithContext")
+		m.operations[sel.Sel.Name] = true
+		if len(call.Args) > 2 {
+			m.report(call, "the request options of %s are not converted", name)
+			call.Args = call.Args[:2]
+		}
+	case strings.HasSuffix(name, "Request") && m.inputs[strings.TrimSuffix(name, "Request")] != "":
+		m.report(call, "%s has no equivalent in SDK v2, call %s instead", name, strings.TrimSuffix(name, "Request"))
+	case m.inputs[name] != "" && len(call.Args) == 1 && isExpect(sel.X):
+		// Note: the expectations of the mocks match any context
+		call.Args = append([]ast.Expr{&ast.CallExpr{
+			Fun: &ast.SelectorExpr{X: ast.NewIdent("gomock"), Sel: ast.NewIdent("Any")},
+		}}, call.Args...)
+	case m.inputs[name] != "" && len(call.Args) == 1:
+		m.operations[name] = true
+		call.Args = append([]ast.Expr{ctx()}, call.Args...)
+	}
+
+	if isSelector(call.Fun, "opts", "Session") {
+		m.report(call, "the session of the lister options is SDK v1, use opts.Config instead")
+	}
+
+	return call
+}
+
+func (m *sdkV2Migration) isService(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	_, ok = m.services[ident.Name]
+	return ok
+}
+
+func (m *sdkV2Migration) isIface(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	_, ok = m.ifaces[ident.Name]
+	return ok
+}
+
+func (m *sdkV2Migration) isTypes(e ast.Expr) bool {
+	ident, ok := e.(*ast.Ident)
+	if !ok {
+		return false
+	}
+
+	service, ok := strings.CutSuffix(ident.Name, "types")
+	return ok && m.typesUsed[service]
+}
+
+func (m *sdkV2Migration) isAWSErrType(e ast.Expr) bool {
+	return m.awserrName != "" && isSelector(e, m.awserrName, "Error")
+}
+
+// importSpec is an import of the file that is printed
+type importSpec struct {
+	name    string
+	path    string
+	comment string
+}
+
+// print prints the file with a new import block, the imports are grouped like the rest of the resources
+func (m *sdkV2Migration) print() ([]byte, error) {
+	var specs []importSpec
+	for _, spec := range fileImports(m.file) {
+		switch {
+		case spec.path == sdkV1AWSErrPath:
+			// Note: the constructs of awserr that are left are reported, they have to be converted by hand
+			if !m.usesIdent(m.awserrName) {
+				continue
+			}
+		case spec.path == sdkV1AWSPath:
+			if !m.usesIdent(m.awsName) {
+				continue
+			}
+
+			spec.path, spec.comment = sdkV2AWSPath, ""
+		case strings.HasPrefix(spec.path, sdkV1ServicePath) && m.ifaces[localName(spec.name, spec.path)] != "":
+			continue
+		case strings.HasPrefix(spec.path, sdkV1ServicePath) && m.services[localName(spec.name, spec.path)] != "":
+			local := localName(spec.name, spec.path)
+			service := m.services[local]
+			specs = append(specs, importSpec{name: spec.name, path: sdkV2ServicePath + service})
+
+			if m.typesUsed[local] {
+				specs = append(specs, importSpec{name: local + "types", path: sdkV2ServicePath + service + "/types"})
+			}
+
+			continue
+		}
+
+		specs = append(specs, spec)
+	}
+
+	for path := range m.imports {
+		specs = append(specs, importSpec{path: path})
+	}
+
+	interfaces, interfaceSpecs := m.interfaceDecls()
+	specs = append(specs, interfaceSpecs...)
+
+	return printFile(m.fset, m.file, specs, interfaces)
+}
+
+// interfaceDecls returns the declarations of the interfaces that replace the interfaces of the iface packages, they
+// have the operations of the service that are called in the file
+func (m *sdkV2Migration) interfaceDecls() (string, []importSpec) {
+	names := make([]string, 0, len(m.interfaces))
+	for name := range m.interfaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var specs []importSpec
+	var b strings.Builder
+	for _, name := range names {
+		service := m.interfaces[name]
+
+		local := ""
+		for l, s := range m.services {
+			if s == service {
+				local = l
+			}
+		}
+		if local == "" {
+			local = service
+			specs = append(specs, importSpec{path: sdkV2ServicePath + service})
+		}
+
+		var operations []string
+		for operation := range m.operations {
+			if m.services[m.inputs[operation]] == service {
+				operations = append(operations, operation)
+			}
+		}
+		sort.Strings(operations)
+
+		fmt.Fprintf(&b, "\n// %s is the interface of the operations of the client that are used, "+
+			"so the client can be mocked\n", name)
+		fmt.Fprintf(&b, "type %s interface {\n", name)
+		for _, operation := range operations {
+			fmt.Fprintf(&b, "\t%s(ctx context.Context, params *%s.%sInput,\n", operation, local, operation)
+			fmt.Fprintf(&b, "\t\toptFns ...func(*%s.Options)) (*%s.%sOutput, error)\n", local, local, operation)
+		}
+		b.WriteString("}\n")
+	}
+
+	if len(names) > 0 {
+		specs = append(specs, importSpec{path: "context"})
+	}
+
+	return b.String(), specs
+}
+
+// fileImports returns the imports of the file
+func fileImports(file *ast.File) []importSpec {
+	var specs []importSpec
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+
+		name := ""
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+
+		comment := ""
+		if spec.Comment != nil {
+			comment = "//" + strings.TrimSpace(spec.Comment.Text())
+		}
+
+		specs = append(specs, importSpec{name: name, path: path, comment: comment})
+	}
+
+	return specs
+}
+
+// printFile prints the file with the imports in a single import block, the imports are grouped like the rest of the
+// resources, the declarations are appended to the file
+func printFile(fset *token.FileSet, file *ast.File, specs []importSpec, decls string) ([]byte, error) {
+	var others []ast.Decl
+	var start, end token.Pos
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.IMPORT {
+			others = append(others, decl)
+			continue
+		}
+
+		if !start.IsValid() {
+			start = gen.Pos()
+		}
+		end = gen.End()
+	}
+
+	// Note: the comments of the old imports are printed with the new import block
+	var comments []*ast.CommentGroup
+	for _, group := range file.Comments {
+		if group.Pos() < start || group.End() > end {
+			comments = append(comments, group)
+		}
+	}
+
+	file.Decls = others
+	file.Comments = comments
+	file.Imports = nil
+
+	var body bytes.Buffer
+	if err := printer.Fprint(&body, fset, file); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	groups := make([][]importSpec, 5)
+	for _, spec := range specs {
+		if seen[spec.path] {
+			continue
+		}
+		seen[spec.path] = true
+
+		group := 1
+		switch {
+		case !strings.Contains(strings.Split(spec.path, "/")[0], "."):
+			group = 0
+		case strings.HasPrefix(spec.path, "github.com/aws/"):
+			group = 2
+		case strings.HasPrefix(spec.path, "github.com/ekristen/libnuke"):
+			group = 3
+		case strings.HasPrefix(spec.path, "github.com/ekristen/aws-nuke"):
+			group = 4
+		}
+
+		groups[group] = append(groups[group], spec)
+	}
+
+	var imports bytes.Buffer
+	imports.WriteString("\nimport (\n")
+	first := true
+	for _, group := range groups {
+		if len(group) == 0 {
+			continue
+		}
+
+		if !first {
+			imports.WriteString("\n")
+		}
+		first = false
+
+		sort.SliceStable(group, func(i, j int) bool {
+			return group[i].path < group[j].path
+		})
+
+		for _, spec := range group {
+			imports.WriteString("\t")
+			if spec.name != "" {
+				imports.WriteString(spec.name + " ")
+			}
+			imports.WriteString(strconv.Quote(spec.path))
+			if spec.comment != "" {
+				imports.WriteString(" " + spec.comment)
+			}
+			imports.WriteString("\n")
+		}
+	}
+	imports.WriteString(")\n")
+
+	// Note: the imports follow the package clause, which is the first line starting with package
+	src := body.Bytes()
+	pkg := 0
+	for !bytes.HasPrefix(src[pkg:], []byte("package ")) {
+		pkg += bytes.IndexByte(src[pkg:], '\n') + 1
+	}
+	pkg += bytes.IndexByte(src[pkg:], '\n') + 1
+
+	var out bytes.Buffer
+	out.Write(src[:pkg])
+	out.Write(imports.Bytes())
+	out.Write(src[pkg:])
+	out.WriteString(decls)
+
+	return format.Source(out.Bytes())
+}
+
+// usesIdent returns true if the identifier is used in the declarations of the file
+func (m *sdkV2Migration) usesIdent(name string) bool {
+	return usesIdent(m.file, name)
+}
+
+// usesIdent returns true if the package name is used in the declarations of the file
+func usesIdent(file *ast.File, name string) bool {
+	used := false
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			continue
+		}
+
+		ast.Inspect(decl, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok && isIdent(sel.X, name) {
+				used = true
+			}
+			return !used
+		})
+	}
+
+	return used
+}
+
+func localName(name, path string) string {
+	if name != "" {
+		return name
+	}
+
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+// isExpect returns true for the EXPECT() call of a mock
+func isExpect(e ast.Expr) bool {
+	call, ok := e.(*ast.CallExpr)
+	if !ok {
+		return false
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	return ok && sel.Sel.Name == "EXPECT"
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	ident, ok := e.(*ast.Ident)
+	return ok && ident.Name == name
+}
+
+func isSelector(e ast.Expr, x, sel string) bool {
+	s, ok := e.(*ast.SelectorExpr)
+	return ok && isIdent(s.X, x) && s.Sel.Name == sel
+}
+
+var (
+	posType   = reflect.TypeOf(token.NoPos)
+	exprType  = reflect.TypeOf((*ast.Expr)(nil)).Elem()
+	objType   = reflect.TypeOf((*ast.Object)(nil))
+	scopeType = reflect.TypeOf((*ast.Scope)(nil))
+)
+
+// replaceExprs replaces every expression below the node with the expression returned by replace, the expressions are
+// replaced bottom up, so replace is called with the expressions below it already replaced
+func replaceExprs(node ast.Node, replace func(ast.Expr) ast.Expr) {
+	replaceValue(reflect.ValueOf(node), replace)
+}
+
+func replaceValue(v reflect.Value, replace func(ast.Expr) ast.Expr) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Type() == objType || v.Type() == scopeType {
+			return
+		}
+
+		replaceValue(v.Elem(), replace)
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+
+		replaceValue(v.Elem(), replace)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			replaceField(v.Field(i), replace)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			replaceField(v.Index(i), replace)
+		}
+	default:
+	}
+}
+
+func replaceField(v reflect.Value, replace func(ast.Expr) ast.Expr) {
+	replaceValue(v, replace)
+
+	if v.Type() == exprType && !v.IsNil() && v.CanSet() {
+		v.Set(reflect.ValueOf(replace(v.Interface().(ast.Expr))))
+	}
+}
+
+// clearPositions clears the positions of the node and the nodes below it
+func clearPositions(node ast.Node) {
+	clearValue(reflect.ValueOf(node))
+}
+
+func clearValue(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() || v.Type() == objType || v.Type() == scopeType {
+			return
+		}
+
+		clearValue(v.Elem())
+	case reflect.Interface:
+		if !v.IsNil() {
+			clearValue(v.Elem())
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Field(i).Type() == posType {
+				v.Field(i).SetInt(int64(token.NoPos))
+				continue
+			}
+
+			clearValue(v.Field(i))
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			clearValue(v.Index(i))
+		}
+	default:
+	}
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sdkV2Modules are the modules of the services the resources of the golden files are migrated to, they are not
+// dependencies of aws-nuke yet, their checksums are in testdata/sdkv2.sum
+var sdkV2Modules = []string{
+	"github.com/aws/aws-sdk-go-v2/service/accessanalyzer v1.49.1",
+	"github.com/aws/aws-sdk-go-v2/service/acm v1.50.1",
+	"github.com/aws/aws-sdk-go-v2/service/acmpca v1.56.1",
+	"github.com/aws/aws-sdk-go-v2/service/apigatewayv2 v1.35.2",
+	"github.com/aws/aws-sdk-go-v2/service/ecr v1.66.1",
+	"github.com/aws/aws-sdk-go-v2/service/kms v1.61.1",
+	"github.com/aws/aws-sdk-go-v2/service/sns v1.47.2",
+}
+
+// sdkV2ModFile writes a copy of go.mod that requires the modules of the services, so the migrated resources can be
+// type-checked
+func sdkV2ModFile(t *testing.T) string {
+	t.Helper()
+
+	mod, err := os.ReadFile(filepath.Join("..", "..", "go.mod"))
+	require.NoError(t, err)
+
+	sum, err := os.ReadFile(filepath.Join("..", "..", "go.sum"))
+	require.NoError(t, err)
+
+	extra, err := os.ReadFile(filepath.Join("testdata", "sdkv2.sum"))
+	require.NoError(t, err)
+
+	mod = append(mod, "\nrequire (\n\t"+strings.Join(sdkV2Modules, "\n\t")+"\n)\n"...)
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), mod, 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.sum"), append(sum, extra...), 0600))
+
+	return filepath.Join(dir, "go.mod")
+}
+
+func TestMigrateSDKv2_Resources(t *testing.T) {
+	dir := filepath.Join("..", "..", "resources")
+
+	checker, err := newPackageChecker(dir, sdkV2ModFile(t))
+	require.NoError(t, err)
+
+	cases := []struct {
+		name     string
+		findings []string
+	}{
+		{
+			name: "accessanalyzer-analyzer",
+		},
+		{
+			name: "acm-certificate",
+			findings: []string{
+				"acm-certificate.go:39:14: aws.StringSlice is not converted",
+				"acm-certificate.go:42:14: cannot use aws.StringSlice([]string{…}) (value of type []*string) as " +
+					"[]\"github.com/aws/aws-sdk-go-v2/service/acm/types\".KeyAlgorithm value in struct literal, " +
+					"it has to be converted by hand",
+				"acm-certificate.go:42:39: cannot use acmtypes.KeyAlgorithmEcPrime256v1 (constant \"EC_prime256v1\" of " +
+					"string type \"github.com/aws/aws-sdk-go-v2/service/acm/types\".KeyAlgorithm) as string value in " +
+					"array or slice literal, it has to be converted by hand",
+				"acm-certificate.go:43:31: cannot use acmtypes.KeyAlgorithmEcSecp384r1 (constant \"EC_secp384r1\" of " +
+					"string type \"github.com/aws/aws-sdk-go-v2/service/acm/types\".KeyAlgorithm) as string value in " +
+					"array or slice literal, it has to be converted by hand",
+				"acm-certificate.go:44:30: cannot use acmtypes.KeyAlgorithmEcSecp521r1 (constant \"EC_secp521r1\" of " +
+					"string type \"github.com/aws/aws-sdk-go-v2/service/acm/types\".KeyAlgorithm) as string value in " +
+					"array or slice literal, it has to be converted by hand",
+				"acm-certificate.go:45:30: cannot use acmtypes.KeyAlgorithmRsa1024 (constant \"RSA_1024\" of " +
+					"string type \"github.com/aws/aws-sdk-go-v2/service/acm/types\".KeyAlgorithm) as string value in " +
+					"array or slice literal, it has to be converted by hand",
+				"acm-certificate.go:46:26: cannot use acmtypes.KeyAlgorithmRsa2048 (constant \"RSA_2048\" of " +
+					"string type \"github.com/aws/aws-sdk-go-v2/service/acm/types\".KeyAlgorithm) as string value in " +
+					"array or slice literal, it has to be converted by hand",
+				"acm-certificate.go:47:26: cannot use acmtypes.KeyAlgorithmRsa4096 (constant \"RSA_4096\" of " +
+					"string type \"github.com/aws/aws-sdk-go-v2/service/acm/types\".KeyAlgorithm) as string value in " +
+					"array or slice literal, it has to be converted by hand",
+			},
+		},
+		{
+			name: "acm-pca-certificate-authority-state",
+		},
+		{
+			name: "apigatewayv2-apis",
+		},
+		{
+			name: "ecr-repository",
+		},
+		{
+			name: "kms-key",
+			findings: []string{
+				"kms-key.go:14:2: github.com/aws/aws-sdk-go/service/kms/kmsiface is replaced by an interface of the " +
+					"operations that are called, the mocks have to be generated again",
+			},
+		},
+		{
+			name: "sns-topics",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			src, err := os.ReadFile(filepath.Join("testdata", tc.name+".input"))
+			require.NoError(t, err)
+
+			want, err := os.ReadFile(filepath.Join("testdata", tc.name+".golden"))
+			require.NoError(t, err)
+
+			out, findings, err := migrateFile(checker, filepath.Join(dir, tc.name+".go"), src)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), string(out))
+
+			var messages []string
+			for _, f := range findings {
+				f.Pos.Filename = filepath.Base(f.Pos.Filename)
+				messages = append(messages, f.String())
+			}
+
+			assert.Equal(t, tc.findings, messages)
+		})
+	}
+}
+
+func TestMigrateSDKv2_Unconverted(t *testing.T) {
+	src, err := os.ReadFile("testdata/unconverted.input")
+	assert.NoError(t, err)
+
+	out, findings, err := migrateSDKv2("unconverted.go", src)
+	assert.NoError(t, err)
+
+	// Note: the calls that are not converted are left as they are
+	assert.Contains(t, string(out), "r.svc.ListPartsPages(")
+	assert.Contains(t, string(out), `"github.com/aws/aws-sdk-go/aws/awserr"`)
+	assert.Contains(t, string(out), "r.svc.DeleteWidget(ctx, &widgets.DeleteWidgetInput{")
+
+	// Note: the interface of the iface package is replaced by an interface of the operations that are called
+	assert.Contains(t, string(out), "svc  WidgetsAPI\n")
+	assert.Contains(t, string(out), "type WidgetsAPI interface {\n\tDeleteWidget(ctx context.Context, "+
+		"params *widgets.DeleteWidgetInput,\n\t\toptFns ...func(*widgets.Options)) (*widgets.DeleteWidgetOutput, error)\n}")
+
+	var messages []string
+	for _, f := range findings {
+		messages = append(messages, f.String())
+	}
+
+	assert.Equal(t, []string{
+		"unconverted.go:7:2: github.com/aws/aws-sdk-go/aws/request has no equivalent in SDK v2",
+		"unconverted.go:9:2: github.com/aws/aws-sdk-go/service/widgets/widgetsiface is replaced by an interface of " +
+			"the operations that are called, the mocks have to be generated again",
+		"unconverted.go:18:12: the request options of DeleteWidgetWithContext are not converted",
+		"unconverted.go:25:9: the waiters of SDK v2 are types of their own, e.g. NewWidgetDeletedWaiter",
+		"unconverted.go:32:9: the callback of ListPartsPages stops the pagination, it has to be converted by hand",
+		"unconverted.go:47:9: awserr.New has no equivalent in SDK v2, use the error types of the service instead",
+	}, messages)
+}
+
+func TestMigrateSDKv2_NoSDKv1(t *testing.T) {
+	src := []byte("package resources\n\nimport \"context\"\n\nvar _ context.Context\n")
+
+	out, findings, err := migrateSDKv2("v2.go", src)
+	assert.NoError(t, err)
+	assert.Equal(t, src, out)
+	assert.Empty(t, findings)
+}
@@ -0,0 +1,753 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// maxTypeFixes is the number of times the types of a file are fixed, a fix may reveal another mismatch, e.g. the
+// comparison of an enum that was passed to ptr.ToString
+const maxTypeFixes = 5
+
+// packageChecker type-checks the migrated files along with the other files of their package, the imports are loaded
+// from the export data of the go command, so the packages of SDK v2 have to be dependencies of the module
+type packageChecker struct {
+	fset    *token.FileSet
+	dir     string
+	modfile string
+
+	// files are the files of the package by their name, the migrated files replace the original files
+	files map[string]*ast.File
+	// exports maps the import paths to their export data, or to the error of the go command
+	exports map[string]string
+	errors  map[string]string
+
+	importer types.Importer
+}
+
+// newPackageChecker parses the files of the package in the directory, the go command uses the modfile if it is set
+func newPackageChecker(dir, modfile string) (*packageChecker, error) {
+	pkg, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &packageChecker{
+		fset:    token.NewFileSet(),
+		dir:     dir,
+		modfile: modfile,
+		files:   make(map[string]*ast.File),
+		exports: make(map[string]string),
+		errors:  make(map[string]string),
+	}
+
+	for _, name := range pkg.GoFiles {
+		file, err := parser.ParseFile(c.fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+
+		c.files[name] = file
+	}
+
+	c.importer = importer.ForCompiler(c.fset, "gc", func(path string) (io.ReadCloser, error) {
+		export, ok := c.exports[path]
+		if !ok || export == "" {
+			return nil, fmt.Errorf("no export data for %s", path)
+		}
+
+		return os.Open(export)
+	})
+
+	return c, nil
+}
+
+// listPackage is the output of `go list -json`
+type listPackage struct {
+	ImportPath string
+	Export     string
+	Error      *struct {
+		Err string
+	}
+}
+
+// load loads the export data of the imports of the files that are not loaded yet
+func (c *packageChecker) load() error {
+	var paths []string
+	for _, file := range c.files {
+		for _, spec := range file.Imports {
+			path, _ := strconv.Unquote(spec.Path.Value)
+			if _, ok := c.exports[path]; !ok && path != "unsafe" && path != "C" {
+				c.exports[path] = ""
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil
+	}
+
+	args := []string{"list", "-e", "-export", "-deps", "-json=ImportPath,Export,Error"}
+	if c.modfile != "" {
+		args = append(args, "-mod=mod", "-modfile="+c.modfile)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("go", append(args, paths...)...)
+	cmd.Dir = c.dir
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return fmt.Errorf("unable to load the imports: %w: %s", err, stderr.String())
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(out))
+	for {
+		var pkg listPackage
+		if err := decoder.Decode(&pkg); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return err
+		}
+
+		c.exports[pkg.ImportPath] = pkg.Export
+		if pkg.Error != nil {
+			c.errors[pkg.ImportPath] = pkg.Error.Err
+		}
+	}
+
+	return nil
+}
+
+// check type-checks the package with the file in place of the file of the same name, it returns the errors in the
+// file only
+func (c *packageChecker) check(filename string, file *ast.File) (*types.Info, []types.Error, error) {
+	name := filepath.Base(filename)
+	original := c.files[name]
+	c.files[name] = file
+	defer func() {
+		c.files[name] = original
+	}()
+
+	if err := c.load(); err != nil {
+		return nil, nil, err
+	}
+
+	files := make([]*ast.File, 0, len(c.files))
+	for _, f := range c.files {
+		files = append(files, f)
+	}
+
+	var errs []types.Error
+	conf := types.Config{
+		Importer: c.importer,
+		Error: func(err error) {
+			var typeErr types.Error
+			if errors.As(err, &typeErr) && c.fset.File(typeErr.Pos) == c.fset.File(file.Pos()) {
+				errs = append(errs, typeErr)
+			}
+		},
+	}
+
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+
+	_, _ = conf.Check(file.Name.Name, c.fset, files, info)
+
+	return info, errs, nil
+}
+
+// replace replaces the original file with the migrated file, so the files that are migrated after it are checked
+// against it
+func (c *packageChecker) replace(filename string, src []byte) error {
+	file, err := parser.ParseFile(c.fset, filename, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	c.files[filepath.Base(filename)] = file
+
+	return nil
+}
+
+// checkTypes fixes the types of the migrated source that changed in SDK v2 and reports the type errors that are left
+func checkTypes(c *packageChecker, filename string, src []byte) ([]byte, []finding, error) {
+	file, err := parser.ParseFile(c.fset, filename, src, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for i := 0; i < maxTypeFixes; i++ {
+		info, errs, err := c.check(filename, file)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(errs) == 0 || !fixTypes(file, info) {
+			break
+		}
+	}
+
+	// Note: the pointer helpers may not be used anymore, e.g. if ptr.Int64(100) became 100
+	var specs []importSpec
+	for _, spec := range fileImports(file) {
+		if spec.path != ptrPath || usesIdent(file, localName(spec.name, spec.path)) {
+			specs = append(specs, spec)
+		}
+	}
+
+	// Note: the file is printed and parsed again, so the positions of the findings are the positions of the output
+	out, err := printFile(c.fset, file, specs, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file, err = parser.ParseFile(c.fset, filename, out, parser.ParseComments|parser.SkipObjectResolution)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	_, errs, err := c.check(filename, file)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var findings []finding
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		if msg, ok := c.errors[path]; ok {
+			findings = append(findings, finding{
+				Pos:     c.fset.Position(spec.Pos()),
+				Message: fmt.Sprintf("%s can not be imported, add the module with go get: %s", path, msg),
+			})
+		}
+	}
+
+	for _, err := range errs {
+		if strings.HasPrefix(err.Msg, "could not import") {
+			continue
+		}
+
+		findings = append(findings, finding{
+			Pos:     c.fset.Position(err.Pos),
+			Message: err.Msg + ", it has to be converted by hand",
+		})
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].Pos.Offset < findings[j].Pos.Offset
+	})
+
+	return out, findings, nil
+}
+
+// typeFixer fixes the expressions of a file whose types changed in SDK v2
+type typeFixer struct {
+	file    *ast.File
+	info    *types.Info
+	ptrName string
+
+	replacements map[ast.Expr]ast.Expr
+	usesPtr      bool
+}
+
+// fixTypes fixes the expressions whose types changed in SDK v2, which are the enums that are types of their own
+// instead of strings and the fields that are values instead of pointers. It returns true if anything was fixed.
+func fixTypes(file *ast.File, info *types.Info) bool {
+	f := &typeFixer{
+		file:         file,
+		info:         info,
+		ptrName:      "ptr",
+		replacements: make(map[ast.Expr]ast.Expr),
+	}
+
+	for _, spec := range fileImports(file) {
+		if spec.path == ptrPath && spec.name != "" {
+			f.ptrName = spec.name
+		}
+	}
+
+	fixed := false
+	var results []*types.Tuple
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case nil:
+			return true
+		case *ast.FuncDecl:
+			results = append(results, f.signature(n.Name).Results())
+			ast.Inspect(n.Body, func(n ast.Node) bool {
+				return f.inspect(n, results, &fixed)
+			})
+			results = results[:len(results)-1]
+			return false
+		}
+
+		return f.inspect(n, results, &fixed)
+	})
+
+	if len(f.replacements) == 0 && !fixed {
+		return false
+	}
+
+	replaceExprs(file, func(e ast.Expr) ast.Expr {
+		if r, ok := f.replacements[e]; ok {
+			return r
+		}
+		return e
+	})
+
+	if f.usesPtr {
+		addImport(file, ptrPath)
+	}
+
+	return true
+}
+
+func (f *typeFixer) signature(ident *ast.Ident) *types.Signature {
+	if fn, ok := f.info.Defs[ident].(*types.Func); ok {
+		return fn.Type().(*types.Signature)
+	}
+
+	return types.NewSignatureType(nil, nil, nil, nil, nil, false)
+}
+
+func (f *typeFixer) inspect(n ast.Node, results []*types.Tuple, fixed *bool) bool {
+	switch n := n.(type) {
+	case *ast.FuncLit:
+		if sig, ok := f.info.TypeOf(n).(*types.Signature); ok {
+			ast.Inspect(n.Body, func(n ast.Node) bool {
+				return f.inspect(n, append(results, sig.Results()), fixed)
+			})
+		}
+		return false
+	case *ast.CallExpr:
+		if f.fixPtrValue(n) {
+			return false
+		}
+
+		f.fixArgs(n)
+	case *ast.CompositeLit:
+		f.fixCompositeLit(n, fixed)
+	case *ast.AssignStmt:
+		if n.Tok == token.ASSIGN && len(n.Lhs) == len(n.Rhs) {
+			for i := range n.Lhs {
+				f.fix(n.Rhs[i], f.info.TypeOf(n.Lhs[i]), f.fieldOf(n.Lhs[i]), fixed)
+			}
+		}
+	case *ast.ReturnStmt:
+		if len(results) > 0 && results[len(results)-1].Len() == len(n.Results) {
+			for i, result := range n.Results {
+				f.fix(result, results[len(results)-1].At(i).Type(), nil, fixed)
+			}
+		}
+	case *ast.BinaryExpr:
+		f.fixComparison(n)
+	}
+
+	return true
+}
+
+// fixPtrValue replaces the ptr.To* helpers whose argument is not a pointer anymore, e.g. ptr.ToString(key.KeyState)
+// becomes string(key.KeyState) and ptr.ToBool(repository.ImageTagMutability) becomes the field itself
+func (f *typeFixer) fixPtrValue(call *ast.CallExpr) bool {
+	if _, ok := f.replacements[call]; ok {
+		return true
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !isIdent(sel.X, f.ptrName) || !strings.HasPrefix(sel.Sel.Name, "To") || len(call.Args) != 1 {
+		return false
+	}
+
+	sig, ok := f.info.TypeOf(call.Fun).(*types.Signature)
+	if !ok || sig.Params().Len() != 1 {
+		return false
+	}
+
+	arg := call.Args[0]
+	got := f.info.TypeOf(arg)
+	want, ok := sig.Params().At(0).Type().(*types.Pointer)
+	if got == nil || !ok {
+		return false
+	}
+
+	if _, ok := got.Underlying().(*types.Pointer); ok {
+		return false
+	}
+
+	switch {
+	case types.Identical(got, want.Elem()):
+		f.replacements[call] = arg
+	case isEnum(got) && isBasic(want.Elem(), types.String):
+		f.replacements[call] = conversion("string", arg)
+	default:
+		return false
+	}
+
+	return true
+}
+
+func (f *typeFixer) fixArgs(call *ast.CallExpr) {
+	if tv, ok := f.info.Types[call.Fun]; !ok || tv.IsType() {
+		return
+	}
+
+	sig, ok := f.info.TypeOf(call.Fun).(*types.Signature)
+	if !ok || call.Ellipsis.IsValid() {
+		return
+	}
+
+	for i, arg := range call.Args {
+		var want types.Type
+		switch {
+		case sig.Variadic() && i >= sig.Params().Len()-1:
+			want = sig.Params().At(sig.Params().Len() - 1).Type().(*types.Slice).Elem()
+		case i < sig.Params().Len():
+			want = sig.Params().At(i).Type()
+		default:
+			return
+		}
+
+		f.fix(arg, want, nil, nil)
+	}
+}
+
+func (f *typeFixer) fixCompositeLit(lit *ast.CompositeLit, fixed *bool) {
+	typ := f.info.TypeOf(lit)
+	if typ == nil {
+		return
+	}
+	if p, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+
+	switch t := typ.Underlying().(type) {
+	case *types.Struct:
+		for _, elt := range lit.Elts {
+			kv, ok := elt.(*ast.KeyValueExpr)
+			if !ok {
+				continue
+			}
+
+			key, ok := kv.Key.(*ast.Ident)
+			if !ok {
+				continue
+			}
+
+			for i := 0; i < t.NumFields(); i++ {
+				if t.Field(i).Name() == key.Name {
+					f.fix(kv.Value, t.Field(i).Type(), t.Field(i), fixed)
+				}
+			}
+		}
+	case *types.Slice:
+		for _, elt := range lit.Elts {
+			f.fix(elt, t.Elem(), nil, fixed)
+		}
+	case *types.Map:
+		for _, elt := range lit.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				f.fix(kv.Value, t.Elem(), nil, fixed)
+			}
+		}
+	}
+}
+
+// fieldOf returns the field the expression selects
+func (f *typeFixer) fieldOf(e ast.Expr) *types.Var {
+	sel, ok := e.(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+
+	selection, ok := f.info.Selections[sel]
+	if !ok || selection.Kind() != types.FieldVal {
+		return nil
+	}
+
+	return selection.Obj().(*types.Var)
+}
+
+// fix fixes the expression that is used as a value of the type, the field is the field the expression is assigned to
+// if there is one. The fields of the file whose slice or map type changed in SDK v2, e.g. map[string]*string for the
+// tags, are declared with the new type.
+func (f *typeFixer) fix(e ast.Expr, want types.Type, field *types.Var, fixed *bool) {
+	got := f.info.TypeOf(e)
+	if got == nil || want == nil || types.AssignableTo(got, want) {
+		return
+	}
+	if _, ok := f.replacements[e]; ok {
+		return
+	}
+
+	if p, ok := want.(*types.Pointer); ok {
+		helper := ptrHelper(p.Elem())
+
+		switch {
+		case helper == "":
+		case types.Identical(got, p.Elem()):
+			f.replacements[e] = f.ptrCall(helper, e)
+			return
+		case isEnum(got) && isBasic(p.Elem(), types.String):
+			f.replacements[e] = f.ptrCall(helper, conversion("string", e))
+			return
+		case isNumeric(got) && isNumeric(p.Elem()):
+			f.replacements[e] = f.ptrCall(helper, conversion(p.Elem().String(), e))
+			return
+		}
+
+		// Note: the constant of a pointer helper is converted to the integer type of SDK v2, e.g. ptr.Int64(100)
+		// becomes ptr.Int32(100)
+		if call, arg := f.ptrHelperCall(e); call != nil && helper != "" && f.info.Types[arg].Value != nil {
+			f.replacements[e] = f.ptrCall(helper, arg)
+			return
+		}
+	}
+
+	// Note: the enums and most numbers and booleans are values in SDK v2, e.g. ptr.Int64(100) for an int32 field
+	// becomes 100 and ptr.String("DISABLED") for an enum becomes types.CertificateAuthorityStatus("DISABLED")
+	if _, arg := f.ptrHelperCall(e); arg != nil {
+		if _, ok := want.Underlying().(*types.Basic); ok && isEnum(want) {
+			if typ, ok := f.typeExpr(want); ok {
+				f.replacements[e] = &ast.CallExpr{Fun: typ, Args: []ast.Expr{arg}}
+				return
+			}
+		} else if ok && f.info.Types[arg].Value != nil {
+			f.replacements[e] = arg
+			return
+		}
+	}
+
+	if p, ok := got.(*types.Pointer); ok && types.Identical(p.Elem(), want) {
+		if _, arg := f.ptrHelperCall(e); arg != nil {
+			f.replacements[e] = arg
+			return
+		}
+
+		if helper := ptrHelper(want); helper != "" {
+			f.replacements[e] = f.ptrCall("To"+helper, e)
+			return
+		}
+	}
+
+	if field != nil && fixed != nil && isCollection(got) && isCollection(want) {
+		if f.redeclare(field, got) {
+			*fixed = true
+		}
+	}
+}
+
+// fixComparison fixes the comparisons of enums with strings, the string is compared to the string of the enum unless
+// it is the conversion of an enum of the same type
+func (f *typeFixer) fixComparison(binary *ast.BinaryExpr) {
+	if binary.Op != token.EQL && binary.Op != token.NEQ {
+		return
+	}
+
+	x, y := f.info.TypeOf(binary.X), f.info.TypeOf(binary.Y)
+	if x == nil || y == nil || types.Identical(x, y) {
+		return
+	}
+
+	for _, side := range []struct {
+		enum ast.Expr
+		typ  types.Type
+		str  ast.Expr
+	}{
+		{enum: binary.X, typ: x, str: binary.Y},
+		{enum: binary.Y, typ: y, str: binary.X},
+	} {
+		if !isEnum(side.typ) || !isBasic(f.info.TypeOf(side.str), types.String) {
+			continue
+		}
+
+		// Note: the conversion of an enum of the same type is dropped, e.g. string(key.KeyManager) or
+		// ptr.ToString(key.KeyManager)
+		if call, ok := side.str.(*ast.CallExpr); ok && len(call.Args) == 1 &&
+			(isIdent(call.Fun, "string") || isSelector(call.Fun, f.ptrName, "ToString")) &&
+			types.Identical(f.info.TypeOf(call.Args[0]), side.typ) {
+			f.replacements[side.str] = call.Args[0]
+			return
+		}
+
+		if _, ok := f.replacements[side.enum]; !ok {
+			f.replacements[side.enum] = conversion("string", side.enum)
+		}
+		return
+	}
+}
+
+// redeclare declares the field of the file with the type
+func (f *typeFixer) redeclare(field *types.Var, typ types.Type) bool {
+	var decl *ast.Field
+	ast.Inspect(f.file, func(n ast.Node) bool {
+		if s, ok := n.(*ast.Field); ok {
+			for _, name := range s.Names {
+				if name.Pos() == field.Pos() {
+					decl = s
+				}
+			}
+		}
+		return decl == nil
+	})
+
+	if decl == nil || len(decl.Names) != 1 {
+		return false
+	}
+
+	expr, ok := f.typeExpr(typ)
+	if !ok {
+		return false
+	}
+
+	decl.Type = expr
+
+	return true
+}
+
+// typeExpr returns the expression of the type, the packages of the type have to be imported by the file
+func (f *typeFixer) typeExpr(typ types.Type) (ast.Expr, bool) {
+	qualified := true
+	expr, err := parser.ParseExpr(types.TypeString(typ, func(pkg *types.Package) string {
+		for _, spec := range fileImports(f.file) {
+			if spec.path == pkg.Path() {
+				return localName(spec.name, spec.path)
+			}
+		}
+
+		qualified = false
+		return pkg.Name()
+	}))
+	if err != nil || !qualified {
+		return nil, false
+	}
+
+	return expr, true
+}
+
+// ptrHelperCall returns the call of a pointer helper along with its argument, e.g. ptr.Int64(100)
+func (f *typeFixer) ptrHelperCall(e ast.Expr) (*ast.CallExpr, ast.Expr) {
+	call, ok := e.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, nil
+	}
+
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !isIdent(sel.X, f.ptrName) || strings.HasPrefix(sel.Sel.Name, "To") {
+		return nil, nil
+	}
+
+	return call, call.Args[0]
+}
+
+func (f *typeFixer) ptrCall(helper string, arg ast.Expr) ast.Expr {
+	f.usesPtr = true
+
+	return &ast.CallExpr{
+		Fun:  &ast.SelectorExpr{X: ast.NewIdent(f.ptrName), Sel: ast.NewIdent(helper)},
+		Args: []ast.Expr{arg},
+	}
+}
+
+// ptrHelper returns the name of the helper of the ptr package for the type, e.g. Int32 for int32
+func ptrHelper(typ types.Type) string {
+	if named, ok := typ.(*types.Named); ok {
+		switch named.String() {
+		case "time.Time":
+			return "Time"
+		case "time.Duration":
+			return "Duration"
+		}
+
+		return ""
+	}
+
+	basic, ok := typ.(*types.Basic)
+	if !ok {
+		return ""
+	}
+
+	switch basic.Kind() {
+	case types.String, types.Bool, types.Int, types.Int8, types.Int16, types.Int32, types.Int64, types.Float32,
+		types.Float64:
+		name := basic.Name()
+		return strings.ToUpper(name[:1]) + name[1:]
+	case types.Uint, types.Uint8, types.Uint16, types.Uint32, types.Uint64:
+		return "UInt" + strings.TrimPrefix(basic.Name(), "uint")
+	}
+
+	return ""
+}
+
+// isEnum returns true for the enums of SDK v2, which are string types of the types packages of the services
+func isEnum(typ types.Type) bool {
+	named, ok := typ.(*types.Named)
+	if !ok || named.Obj().Pkg() == nil || !strings.HasPrefix(named.Obj().Pkg().Path(), sdkV2ServicePath) {
+		return false
+	}
+
+	return isBasic(named.Underlying(), types.String)
+}
+
+func isBasic(typ types.Type, kind types.BasicKind) bool {
+	basic, ok := typ.(*types.Basic)
+	return ok && basic.Kind() == kind
+}
+
+func isNumeric(typ types.Type) bool {
+	basic, ok := typ.(*types.Basic)
+	return ok && basic.Info()&types.IsNumeric != 0
+}
+
+func isCollection(typ types.Type) bool {
+	switch typ.(type) {
+	case *types.Slice, *types.Map:
+		return true
+	}
+
+	return false
+}
+
+func conversion(typ string, e ast.Expr) ast.Expr {
+	return &ast.CallExpr{Fun: ast.NewIdent(typ), Args: []ast.Expr{e}}
+}
+
+// addImport adds the import to the file if it is not imported yet, the imports are grouped when the file is printed
+func addImport(file *ast.File, path string) {
+	for _, spec := range file.Imports {
+		if spec.Path.Value == strconv.Quote(path) {
+			return
+		}
+	}
+
+	spec := &ast.ImportSpec{Path: &ast.BasicLit{Kind: token.STRING, Value: strconv.Quote(path)}}
+	file.Imports = append(file.Imports, spec)
+
+	for _, decl := range file.Decls {
+		if gen, ok := decl.(*ast.GenDecl); ok && gen.Tok == token.IMPORT {
+			gen.Specs = append(gen.Specs, spec)
+			return
+		}
+	}
+}